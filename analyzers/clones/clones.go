@@ -0,0 +1,244 @@
+// Package clones defines an Analyzer that reports groups of copy-pasted
+// functions.
+//
+// Each function body is flattened into a normalized token stream: the
+// pre-order sequence of its syntax node kinds and operators, with every
+// identifier replaced by one placeholder and every literal by a placeholder
+// for its kind. Renaming variables or changing strings therefore leaves the
+// stream unchanged, which is exactly what copy-paste-and-tweak produces.
+// Functions whose streams are similar enough are joined into clone groups.
+package clones
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+)
+
+const Doc = `report groups of duplicated functions
+
+The clones analyzer normalizes each function body, abstracting away
+identifiers and literal values, and compares the resulting token streams.
+Similarity is the Dice coefficient of the longest common subsequence,
+2*LCS/(len(a)+len(b)), so 1.0 means the functions differ only in names and
+literals. Functions of at least -min-tokens tokens whose similarity reaches
+-threshold are joined into a clone group, reported once at its first member
+with the other members as related locations.
+
+Comparison is within a single package.`
+
+// Analyzer reports clone groups. Its result is the []*Group found in the
+// package.
+var Analyzer = &analysis.Analyzer{
+	Name:       "clones",
+	Doc:        Doc,
+	URL:        "https://github.com/bclements/antipatterns/tree/main/analyzers/clones",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	Run:        run,
+	ResultType: reflect.TypeOf([]*Group(nil)),
+}
+
+var (
+	threshold float64
+	minTokens int
+)
+
+func init() {
+	Analyzer.Flags.Float64Var(&threshold, "threshold", 0.9, "minimum similarity, between 0 and 1, for two functions to be clones")
+	Analyzer.Flags.IntVar(&minTokens, "min-tokens", 12, "ignore functions whose normalized body has fewer tokens than this")
+}
+
+// A Group is a set of functions that are clones of one another.
+type Group struct {
+	Funcs []*Func
+	// Similarity is the lowest pairwise similarity between linked members.
+	Similarity float64
+}
+
+// Func is one member of a clone group.
+type Func struct {
+	Name   string // e.g. "(*ReportGenerator).GenerateSalesReport"
+	Pos    token.Pos
+	Tokens int
+}
+
+type candidate struct {
+	fn     *Func
+	tokens []int
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	vocab := make(map[string]int)
+	var cands []*candidate
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fd := n.(*ast.FuncDecl)
+		if fd.Body == nil {
+			return
+		}
+		toks := Normalize(fd.Body, vocab)
+		if len(toks) < minTokens {
+			return
+		}
+		cands = append(cands, &candidate{
+			fn:     &Func{Name: astutil.FuncName(fd), Pos: fd.Name.Pos(), Tokens: len(toks)},
+			tokens: toks,
+		})
+	})
+
+	// Link every pair of sufficiently similar functions.
+	parent := make([]int, len(cands))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	minSim := make(map[int]float64) // root -> lowest linking similarity
+	for i := range cands {
+		for j := i + 1; j < len(cands); j++ {
+			a, b := cands[i].tokens, cands[j].tokens
+			// Dice can be no higher than 2*min/(len(a)+len(b)); skip early.
+			if 2*float64(min(len(a), len(b)))/float64(len(a)+len(b)) < threshold {
+				continue
+			}
+			sim := Similarity(a, b)
+			if sim < threshold {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			low := sim
+			for _, r := range []int{ri, rj} {
+				if s, ok := minSim[r]; ok {
+					low = min(low, s)
+				}
+			}
+			if ri != rj {
+				parent[rj] = ri
+				delete(minSim, rj)
+			}
+			minSim[ri] = low
+		}
+	}
+
+	byRoot := make(map[int]*Group)
+	var groups []*Group
+	for i, c := range cands {
+		r := find(i)
+		sim, linked := minSim[r]
+		if !linked {
+			continue
+		}
+		g, ok := byRoot[r]
+		if !ok {
+			g = &Group{Similarity: sim}
+			byRoot[r] = g
+			groups = append(groups, g)
+		}
+		g.Funcs = append(g.Funcs, c.fn)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Funcs[0].Pos < groups[j].Funcs[0].Pos })
+
+	for _, g := range groups {
+		names := make([]string, len(g.Funcs))
+		var related []analysis.RelatedInformation
+		for i, fn := range g.Funcs {
+			names[i] = fn.Name
+			if i > 0 {
+				related = append(related, analysis.RelatedInformation{
+					Pos:     fn.Pos,
+					Message: fmt.Sprintf("clone of %s", g.Funcs[0].Name),
+				})
+			}
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     g.Funcs[0].Pos,
+			Message: fmt.Sprintf("%d functions are clones (%.0f%% similar): %s; extract the shared logic", len(g.Funcs), 100*g.Similarity, strings.Join(names, ", ")),
+			Related: related,
+		})
+	}
+	return groups, nil
+}
+
+// Normalize returns the normalized token stream of a syntax tree, interning
+// token kinds through vocab so streams from the same vocab can be compared.
+func Normalize(n ast.Node, vocab map[string]int) []int {
+	var toks []int
+	emit := func(kind string) {
+		id, ok := vocab[kind]
+		if !ok {
+			id = len(vocab)
+			vocab[kind] = id
+		}
+		toks = append(toks, id)
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case nil:
+			return false
+		case *ast.Ident:
+			if n.Name == "_" {
+				emit("_")
+			} else {
+				emit("ident")
+			}
+		case *ast.BasicLit:
+			emit("lit:" + n.Kind.String())
+		case *ast.BinaryExpr:
+			emit("binary:" + n.Op.String())
+		case *ast.UnaryExpr:
+			emit("unary:" + n.Op.String())
+		case *ast.AssignStmt:
+			emit("assign:" + n.Tok.String())
+		case *ast.IncDecStmt:
+			emit("incdec:" + n.Tok.String())
+		case *ast.BranchStmt:
+			emit("branch:" + n.Tok.String())
+		default:
+			emit(reflect.TypeOf(n).Elem().Name())
+		}
+		return true
+	})
+	return toks
+}
+
+// Similarity returns the Dice coefficient of the longest common subsequence
+// of a and b: 1 for identical streams, 0 for streams with nothing in common.
+func Similarity(a, b []int) float64 {
+	if len(a)+len(b) == 0 {
+		return 1
+	}
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return 2 * float64(prev[len(b)]) / float64(len(a)+len(b))
+}
@@ -0,0 +1,32 @@
+package clones_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/clones"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), clones.Analyzer, "a")
+	groups := results[0].Result.([]*clones.Group)
+	if len(groups) != 1 {
+		t.Fatalf("%d clone groups, want 1", len(groups))
+	}
+	var names []string
+	for _, fn := range groups[0].Funcs {
+		names = append(names, fn.Name)
+	}
+	if want := []string{"SalesReport", "ExpenseReport", "InventoryReport"}; !slices.Equal(names, want) || groups[0].Similarity != 1 {
+		t.Errorf("group %v, %.2f similar; want %v, 1.00", names, groups[0].Similarity, want)
+	}
+}
+
+// TestCopyPaste runs the analyzer on the Copy-Paste Programming example.
+func TestCopyPaste(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), clones.Analyzer, "copypaste")
+	if groups := results[0].Result.([]*clones.Group); len(groups) != 5 {
+		t.Errorf("%d clone groups, want 5", len(groups))
+	}
+}
@@ -0,0 +1,68 @@
+package a
+
+import "fmt"
+
+type Report struct {
+	Title string
+	Rows  []float64
+}
+
+// The three report functions differ only in names and literals.
+
+func SalesReport(rows []float64) Report { // want `3 functions are clones \(100% similar\): SalesReport, ExpenseReport, InventoryReport; extract the shared logic`
+	total := 0.0
+	for _, r := range rows {
+		if r > 0 {
+			total += r
+		}
+	}
+	return Report{Title: fmt.Sprintf("Sales: %.2f", total), Rows: rows}
+}
+
+func ExpenseReport(costs []float64) Report {
+	sum := 0.0
+	for _, c := range costs {
+		if c > 0 {
+			sum += c
+		}
+	}
+	return Report{Title: fmt.Sprintf("Expenses: %.2f", sum), Rows: costs}
+}
+
+func InventoryReport(counts []float64) Report {
+	n := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			n += c
+		}
+	}
+	return Report{Title: fmt.Sprintf("Inventory: %.2f", n), Rows: counts}
+}
+
+// Average has the same loop but does something else with it.
+func Average(rows []float64) float64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, r := range rows {
+		total += r
+	}
+	return total / float64(len(rows))
+}
+
+// Max is unrelated to the reports.
+func Max(rows []float64) (float64, error) {
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no rows")
+	}
+	best := rows[0]
+	for _, r := range rows[1:] {
+		best = max(best, r)
+	}
+	return best, nil
+}
+
+// Short functions are too small to count as clones, however alike.
+func Double(x int) int { return x * 2 }
+func Triple(x int) int { return x * 3 }
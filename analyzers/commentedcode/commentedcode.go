@@ -0,0 +1,295 @@
+// Package commentedcode defines an Analyzer that reports commented-out Go
+// code, with fixes that delete it.
+package commentedcode
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const Doc = `report commented-out code, with fixes that delete it
+
+The commentedcode analyzer tries to parse each run of // comment lines,
+and each /* */ comment, as Go declarations or statements; inside a struct
+type it also tries struct fields. The longest runs that parse are reported
+with their line count. Prose that happens to parse, such as a lone word,
+is not code: every statement must do something, such as call a function,
+assign or return, and a field's type must be a composite type or a type in
+scope. Preformatted blocks in doc comments are examples, not dead code, and
+are skipped.
+
+Each diagnostic carries a suggested fix that deletes the lines; version
+control still has them.`
+
+// Analyzer reports commented-out code.
+var Analyzer = &analysis.Analyzer{
+	Name: "commentedcode",
+	Doc:  Doc,
+	URL:  "https://github.com/bclements/antipatterns/tree/main/analyzers/commentedcode",
+	Run:  run,
+}
+
+// A line is one line of comment text.
+type line struct {
+	text   string
+	c      *ast.Comment
+	alone  bool // no code before the comment on its line
+	inDoc  bool // part of a declaration's doc comment
+	number int
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, f := range pass.Files {
+		docs := make(map[*ast.CommentGroup]bool)
+		var structs []*ast.FieldList
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if n.Doc != nil {
+					docs[n.Doc] = true
+				}
+			case *ast.GenDecl:
+				if n.Doc != nil {
+					docs[n.Doc] = true
+				}
+			case *ast.StructType:
+				structs = append(structs, n.Fields)
+			}
+			return true
+		})
+		tf := pass.Fset.File(f.Pos())
+		content, err := pass.ReadFile(tf.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cg := range f.Comments {
+			var run []line
+			flush := func() {
+				checkLines(pass, tf, structs, run)
+				run = nil
+			}
+			for _, c := range cg.List {
+				if strings.HasPrefix(c.Text, "/*") {
+					flush()
+					checkBlock(pass, tf, structs, c)
+					continue
+				}
+				n := tf.Line(c.Slash)
+				before := content[tf.Offset(tf.LineStart(n)):tf.Offset(c.Slash)]
+				alone := len(bytes.TrimLeft(before, " \t")) == 0
+				if !alone {
+					// A comment trailing code stands on its own.
+					flush()
+					checkLines(pass, tf, structs, []line{{text: c.Text[2:], c: c, number: n}})
+					continue
+				}
+				run = append(run, line{text: c.Text[2:], c: c, alone: true, inDoc: docs[cg], number: n})
+			}
+			flush()
+		}
+	}
+	return nil, nil
+}
+
+// checkLines reports the longest runs of lines that parse as code, trying
+// each starting line in turn.
+func checkLines(pass *analysis.Pass, tf *token.File, structs []*ast.FieldList, lines []line) {
+	for i := 0; i < len(lines); {
+		if isDirective(lines[i].text) || (lines[i].inDoc && isPreformatted(lines[i].text)) || strings.TrimSpace(lines[i].text) == "" {
+			i++
+			continue
+		}
+		found := false
+		for j := len(lines); j > i; j-- {
+			texts := make([]string, j-i)
+			for k := range texts {
+				texts[k] = lines[i+k].text
+			}
+			what := parseCode(pass, structs, lines[i].c.Pos(), strings.Join(texts, "\n"))
+			if what == "" {
+				continue
+			}
+			// Trim trailing blank lines from the reported run.
+			for j > i+1 && strings.TrimSpace(lines[j-1].text) == "" {
+				j--
+			}
+			first, last := lines[i], lines[j-1]
+			edit := analysis.TextEdit{Pos: first.c.Pos(), End: last.c.End()}
+			if first.alone {
+				edit.Pos = tf.LineStart(first.number)
+				if last.number < tf.LineCount() {
+					edit.End = tf.LineStart(last.number + 1)
+				}
+			}
+			report(pass, first.c.Pos(), last.c.End(), j-i, what, edit)
+			i, found = j, true
+			break
+		}
+		if !found {
+			i++
+		}
+	}
+}
+
+// checkBlock reports a /* */ comment whose whole content parses as code.
+func checkBlock(pass *analysis.Pass, tf *token.File, structs []*ast.FieldList, c *ast.Comment) {
+	body := strings.TrimSuffix(strings.TrimPrefix(c.Text, "/*"), "*/")
+	what := parseCode(pass, structs, c.Pos(), body)
+	if what == "" {
+		return
+	}
+	lines := tf.Line(c.End()) - tf.Line(c.Pos()) + 1
+	report(pass, c.Pos(), c.End(), lines, what, analysis.TextEdit{Pos: c.Pos(), End: c.End()})
+}
+
+func report(pass *analysis.Pass, pos, end token.Pos, lines int, what string, edit analysis.TextEdit) {
+	noun := "line"
+	if lines != 1 {
+		noun = "lines"
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:     pos,
+		End:     end,
+		Message: fmt.Sprintf("%d %s of commented-out code (%s); delete it, version control remembers", lines, noun, what),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Delete the commented-out code",
+			TextEdits: []analysis.TextEdit{edit},
+		}},
+	})
+}
+
+// parseCode reports whether src is Go code, returning a short description
+// of it such as "func oldImplementation" or "if statement", or "" if src
+// is not code. pos is where the comment sits, which decides whether struct
+// fields are plausible.
+func parseCode(pass *analysis.Pass, structs []*ast.FieldList, pos token.Pos, src string) string {
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+	fset := token.NewFileSet()
+
+	if f, err := parser.ParseFile(fset, "", "package p\n"+src, parser.SkipObjectResolution); err == nil && len(f.Decls) > 0 {
+		switch d := f.Decls[0].(type) {
+		case *ast.FuncDecl:
+			return "func " + d.Name.Name
+		case *ast.GenDecl:
+			return d.Tok.String() + " declaration"
+		}
+	}
+
+	if f, err := parser.ParseFile(fset, "", "package p\nfunc _() {\n"+src+"\n}", parser.SkipObjectResolution); err == nil {
+		body := f.Decls[0].(*ast.FuncDecl).Body.List
+		switch {
+		case len(body) == 0 || !allMeaningful(body):
+		case len(body) == 1:
+			return describe(body[0])
+		default:
+			return fmt.Sprintf("%d statements", len(body))
+		}
+	}
+
+	for _, fl := range structs {
+		if fl.Opening >= pos || pos >= fl.Closing {
+			continue
+		}
+		f, err := parser.ParseFile(fset, "", "package p\ntype _ struct {\n"+src+"\n}", parser.SkipObjectResolution)
+		if err != nil {
+			break
+		}
+		fields := f.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List
+		if len(fields) == 0 {
+			break
+		}
+		for _, field := range fields {
+			if !plausibleType(pass, field.Type) {
+				return ""
+			}
+		}
+		return "struct field"
+	}
+	return ""
+}
+
+// allMeaningful reports whether every statement does something. A bare
+// expression such as "TODO" or "Nobody knows" parses, but is prose.
+func allMeaningful(stmts []ast.Stmt) bool {
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *ast.ExprStmt:
+			switch x := s.X.(type) {
+			case *ast.CallExpr:
+			case *ast.UnaryExpr:
+				if x.Op != token.ARROW {
+					return false
+				}
+			default:
+				return false
+			}
+		case *ast.LabeledStmt, *ast.EmptyStmt, *ast.BadStmt:
+			return false
+		}
+	}
+	return true
+}
+
+// plausibleType reports whether a field type looks like real code: a
+// composite type, or a name that is a type in scope.
+func plausibleType(pass *analysis.Pass, e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	if !ok {
+		return true
+	}
+	_, obj := pass.Pkg.Scope().LookupParent(id.Name, token.NoPos)
+	_, isType := obj.(*types.TypeName)
+	return isType
+}
+
+func describe(s ast.Stmt) string {
+	switch s := s.(type) {
+	case *ast.AssignStmt:
+		return "assignment"
+	case *ast.ExprStmt:
+		if call, ok := s.X.(*ast.CallExpr); ok {
+			return "call to " + types.ExprString(call.Fun)
+		}
+	case *ast.DeclStmt:
+		return "declaration"
+	case *ast.ReturnStmt:
+		return "return statement"
+	case *ast.IfStmt:
+		return "if statement"
+	case *ast.ForStmt, *ast.RangeStmt:
+		return "loop"
+	case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+		return "switch statement"
+	case *ast.DeferStmt:
+		return "defer statement"
+	case *ast.GoStmt:
+		return "go statement"
+	}
+	return "statements"
+}
+
+// isDirective reports whether a // comment is a directive such as
+// //go:generate or //nolint rather than text.
+func isDirective(text string) bool {
+	if strings.HasPrefix(text, "line ") || strings.HasPrefix(text, "export ") {
+		return true
+	}
+	i := strings.IndexByte(text, ':')
+	return i > 0 && !strings.ContainsAny(text[:i], " \t") && strings.ToLower(text[:i]) == text[:i] && len(text) > i+1 && text[i+1] != ' '
+}
+
+// isPreformatted reports whether a doc comment line is part of an indented
+// example block.
+func isPreformatted(text string) bool {
+	return strings.HasPrefix(text, "\t") || strings.HasPrefix(text, "   ")
+}
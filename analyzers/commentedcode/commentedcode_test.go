@@ -0,0 +1,12 @@
+package commentedcode_test
+
+import (
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/commentedcode"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), commentedcode.Analyzer, "a")
+}
@@ -0,0 +1,34 @@
+package a
+
+import "fmt"
+
+type User struct {
+	Name string
+	// Email string // want `1 line of commented-out code \(struct field\)`
+	Age int
+	// Nobody remembers what this was for.
+}
+
+// func oldGreeting(u User) string { // want `3 lines of commented-out code \(func oldGreeting\)`
+//	return "Hi " + u.Name
+// }
+
+// Greeting returns the greeting for u. For example:
+//
+//	fmt.Println(Greeting(u))
+func Greeting(u User) string {
+	// total := 0 // want `2 lines of commented-out code \(2 statements\)`
+	// total += u.Age
+	s := "Hello " + u.Name // fmt.Println(s) // want `1 line of commented-out code \(call to fmt.Println\)`
+
+	/* s += "!" */ // want `1 line of commented-out code \(assignment\)`
+
+	// TODO
+	// Check the name first.
+	//nolint:unused
+	return s
+}
+
+func Print(u User) {
+	fmt.Println(Greeting(u))
+}
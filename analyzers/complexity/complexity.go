@@ -0,0 +1,304 @@
+// Package complexity defines an Analyzer that measures the cyclomatic
+// complexity, cognitive complexity and maximum if-nesting depth of every
+// function and reports the ones that exceed configurable thresholds.
+//
+// These are the Spaghetti Code detection metrics from
+// ANTI_PATTERNS_SUMMARY.md: deeply nested conditionals and tangled control
+// flow show up as high numbers on all three.
+package complexity
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+)
+
+const Doc = `report functions with high cyclomatic complexity, cognitive complexity or if nesting
+
+Cyclomatic complexity is McCabe's count of independent paths: one plus the
+number of if, for, case and select clauses and && and || operators.
+
+Cognitive complexity follows the SonarSource definition: each break in
+linear flow costs one, plus one for every level of nesting it sits in, so
+nested conditionals cost far more than sequential ones.
+
+If nesting is the deepest chain of if statements inside one another; an
+else-if continues its chain rather than nesting.
+
+A function is reported for every metric that exceeds its threshold.`
+
+// Analyzer reports overly complex functions. Its result is a []*Func
+// describing every function in the package, whether reported or not.
+var Analyzer = &analysis.Analyzer{
+	Name:       "complexity",
+	Doc:        Doc,
+	URL:        "https://github.com/bclements/antipatterns/tree/main/analyzers/complexity",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	Run:        run,
+	ResultType: reflect.TypeOf([]*Func(nil)),
+}
+
+// Thresholds; a function is reported when a metric is strictly greater.
+var (
+	maxCyclomatic int
+	maxCognitive  int
+	maxNesting    int
+)
+
+func init() {
+	Analyzer.Flags.IntVar(&maxCyclomatic, "cyclomatic", 15, "report functions whose cyclomatic complexity exceeds this")
+	Analyzer.Flags.IntVar(&maxCognitive, "cognitive", 15, "report functions whose cognitive complexity exceeds this")
+	Analyzer.Flags.IntVar(&maxNesting, "nesting", 5, "report functions whose if statements nest deeper than this")
+}
+
+// Categories of the diagnostics, one per metric.
+const (
+	CategoryCyclomatic = "cyclomatic"
+	CategoryCognitive  = "cognitive"
+	CategoryNesting    = "nesting"
+)
+
+// Func holds the metrics of one function or method.
+type Func struct {
+	Name       string // e.g. "ProcessOrder" or "(*OrderProcessor).Process"
+	Pos        token.Pos
+	Cyclomatic int
+	Cognitive  int
+	MaxNesting int
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var funcs []*Func
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fd := n.(*ast.FuncDecl)
+		if fd.Body == nil {
+			return
+		}
+		fn, _ := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		f := &Func{
+			Name:       astutil.FuncName(fd),
+			Pos:        fd.Name.Pos(),
+			Cyclomatic: Cyclomatic(fd.Body),
+			Cognitive:  Cognitive(pass.TypesInfo, fn, fd.Body),
+			MaxNesting: MaxIfNesting(fd.Body),
+		}
+		funcs = append(funcs, f)
+
+		report := func(category, metric string, value, limit int) {
+			if value <= limit {
+				return
+			}
+			pass.Report(analysis.Diagnostic{
+				Pos:      fd.Name.Pos(),
+				End:      fd.Name.End(),
+				Category: category,
+				Message:  fmt.Sprintf("%s has %s %d (> %d)", f.Name, metric, value, limit),
+			})
+		}
+		report(CategoryCyclomatic, "cyclomatic complexity", f.Cyclomatic, maxCyclomatic)
+		report(CategoryCognitive, "cognitive complexity", f.Cognitive, maxCognitive)
+		report(CategoryNesting, "if nesting depth", f.MaxNesting, maxNesting)
+	})
+	return funcs, nil
+}
+
+// Cyclomatic returns the McCabe cyclomatic complexity of a function body.
+func Cyclomatic(body *ast.BlockStmt) int {
+	n := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			n++
+		case *ast.CaseClause:
+			if node.List != nil { // default
+				n++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil { // default
+				n++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+// MaxIfNesting returns the depth of the deepest chain of nested if
+// statements in body.
+func MaxIfNesting(body *ast.BlockStmt) int {
+	var walk func(n ast.Node, depth int) int
+	walk = func(n ast.Node, depth int) int {
+		deepest := depth
+		ast.Inspect(n, func(node ast.Node) bool {
+			ifs, ok := node.(*ast.IfStmt)
+			if !ok || node == n {
+				return true
+			}
+			deepest = max(deepest, walkIf(ifs, depth+1, walk))
+			return false
+		})
+		return deepest
+	}
+	return walk(body, 0)
+}
+
+// walkIf measures an if statement at the given depth. Its else-if chain
+// stays at the same depth.
+func walkIf(ifs *ast.IfStmt, depth int, walk func(ast.Node, int) int) int {
+	deepest := max(depth, walk(ifs.Body, depth))
+	switch e := ifs.Else.(type) {
+	case *ast.IfStmt:
+		deepest = max(deepest, walkIf(e, depth, walk))
+	case *ast.BlockStmt:
+		deepest = max(deepest, walk(e, depth))
+	}
+	return deepest
+}
+
+// Cognitive returns the cognitive complexity of a function body. fn, if
+// non-nil, is the function itself, so that recursive calls can be counted.
+func Cognitive(info *types.Info, fn *types.Func, body *ast.BlockStmt) int {
+	c := &cognitive{info: info, fn: fn}
+	c.block(body.List, 0)
+	return c.total
+}
+
+type cognitive struct {
+	info  *types.Info
+	fn    *types.Func
+	total int
+}
+
+func (c *cognitive) block(stmts []ast.Stmt, nesting int) {
+	for _, s := range stmts {
+		c.stmt(s, nesting)
+	}
+}
+
+func (c *cognitive) stmt(s ast.Stmt, nesting int) {
+	switch s := s.(type) {
+	case *ast.IfStmt:
+		c.total += 1 + nesting
+		c.ifChain(s, nesting)
+	case *ast.ForStmt:
+		c.total += 1 + nesting
+		c.expr(s.Cond, nesting)
+		c.block(s.Body.List, nesting+1)
+	case *ast.RangeStmt:
+		c.total += 1 + nesting
+		c.expr(s.X, nesting)
+		c.block(s.Body.List, nesting+1)
+	case *ast.SwitchStmt:
+		c.total += 1 + nesting
+		c.expr(s.Tag, nesting)
+		c.clauses(s.Body, nesting+1)
+	case *ast.TypeSwitchStmt:
+		c.total += 1 + nesting
+		c.clauses(s.Body, nesting+1)
+	case *ast.SelectStmt:
+		c.total += 1 + nesting
+		c.clauses(s.Body, nesting+1)
+	case *ast.BranchStmt:
+		if s.Label != nil {
+			c.total++
+		}
+	case *ast.LabeledStmt:
+		c.stmt(s.Stmt, nesting)
+	case *ast.BlockStmt:
+		c.block(s.List, nesting)
+	default:
+		c.expr(s, nesting)
+	}
+}
+
+// ifChain scores the condition, body and else branches of an if statement
+// whose own increment has already been counted.
+func (c *cognitive) ifChain(s *ast.IfStmt, nesting int) {
+	if s.Init != nil {
+		c.stmt(s.Init, nesting)
+	}
+	c.expr(s.Cond, nesting)
+	c.block(s.Body.List, nesting+1)
+	switch e := s.Else.(type) {
+	case *ast.IfStmt:
+		c.total++ // else if: no nesting increment
+		c.ifChain(e, nesting)
+	case *ast.BlockStmt:
+		c.total++
+		c.block(e.List, nesting+1)
+	}
+}
+
+func (c *cognitive) clauses(body *ast.BlockStmt, nesting int) {
+	for _, cl := range body.List {
+		switch cl := cl.(type) {
+		case *ast.CaseClause:
+			c.block(cl.Body, nesting)
+		case *ast.CommClause:
+			c.block(cl.Body, nesting)
+		}
+	}
+}
+
+// expr scores boolean operator sequences, recursion and function literals
+// found in a node that is not itself a control-flow statement.
+func (c *cognitive) expr(n ast.Node, nesting int) {
+	if n == nil {
+		return
+	}
+	ast.Inspect(n, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			c.block(node.Body.List, nesting+1)
+			return false
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				c.total += logicalSequences(node)
+				return false
+			}
+		case *ast.CallExpr:
+			if c.fn != nil && c.info != nil && typeutil.Callee(c.info, node) == c.fn {
+				c.total++
+			}
+		}
+		return true
+	})
+}
+
+// logicalSequences counts the runs of like boolean operators in a chain
+// such as a && b && c || d, which has two.
+func logicalSequences(e *ast.BinaryExpr) int {
+	var ops []token.Token
+	var flatten func(ast.Expr)
+	flatten = func(x ast.Expr) {
+		x = ast.Unparen(x)
+		if b, ok := x.(*ast.BinaryExpr); ok && (b.Op == token.LAND || b.Op == token.LOR) {
+			flatten(b.X)
+			ops = append(ops, b.Op)
+			flatten(b.Y)
+		}
+	}
+	flatten(e)
+	n := 0
+	for i, op := range ops {
+		if i == 0 || op != ops[i-1] {
+			n++
+		}
+	}
+	return n
+}
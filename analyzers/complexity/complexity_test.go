@@ -0,0 +1,96 @@
+package complexity_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/complexity"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), complexity.Analyzer, "spaghetti")
+	checkResults(t, results, map[string]complexity.Func{
+		"ProcessOrder": {Cyclomatic: 37, Cognitive: 128, MaxNesting: 6},
+		"ValidateUser": {Cyclomatic: 15, Cognitive: 73, MaxNesting: 9},
+		"Total":        {Cyclomatic: 3, Cognitive: 2, MaxNesting: 1},
+		"Status":       {Cyclomatic: 4, Cognitive: 1, MaxNesting: 0},
+	})
+}
+
+// TestUntangled checks that ProcessOrder and ValidateUser as
+// goodexamples/spaghetticode refactored them, and everything they call,
+// are reported nowhere.
+func TestUntangled(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), complexity.Analyzer, "untangled")
+	checkResults(t, results, map[string]complexity.Func{
+		"ProcessOrder":              {Cyclomatic: 1, Cognitive: 0, MaxNesting: 0},
+		"NewOrderProcessor":         {Cyclomatic: 1, Cognitive: 0, MaxNesting: 0},
+		"(*OrderProcessor).Process": {Cyclomatic: 3, Cognitive: 2, MaxNesting: 1},
+		"ValidateUser":              {Cyclomatic: 8, Cognitive: 2, MaxNesting: 1},
+		"eligibility":               {Cyclomatic: 9, Cognitive: 12, MaxNesting: 1},
+		"(*Rules).Quote":            {Cyclomatic: 5, Cognitive: 4, MaxNesting: 1},
+		"(*Rules).ProcessOrder":     {Cyclomatic: 2, Cognitive: 1, MaxNesting: 1},
+		"first":                     {Cyclomatic: 3, Cognitive: 3, MaxNesting: 1},
+		"Condition.holds":           {Cyclomatic: 8, Cognitive: 1, MaxNesting: 0},
+		"oneOf":                     {Cyclomatic: 2, Cognitive: 1, MaxNesting: 0},
+		"(*Bound).contains":         {Cyclomatic: 5, Cognitive: 4, MaxNesting: 1},
+		"subtotal":                  {Cyclomatic: 2, Cognitive: 1, MaxNesting: 0},
+	})
+}
+
+// checkResults compares the analyzer's result for the one package analyzed
+// with want, keyed by function name.
+func checkResults(t *testing.T, results []*analysistest.Result, want map[string]complexity.Func) {
+	t.Helper()
+	for _, fn := range results[0].Result.([]*complexity.Func) {
+		w, ok := want[fn.Name]
+		if !ok {
+			t.Errorf("unexpected function %s", fn.Name)
+			continue
+		}
+		delete(want, fn.Name)
+		if fn.Cyclomatic != w.Cyclomatic || fn.Cognitive != w.Cognitive || fn.MaxNesting != w.MaxNesting {
+			t.Errorf("%s: cyclomatic %d, cognitive %d, nesting %d; want %d, %d, %d",
+				fn.Name, fn.Cyclomatic, fn.Cognitive, fn.MaxNesting, w.Cyclomatic, w.Cognitive, w.MaxNesting)
+		}
+	}
+	for name := range want {
+		t.Errorf("no result for %s", name)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	for _, test := range []struct {
+		name, body                        string
+		cyclomatic, cognitive, maxNesting int
+	}{
+		{"empty", ``, 1, 0, 0},
+		{"else if chain", `if a { } else if b { } else if c { } else { }`, 4, 4, 1},
+		{"nested ifs", `if a { if b { if c { } } }`, 4, 6, 3},
+		{"same operators", `if a && b && c { }`, 4, 2, 1},
+		{"mixed operators", `if a && b || c { }`, 4, 3, 1},
+		{"loop in if", `if a { for b { } }`, 3, 3, 1},
+		{"select", `select { case <-ch: default: }`, 2, 1, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			src := "package p\nfunc f() {\n" + test.body + "\n}\n"
+			f, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := f.Decls[0].(*ast.FuncDecl).Body
+			if got := complexity.Cyclomatic(body); got != test.cyclomatic {
+				t.Errorf("Cyclomatic = %d, want %d", got, test.cyclomatic)
+			}
+			if got := complexity.Cognitive(nil, nil, body); got != test.cognitive {
+				t.Errorf("Cognitive = %d, want %d", got, test.cognitive)
+			}
+			if got := complexity.MaxIfNesting(body); got != test.maxNesting {
+				t.Errorf("MaxIfNesting = %d, want %d", got, test.maxNesting)
+			}
+		})
+	}
+}
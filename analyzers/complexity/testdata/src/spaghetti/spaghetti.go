@@ -0,0 +1,238 @@
+// Package spaghetti holds ProcessOrder and ValidateUser from
+// golangexamples/spaghetticode, whose metrics are the ones the Spaghetti Code
+// example is known by, and functions simple enough to pass.
+package spaghetti
+
+// Order represents an order in the system
+type Order struct {
+	ID             int
+	Items          []Item
+	UserType       string
+	PaymentMethod  string
+	DiscountCode   string
+	ShippingMethod string
+	Status         string
+}
+
+type Item struct {
+	Product string
+	Price   float64
+}
+
+// ProcessOrder - A nightmare of nested conditions and tangled logic
+func ProcessOrder(orderID int, userType, paymentMethod, discountCode, shippingMethod string, items []Item) *Order { // want `ProcessOrder has cyclomatic complexity 37 \(> 15\)` `ProcessOrder has cognitive complexity 128 \(> 15\)` `ProcessOrder has if nesting depth 6 \(> 5\)`
+	status := ""
+	total := 0.0
+
+	if userType == "premium" {
+		if paymentMethod == "credit" {
+			if len(items) > 5 {
+				for _, item := range items {
+					total += item.Price
+				}
+				if discountCode != "" {
+					if discountCode == "SAVE20" {
+						total = total * 0.8
+						status = "processing"
+					} else if discountCode == "SAVE10" {
+						total = total * 0.9
+						if shippingMethod == "express" {
+							total += 20
+							status = "processing"
+						} else {
+							total += 5
+							status = "pending"
+						}
+					} else {
+						status = "invalid_code"
+						return nil
+					}
+				} else {
+					if shippingMethod == "express" {
+						total += 20
+						status = "processing"
+					} else {
+						status = "processing"
+					}
+				}
+			} else {
+				if discountCode != "" {
+					for _, item := range items {
+						total += item.Price
+					}
+					if discountCode == "SAVE20" {
+						total = total * 0.8
+						status = "processing"
+					} else {
+						status = "invalid_code"
+					}
+				} else {
+					for _, item := range items {
+						total += item.Price
+					}
+					status = "processing"
+				}
+			}
+		} else if paymentMethod == "paypal" {
+			for _, item := range items {
+				total += item.Price
+			}
+			if total > 100 {
+				if discountCode == "SAVE20" {
+					total = total * 0.8
+					status = "processing"
+				} else {
+					status = "processing"
+				}
+			} else {
+				status = "pending"
+			}
+		} else {
+			status = "invalid_payment"
+			return nil
+		}
+	} else if userType == "regular" {
+		if paymentMethod == "credit" {
+			for _, item := range items {
+				total += item.Price
+			}
+			if discountCode != "" {
+				if discountCode == "SAVE10" {
+					total = total * 0.9
+					if len(items) > 3 {
+						status = "processing"
+					} else {
+						status = "pending"
+					}
+				} else {
+					status = "invalid_code"
+				}
+			} else {
+				if len(items) > 3 {
+					status = "processing"
+				} else {
+					status = "pending"
+				}
+			}
+		} else {
+			status = "cash_only_for_regular"
+		}
+	} else {
+		if paymentMethod == "credit" {
+			for _, item := range items {
+				total += item.Price
+			}
+			status = "guest_order"
+		} else {
+			status = "invalid"
+		}
+	}
+
+	// More tangled logic for shipping
+	if status == "processing" {
+		if shippingMethod == "express" {
+			if userType == "premium" {
+				// Free express for premium
+			} else {
+				total += 20
+			}
+		} else if shippingMethod == "standard" {
+			if total > 50 {
+				// Free shipping
+			} else {
+				total += 5
+			}
+		}
+	}
+
+	// Even more tangled validation
+	if status != "" {
+		if status != "invalid" {
+			if total > 0 {
+				if userType == "premium" || userType == "regular" {
+					return &Order{
+						ID:             orderID,
+						Items:          items,
+						UserType:       userType,
+						PaymentMethod:  paymentMethod,
+						DiscountCode:   discountCode,
+						ShippingMethod: shippingMethod,
+						Status:         status,
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateUser - More spaghetti with deeply nested conditions
+func ValidateUser(username, password, email, userType string, age int, country string) (bool, string) { // want `ValidateUser has cognitive complexity 73 \(> 15\)` `ValidateUser has if nesting depth 9 \(> 5\)`
+	if username != "" {
+		if len(username) >= 3 {
+			if len(username) <= 20 {
+				if password != "" {
+					if len(password) >= 8 {
+						if email != "" {
+							if userType == "admin" {
+								if age >= 21 {
+									if country == "US" || country == "UK" {
+										return true, "valid"
+									}
+									return false, "admin must be in US or UK"
+								}
+								return false, "admin must be 21+"
+							} else if userType == "regular" {
+								if age >= 18 {
+									if country != "" {
+										return true, "valid"
+									}
+									return false, "country required"
+								}
+								return false, "must be 18+"
+							} else {
+								if age >= 13 {
+									return true, "valid"
+								}
+								return false, "must be 13+"
+							}
+						}
+						return false, "email required"
+					}
+					return false, "password too short"
+				}
+				return false, "password required"
+			}
+			return false, "username too long"
+		}
+		return false, "username too short"
+	}
+	return false, "username required"
+}
+
+// Total has a loop and one condition, well under every threshold.
+func Total(items []Item, discount float64) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.Price
+	}
+	if discount > 0 {
+		total *= 1 - discount
+	}
+	return total
+}
+
+// Status is a flat switch: many paths, but no nesting.
+func Status(code int) string {
+	switch code {
+	case 0:
+		return "pending"
+	case 1:
+		return "processing"
+	case 2:
+		return "shipped"
+	default:
+		return "unknown"
+	}
+}
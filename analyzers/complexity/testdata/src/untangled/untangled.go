@@ -0,0 +1,296 @@
+// Package untangled holds ProcessOrder and ValidateUser as
+// goodexamples/spaghetticode refactored them, with the rule engine
+// ProcessOrder now runs on. None of it is complex enough to report.
+package untangled
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultRules are the rules ProcessOrder uses.
+var DefaultRules = &Rules{}
+
+// Customer types, payment methods, discount codes and shipping methods.
+const (
+	Premium = "premium"
+	Regular = "regular"
+
+	Credit = "credit"
+	PayPal = "paypal"
+
+	Save10 = "SAVE10"
+	Save20 = "SAVE20"
+
+	Express  = "express"
+	Standard = "standard"
+)
+
+// Order statuses.
+const (
+	StatusProcessing  = "processing"
+	StatusPending     = "pending"
+	StatusInvalidCode = "invalid_code"
+)
+
+// Order represents an order in the system.
+type Order struct {
+	ID             int
+	Items          []Item
+	UserType       string
+	PaymentMethod  string
+	DiscountCode   string
+	ShippingMethod string
+	Status         string
+}
+
+// Item is one line of an order.
+type Item struct {
+	Product string
+	Price   float64
+}
+
+// Rules price orders. Each table is searched in order and only the first
+// rule whose conditions all hold applies; see pricing.yaml for the format.
+type Rules struct {
+	// Pricing sets the status and the total before shipping, or rejects
+	// the order.
+	Pricing []Rule
+	// Shipping adds a surcharge to orders it matches.
+	Shipping []Rule
+}
+
+// A Rule is an action and the conditions under which it applies.
+type Rule struct {
+	Name string
+	When Condition
+	Then Action
+}
+
+// A Condition is met when every field that is set holds. String lists
+// match any of their values, "" matching an empty one.
+type Condition struct {
+	User     []string
+	Payment  []string
+	Code     []string
+	Shipping []string
+	Status   []string
+	Items    *Bound
+	Subtotal *Bound
+	Total    *Bound
+}
+
+// A Bound is a range of numbers, open below and closed above.
+type Bound struct {
+	Over   *float64
+	AtMost *float64
+}
+
+// An Action is what a matching rule does: take Discount off the total,
+// then add Surcharge, then set Status. A rule with Reject set refuses the
+// order instead, for that reason.
+type Action struct {
+	Discount  float64
+	Surcharge float64
+	Status    string
+	Reject    string
+}
+
+// A Quote is the outcome of pricing an order.
+type Quote struct {
+	Total  float64
+	Status string
+	// Rejected says why the order was refused, or is "" if it was not.
+	Rejected string
+	// Applied names the rules that matched, in order.
+	Applied []string
+}
+
+// ProcessOrder prices an order with DefaultRules and returns it with its
+// status, or nil if the order cannot be accepted: guests, unsupported
+// payment methods, unknown codes on large premium baskets and orders that
+// come to nothing.
+func ProcessOrder(orderID int, userType, paymentMethod, discountCode, shippingMethod string, items []Item) *Order {
+	return DefaultRules.ProcessOrder(orderID, userType, paymentMethod, discountCode, shippingMethod, items)
+}
+
+// batchSize is how many pieces an OrderProcessor collects.
+const batchSize = 8
+
+// OrderProcessor collects the pieces of an order. The original tangle of
+// states and flags only ever did one thing: return the concatenation of
+// the first eight pieces when the eighth arrives.
+type OrderProcessor struct {
+	buf  strings.Builder
+	n    int
+	done bool
+}
+
+// NewOrderProcessor returns an empty processor.
+func NewOrderProcessor() *OrderProcessor {
+	return &OrderProcessor{}
+}
+
+// Process adds a piece. It returns the collected pieces and true when the
+// batch is complete, and false before then and for any later pieces.
+func (op *OrderProcessor) Process(data any) (string, bool) {
+	if op.done {
+		return "", false
+	}
+	fmt.Fprint(&op.buf, data)
+	op.n++
+	if op.n < batchSize {
+		return "", false
+	}
+	op.done = true
+	return op.buf.String(), true
+}
+
+// ValidateUser checks a sign-up, returning false and the first problem
+// found, or true and "valid".
+func ValidateUser(username, password, email, userType string, age int, country string) (bool, string) {
+	switch {
+	case username == "":
+		return false, "username required"
+	case len(username) < 3:
+		return false, "username too short"
+	case len(username) > 20:
+		return false, "username too long"
+	case password == "":
+		return false, "password required"
+	case len(password) < 8:
+		return false, "password too short"
+	case email == "":
+		return false, "email required"
+	}
+	if msg := eligibility(userType, age, country); msg != "" {
+		return false, msg
+	}
+	return true, "valid"
+}
+
+// eligibility returns why a user of the given type, age and country may
+// not sign up, or "" if they may.
+func eligibility(userType string, age int, country string) string {
+	switch userType {
+	case "admin":
+		if age < 21 {
+			return "admin must be 21+"
+		}
+		if country != "US" && country != "UK" {
+			return "admin must be in US or UK"
+		}
+	case Regular:
+		if age < 18 {
+			return "must be 18+"
+		}
+		if country == "" {
+			return "country required"
+		}
+	default:
+		if age < 13 {
+			return "must be 13+"
+		}
+	}
+	return ""
+}
+
+// Quote prices an order.
+func (r *Rules) Quote(userType, paymentMethod, discountCode, shippingMethod string, items []Item) Quote {
+	in := facts{
+		user:     userType,
+		payment:  paymentMethod,
+		code:     discountCode,
+		shipping: shippingMethod,
+		items:    len(items),
+		subtotal: subtotal(items),
+	}
+	q := Quote{Total: in.subtotal}
+	rule, ok := first(r.Pricing, in)
+	if !ok {
+		q.Rejected = "no pricing rule matches"
+		return q
+	}
+	q.Applied = append(q.Applied, rule.Name)
+	if rule.Then.Reject != "" {
+		q.Rejected = rule.Then.Reject
+		return q
+	}
+	q.Total = q.Total*(1-rule.Then.Discount) + rule.Then.Surcharge
+	q.Status = rule.Then.Status
+
+	in.status, in.total = q.Status, q.Total
+	if rule, ok := first(r.Shipping, in); ok {
+		q.Applied = append(q.Applied, rule.Name)
+		q.Total += rule.Then.Surcharge
+	}
+	if q.Total <= 0 {
+		q.Rejected = "nothing to pay"
+	}
+	return q
+}
+
+// ProcessOrder prices an order with the rules and returns it with its
+// status, or nil if the rules reject it.
+func (r *Rules) ProcessOrder(orderID int, userType, paymentMethod, discountCode, shippingMethod string, items []Item) *Order {
+	q := r.Quote(userType, paymentMethod, discountCode, shippingMethod, items)
+	if q.Rejected != "" {
+		return nil
+	}
+	return &Order{
+		ID:             orderID,
+		Items:          items,
+		UserType:       userType,
+		PaymentMethod:  paymentMethod,
+		DiscountCode:   discountCode,
+		ShippingMethod: shippingMethod,
+		Status:         q.Status,
+	}
+}
+
+// facts are what a condition can test.
+type facts struct {
+	user, payment, code, shipping, status string
+	items                                 int
+	subtotal, total                       float64
+}
+
+func first(rules []Rule, in facts) (Rule, bool) {
+	for _, r := range rules {
+		if r.When.holds(in) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (c Condition) holds(in facts) bool {
+	return oneOf(c.User, in.user) &&
+		oneOf(c.Payment, in.payment) &&
+		oneOf(c.Code, in.code) &&
+		oneOf(c.Shipping, in.shipping) &&
+		oneOf(c.Status, in.status) &&
+		c.Items.contains(float64(in.items)) &&
+		c.Subtotal.contains(in.subtotal) &&
+		c.Total.contains(in.total)
+}
+
+func oneOf(values []string, v string) bool {
+	return values == nil || slices.Contains(values, v)
+}
+
+func (b *Bound) contains(x float64) bool {
+	if b == nil {
+		return true
+	}
+	return (b.Over == nil || x > *b.Over) && (b.AtMost == nil || x <= *b.AtMost)
+}
+
+func subtotal(items []Item) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.Price
+	}
+	return total
+}
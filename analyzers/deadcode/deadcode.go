@@ -0,0 +1,567 @@
+// Package deadcode defines an Analyzer that reports code which provably
+// never runs inside a function, and offers fixes that delete it.
+package deadcode
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const Doc = `report unreachable statements and dead branches, with fixes that delete them
+
+The deadcode analyzer reports:
+
+  - statements after a return, panic, goto, break or continue, or after a
+    statement all of whose branches end that way (unreachable)
+  - else-if branches whose condition repeats an earlier condition in the
+    same chain (duplicate-condition)
+  - conditions that can never hold, such as len(x) < 0, a constant false,
+    or a repeat of an earlier guard in the same block that already left it
+    (impossible-condition)
+  - loops over empty composite literals such as range []int{} (empty-loop)
+
+Each diagnostic carries a suggested fix deleting the dead code, so running
+the deadcode command with -fix cleans a file automatically.`
+
+// Analyzer reports dead code.
+var Analyzer = &analysis.Analyzer{
+	Name: "deadcode",
+	Doc:  Doc,
+	URL:  "https://github.com/bclements/antipatterns/tree/main/analyzers/deadcode",
+	Run:  run,
+}
+
+// Categories of the diagnostics.
+const (
+	CategoryUnreachable         = "unreachable"
+	CategoryDuplicateCondition  = "duplicate-condition"
+	CategoryImpossibleCondition = "impossible-condition"
+	CategoryEmptyLoop           = "empty-loop"
+)
+
+type checker struct {
+	pass   *analysis.Pass
+	file   *ast.File
+	elseIf map[*ast.IfStmt]bool // if statements that are the else branch of another
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, f := range pass.Files {
+		c := &checker{pass: pass, file: f, elseIf: make(map[*ast.IfStmt]bool)}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt:
+				c.stmts(n.List)
+			case *ast.CaseClause:
+				c.stmts(n.Body)
+			case *ast.CommClause:
+				c.stmts(n.Body)
+			case *ast.IfStmt:
+				// Parents are visited first, so a chain is checked once, from its head.
+				if e, ok := n.Else.(*ast.IfStmt); ok {
+					c.elseIf[e] = true
+				}
+				if !c.elseIf[n] {
+					c.ifChain(n)
+				}
+			case *ast.RangeStmt:
+				c.rangeStmt(n)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// stmts checks one statement list for unreachable statements and repeated
+// guards.
+func (c *checker) stmts(list []ast.Stmt) {
+	for i, s := range list {
+		if ifs, ok := s.(*ast.IfStmt); ok {
+			c.repeatedGuard(list[:i], ifs)
+		}
+		if !c.terminates(s, true) || i == len(list)-1 {
+			continue
+		}
+		// A label may be the target of a goto, so only the statements
+		// before the next one are dead.
+		end := i + 1
+		for end < len(list) {
+			if _, ok := list[end].(*ast.LabeledStmt); ok {
+				break
+			}
+			end++
+		}
+		if end == i+1 {
+			continue
+		}
+		dead := list[i+1 : end]
+		n := len(dead)
+		d := analysis.Diagnostic{
+			Pos:      dead[0].Pos(),
+			End:      dead[n-1].End(),
+			Category: CategoryUnreachable,
+			Message:  fmt.Sprintf("unreachable code: %s after %s", plural(n, "statement"), describe(s)),
+		}
+		// Deleting the tail of a list after, say, "if true { return }"
+		// could leave a function without a terminating statement, so a
+		// fix is only offered when the compiler agrees the code is dead
+		// or the list keeps going after it.
+		if end < len(list) || c.terminates(s, false) {
+			d.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: fmt.Sprintf("Delete %s", plural(n, "unreachable statement")),
+				TextEdits: []analysis.TextEdit{{
+					Pos: s.End(),
+					End: c.lineEnd(dead[n-1].End()),
+				}},
+			}}
+		}
+		c.pass.Report(d)
+		return // statements after the dead ones are checked once the dead ones are gone
+	}
+}
+
+// ifChain checks an if/else-if chain, starting at its head, for repeated
+// and impossible conditions.
+func (c *checker) ifChain(head *ast.IfStmt) {
+	seen := make(map[string]bool)
+	var prev *ast.IfStmt
+	for ifs := head; ifs != nil; {
+		if ifs.Init == nil && isPure(c.pass.TypesInfo, ifs.Cond) {
+			key := types.ExprString(ast.Unparen(ifs.Cond))
+			if seen[key] && prev != nil {
+				c.pass.Report(analysis.Diagnostic{
+					Pos:      ifs.Cond.Pos(),
+					End:      ifs.Cond.End(),
+					Category: CategoryDuplicateCondition,
+					Message:  fmt.Sprintf("condition %s repeats an earlier branch of this if chain, so its body never runs", key),
+					SuggestedFixes: []analysis.SuggestedFix{{
+						Message:   "Delete the duplicate branch",
+						TextEdits: []analysis.TextEdit{deleteElseIf(prev, ifs)},
+					}},
+				})
+			}
+			seen[key] = true
+		}
+		if why := c.impossible(ifs.Cond); why != "" {
+			c.reportImpossible(ifs, prev, why)
+		}
+		prev = ifs
+		ifs, _ = ifs.Else.(*ast.IfStmt)
+	}
+}
+
+// repeatedGuard reports ifs when an earlier statement in the same block is
+// an if with the same pure condition whose body always leaves the block,
+// and nothing in between can change the condition's operands.
+func (c *checker) repeatedGuard(before []ast.Stmt, ifs *ast.IfStmt) {
+	if ifs.Init != nil || !isPure(c.pass.TypesInfo, ifs.Cond) {
+		return
+	}
+	key := types.ExprString(ast.Unparen(ifs.Cond))
+	operands := c.objects(ifs.Cond)
+	for i := len(before) - 1; i >= 0; i-- {
+		if c.mayModify(before[i], operands) {
+			return
+		}
+		guard, ok := before[i].(*ast.IfStmt)
+		if !ok || guard.Init != nil || guard.Else != nil || !c.terminates(guard.Body, false) {
+			continue
+		}
+		if types.ExprString(ast.Unparen(guard.Cond)) == key {
+			line := c.pass.Fset.Position(guard.Pos()).Line
+			c.reportImpossible(ifs, nil, fmt.Sprintf("%s was already handled by the guard on line %d", key, line))
+			return
+		}
+	}
+}
+
+// impossible explains why cond can never be true, or returns "".
+func (c *checker) impossible(cond ast.Expr) string {
+	cond = ast.Unparen(cond)
+	if tv, ok := c.pass.TypesInfo.Types[cond]; ok && tv.Value != nil && tv.Value.Kind() == constant.Bool {
+		if !constant.BoolVal(tv.Value) {
+			return fmt.Sprintf("%s is always false", types.ExprString(cond))
+		}
+		return ""
+	}
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok {
+		return ""
+	}
+	// len(x) < 0, 0 > len(x) and friends.
+	isLen := func(e ast.Expr) bool {
+		call, ok := ast.Unparen(e).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		b, ok := c.pass.TypesInfo.Uses[id].(*types.Builtin)
+		return ok && (b.Name() == "len" || b.Name() == "cap")
+	}
+	isZero := func(e ast.Expr) bool {
+		tv, ok := c.pass.TypesInfo.Types[e]
+		return ok && tv.Value != nil && constant.Sign(tv.Value) <= 0 && tv.Value.Kind() == constant.Int
+	}
+	switch {
+	case bin.Op == token.LSS && isLen(bin.X) && isZero(bin.Y),
+		bin.Op == token.GTR && isZero(bin.X) && isLen(bin.Y):
+		return fmt.Sprintf("%s is always false: lengths are never negative", types.ExprString(cond))
+	}
+	return ""
+}
+
+// reportImpossible reports an if statement whose condition never holds.
+// prev is the if statement whose else branch holds ifs, if any.
+func (c *checker) reportImpossible(ifs, prev *ast.IfStmt, why string) {
+	d := analysis.Diagnostic{
+		Pos:      ifs.Cond.Pos(),
+		End:      ifs.Cond.End(),
+		Category: CategoryImpossibleCondition,
+		Message:  fmt.Sprintf("dead branch: %s", why),
+	}
+	switch {
+	case prev != nil:
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Delete the dead branch",
+			TextEdits: []analysis.TextEdit{deleteElseIf(prev, ifs)},
+		}}
+	case ifs.Else == nil && ifs.Init == nil:
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Delete the dead if statement",
+			TextEdits: []analysis.TextEdit{c.deleteStmt(ifs)},
+		}}
+	}
+	c.pass.Report(d)
+}
+
+// rangeStmt reports loops over empty composite literals.
+func (c *checker) rangeStmt(rs *ast.RangeStmt) {
+	lit, ok := ast.Unparen(rs.X).(*ast.CompositeLit)
+	if !ok || len(lit.Elts) != 0 {
+		return
+	}
+	c.pass.Report(analysis.Diagnostic{
+		Pos:      rs.Pos(),
+		End:      rs.X.End(),
+		Category: CategoryEmptyLoop,
+		Message:  fmt.Sprintf("loop over empty %s never runs its body", types.ExprString(lit)),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Delete the loop",
+			TextEdits: []analysis.TextEdit{c.deleteStmt(rs)},
+		}},
+	})
+}
+
+// terminates reports whether control never continues past s to the next
+// statement in its list. With consts set, if and for conditions that are
+// constant are taken into account; without it, the answer is the language's
+// own notion of a terminating statement, which decides whether a function
+// may end there.
+func (c *checker) terminates(s ast.Stmt, consts bool) bool {
+	switch s := s.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok != token.FALLTHROUGH
+	case *ast.ExprStmt:
+		call, ok := ast.Unparen(s.X).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		b, ok := c.pass.TypesInfo.Uses[id].(*types.Builtin)
+		return ok && b.Name() == "panic"
+	case *ast.BlockStmt:
+		if !consts {
+			return len(s.List) > 0 && c.terminates(s.List[len(s.List)-1], false)
+		}
+		// Flow ends at the first terminating statement, unless a later
+		// label lets a goto back in.
+		ends := false
+		for _, s := range s.List {
+			if _, ok := s.(*ast.LabeledStmt); ok {
+				ends = false
+			}
+			ends = ends || c.terminates(s, true)
+		}
+		return ends
+	case *ast.LabeledStmt:
+		return c.terminates(s.Stmt, consts)
+	case *ast.IfStmt:
+		if v, ok := c.constBool(s.Cond); ok && consts {
+			if v {
+				return c.terminates(s.Body, consts)
+			}
+			return s.Else != nil && c.terminates(s.Else, consts)
+		}
+		return s.Else != nil && c.terminates(s.Body, consts) && c.terminates(s.Else, consts)
+	case *ast.ForStmt:
+		infinite := s.Cond == nil
+		if v, ok := c.constBool(s.Cond); ok && v && consts {
+			infinite = true
+		}
+		return infinite && !hasBreak(s.Body)
+	case *ast.SwitchStmt:
+		return c.clausesTerminate(s.Body, true, consts)
+	case *ast.TypeSwitchStmt:
+		return c.clausesTerminate(s.Body, true, consts)
+	case *ast.SelectStmt:
+		return c.clausesTerminate(s.Body, false, consts)
+	}
+	return false
+}
+
+// clausesTerminate reports whether every clause of a switch or select body
+// terminates without breaking out, and, for switches, whether there is a
+// default clause.
+func (c *checker) clausesTerminate(body *ast.BlockStmt, needDefault, consts bool) bool {
+	hasDefault := !needDefault
+	for _, cl := range body.List {
+		var list []ast.Stmt
+		switch cl := cl.(type) {
+		case *ast.CaseClause:
+			list = cl.Body
+			hasDefault = hasDefault || cl.List == nil
+		case *ast.CommClause:
+			list = cl.Body
+		}
+		if len(list) == 0 {
+			return false
+		}
+		last := list[len(list)-1]
+		if br, ok := last.(*ast.BranchStmt); ok && br.Tok == token.FALLTHROUGH {
+			continue
+		}
+		if !c.terminates(last, consts) {
+			return false
+		}
+		for _, s := range list {
+			if hasBreak(s) {
+				return false
+			}
+		}
+	}
+	return hasDefault
+}
+
+// hasBreak reports whether n contains a break that leaves the statement n
+// belongs to: an unlabeled break outside any nested loop, switch or select,
+// or any labeled break.
+func hasBreak(n ast.Node) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BranchStmt:
+			if n.Tok == token.BREAK {
+				found = true
+			}
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			// Only labeled breaks inside these can reach us.
+			ast.Inspect(n, func(n ast.Node) bool {
+				if br, ok := n.(*ast.BranchStmt); ok && br.Tok == token.BREAK && br.Label != nil {
+					found = true
+				}
+				return !found
+			})
+			return false
+		case *ast.FuncLit:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+func (c *checker) constBool(e ast.Expr) (value, ok bool) {
+	if e == nil {
+		return false, false
+	}
+	tv, found := c.pass.TypesInfo.Types[e]
+	if !found || tv.Value == nil || tv.Value.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(tv.Value), true
+}
+
+// isPure reports whether evaluating e has no side effects, so evaluating it
+// twice gives the same answer.
+func isPure(info *types.Info, e ast.Expr) bool {
+	pure := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if tv, ok := info.Types[n.Fun]; ok && tv.IsType() {
+				return true // conversion
+			}
+			id, ok := ast.Unparen(n.Fun).(*ast.Ident)
+			if b, isBuiltin := info.Uses[id].(*types.Builtin); !ok || !isBuiltin || (b.Name() != "len" && b.Name() != "cap") {
+				pure = false
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				pure = false
+			}
+		case *ast.FuncLit:
+			pure = false
+		}
+		return pure
+	})
+	return pure
+}
+
+// objects returns the variables referred to in e.
+func (c *checker) objects(e ast.Expr) map[types.Object]bool {
+	objs := make(map[types.Object]bool)
+	ast.Inspect(e, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if v, ok := c.pass.TypesInfo.Uses[id].(*types.Var); ok {
+				objs[v] = true
+			}
+		}
+		return true
+	})
+	return objs
+}
+
+// mayModify reports whether s may assign to, or take the address of, any of
+// the given variables. Package-level variables and struct fields can change
+// behind any call, so for them every call counts as well.
+func (c *checker) mayModify(s ast.Stmt, objs map[types.Object]bool) bool {
+	touches := func(e ast.Expr) bool {
+		hit := false
+		ast.Inspect(e, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && objs[c.pass.TypesInfo.ObjectOf(id)] {
+				hit = true
+			}
+			return !hit
+		})
+		return hit
+	}
+	mod := false
+	ast.Inspect(s, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				mod = mod || touches(lhs)
+			}
+		case *ast.IncDecStmt:
+			mod = mod || touches(n.X)
+		case *ast.UnaryExpr:
+			mod = mod || (n.Op == token.AND && touches(n.X))
+		case *ast.RangeStmt:
+			mod = mod || (n.Key != nil && touches(n.Key)) || (n.Value != nil && touches(n.Value))
+		case *ast.CallExpr:
+			for obj := range objs {
+				if v := obj.(*types.Var); v.IsField() || v.Parent() == v.Pkg().Scope() {
+					mod = true
+				}
+			}
+		}
+		return !mod
+	})
+	return mod
+}
+
+// deleteElseIf returns an edit removing the branch ifs from the chain whose
+// previous link is prev, keeping any else branches that follow it.
+func deleteElseIf(prev, ifs *ast.IfStmt) analysis.TextEdit {
+	if ifs.Else == nil {
+		return analysis.TextEdit{Pos: prev.Body.End(), End: ifs.End()}
+	}
+	// "else if dup { ... } else X" becomes "else X".
+	return analysis.TextEdit{Pos: ifs.Pos(), End: ifs.Else.Pos()}
+}
+
+// deleteStmt returns an edit removing s, along with the comments that
+// directly precede it and any comment trailing it on its last line.
+func (c *checker) deleteStmt(s ast.Stmt) analysis.TextEdit {
+	start := s.Pos()
+	for _, cg := range c.file.Comments {
+		if cg.End() < start && c.line(cg.End())+1 == c.line(start) && c.line(cg.Pos()) > c.lineOfPrevToken(start) {
+			start = cg.Pos()
+		}
+	}
+	// Also take the indentation and newline before the statement.
+	tf := c.pass.Fset.File(start)
+	lineStart := tf.LineStart(c.line(start))
+	if prevEnd := lineStart - 1; prevEnd > token.Pos(tf.Base()) {
+		start = prevEnd
+	}
+	return analysis.TextEdit{Pos: start, End: c.lineEnd(s.End())}
+}
+
+// lineOfPrevToken returns the line of the last syntax node ending before
+// pos, so that a comment belonging to an earlier statement is not deleted.
+func (c *checker) lineOfPrevToken(pos token.Pos) int {
+	last := token.NoPos
+	ast.Inspect(c.file, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= pos {
+			return false
+		}
+		if n.End() <= pos && n.End() > last {
+			if _, isComment := n.(*ast.CommentGroup); !isComment {
+				last = n.End()
+			}
+		}
+		return true
+	})
+	if !last.IsValid() {
+		return 0
+	}
+	return c.line(last)
+}
+
+// lineEnd extends pos past a comment that trails it on the same line.
+func (c *checker) lineEnd(pos token.Pos) token.Pos {
+	line := c.line(pos)
+	for _, cg := range c.file.Comments {
+		if cg.Pos() >= pos && c.line(cg.Pos()) == line {
+			return cg.End()
+		}
+	}
+	return pos
+}
+
+func (c *checker) line(pos token.Pos) int { return c.pass.Fset.Position(pos).Line }
+
+// describe names the kind of statement that ends control flow.
+func describe(s ast.Stmt) string {
+	switch s := s.(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.BranchStmt:
+		return s.Tok.String()
+	case *ast.ExprStmt:
+		return "panic"
+	case *ast.LabeledStmt:
+		return describe(s.Stmt)
+	case *ast.IfStmt:
+		return "an if statement whose branches all return"
+	case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+		return "a switch whose cases all return"
+	case *ast.SelectStmt:
+		return "a select whose cases all return"
+	case *ast.ForStmt:
+		return "an infinite loop"
+	}
+	return "a terminating statement"
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,12 @@
+package deadcode_test
+
+import (
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/deadcode"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), deadcode.Analyzer, "a")
+}
@@ -0,0 +1,90 @@
+package a
+
+import "fmt"
+
+func afterReturn(x int) int {
+	if x < 0 {
+		return 0
+		fmt.Println("never") // want `unreachable code: 2 statements after return`
+		x = -x
+	}
+	return x
+}
+
+func afterPanic() {
+	panic("boom")
+	fmt.Println("never") // want `unreachable code: 1 statement after panic`
+}
+
+func afterIf(ok bool) string {
+	if ok {
+		return "yes"
+	} else {
+		return "no"
+	}
+	return "maybe" // want `unreachable code: 1 statement after an if statement whose branches all return`
+}
+
+func duplicate(kind string) int {
+	if kind == "a" {
+		return 1
+	} else if kind == "b" {
+		return 2
+	} else if kind == "a" { // want `condition kind == "a" repeats an earlier branch of this if chain, so its body never runs`
+		return 3
+	}
+	return 0
+}
+
+func impossible(data []byte) bool {
+	if len(data) < 0 { // want `dead branch: len\(data\) < 0 is always false: lengths are never negative`
+		return false
+	}
+	return true
+}
+
+func guarded(items []int) int {
+	if len(items) == 0 {
+		return 0
+	}
+	if len(items) == 0 { // want `dead branch: len\(items\) == 0 was already handled by the guard on line 47`
+		return -1
+	}
+	return items[0]
+}
+
+func emptyLoop() int {
+	n := 0
+	for range []int{} { // want `loop over empty \[\]int\{\} never runs its body`
+		n++
+	}
+	return n
+}
+
+// live has none of the above: every statement can run.
+func live(items []int, kind string) int {
+	n := 0
+	for _, it := range items {
+		if it < 0 {
+			continue
+		}
+		n += it
+	}
+	switch kind {
+	case "double":
+		return n * 2
+	}
+	if len(items) == 0 {
+		return -1
+	}
+	if kind == "a" {
+		return n
+	} else if kind == "b" {
+		return n + 1
+	}
+	return n
+}
+
+func report(n int) {
+	fmt.Println(live(nil, "") + n)
+}
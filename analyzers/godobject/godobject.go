@@ -0,0 +1,650 @@
+// Package godobject defines an Analyzer that reports God Objects: struct
+// types whose fields and methods span many unrelated responsibilities.
+//
+// Responsibilities are found the way a reviewer would find them by hand.
+// Fields are first grouped by the blank lines and section comments the
+// author already put between them (structs without such sections start with
+// one group per field, joined when their names share a leading word such as
+// "cache" in cache/cacheExpiry). Groups are then merged whenever a method
+// touches fields from more than one of them, since those fields evidently
+// belong together. Every method is then attached to the group whose fields
+// it uses, or, for methods that touch no fields, to the group whose
+// vocabulary best matches the method name. Finally a section is split when
+// some of its methods work on fields unrelated to the section's label, as
+// with tokens kept under "User management".
+package godobject
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const Doc = `report structs whose fields and methods span many responsibilities
+
+The godobject analyzer clusters a struct's fields by the methods that use
+them and by how the fields are sectioned in the declaration, attaches each
+method to a cluster, and reports structs that have at least -min-methods
+methods spread over at least -min-groups clusters. Each reported cluster is a
+suggested type to split out.`
+
+// Analyzer reports God Objects.
+var Analyzer = &analysis.Analyzer{
+	Name:     "godobject",
+	Doc:      Doc,
+	URL:      "https://github.com/bclements/antipatterns/tree/main/analyzers/godobject",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	minMethods int
+	minFields  int
+	minGroups  int
+)
+
+func init() {
+	Analyzer.Flags.IntVar(&minMethods, "min-methods", 15, "minimum number of methods before a struct is considered")
+	Analyzer.Flags.IntVar(&minFields, "min-fields", 10, "minimum number of fields before a struct is considered")
+	Analyzer.Flags.IntVar(&minGroups, "min-groups", 4, "minimum number of responsibilities (groups with at least one method) to report")
+}
+
+// A group is one suggested responsibility: a set of fields and the methods
+// that work with them.
+type group struct {
+	label   string
+	fields  []*types.Var
+	methods []*types.Func
+	words   map[string]int // vocabulary of the fields and label
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Collect the methods declared in this package for each named type.
+	methods := make(map[*types.TypeName][]*ast.FuncDecl)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fd := n.(*ast.FuncDecl)
+		if fd.Recv == nil || fd.Body == nil {
+			return
+		}
+		fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		if !ok {
+			return
+		}
+		if named := receiverNamed(fn); named != nil {
+			methods[named.Obj()] = append(methods[named.Obj()], fd)
+		}
+	})
+
+	inspect.Preorder([]ast.Node{(*ast.TypeSpec)(nil)}, func(n ast.Node) {
+		spec := n.(*ast.TypeSpec)
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return
+		}
+		obj, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+		if !ok {
+			return
+		}
+		decls := methods[obj]
+		nfields := st.Fields.NumFields()
+		if len(decls) < minMethods || nfields < minFields {
+			return
+		}
+
+		groups, unassigned := cluster(pass, st, decls)
+		var used []*group
+		for _, g := range groups {
+			if len(g.methods) > 0 {
+				used = append(used, g)
+			}
+		}
+		if len(used) < minGroups {
+			return
+		}
+
+		labels := make([]string, len(used))
+		related := make([]analysis.RelatedInformation, 0, len(used))
+		for i, g := range used {
+			labels[i] = g.label
+			pos := g.methods[0].Pos()
+			if len(g.fields) > 0 {
+				pos = g.fields[0].Pos()
+			}
+			related = append(related, analysis.RelatedInformation{
+				Pos:     pos,
+				Message: describe(g),
+			})
+		}
+		if len(unassigned) > 0 {
+			names := make([]string, len(unassigned))
+			for i, fn := range unassigned {
+				names[i] = fn.Name()
+			}
+			sort.Strings(names)
+			related = append(related, analysis.RelatedInformation{
+				Pos:     unassigned[0].Pos(),
+				Message: "no clear owner: " + strings.Join(names, ", "),
+			})
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     spec.Name.Pos(),
+			End:     spec.Name.End(),
+			Message: fmt.Sprintf("%s is a God Object: %d methods and %d fields span %d responsibilities; consider splitting it into: %s", spec.Name.Name, len(decls), nfields, len(used), strings.Join(labels, ", ")),
+			Related: related,
+		})
+	})
+	return nil, nil
+}
+
+// cluster groups the fields of st and attaches each method in decls to one
+// of the groups. Groups are returned in field declaration order, followed by
+// the methods that fit none of them.
+func cluster(pass *analysis.Pass, st *ast.StructType, decls []*ast.FuncDecl) ([]*group, []*types.Func) {
+	// Initial groups come from the struct's own sections.
+	var groups []*group
+	index := make(map[*types.Var]int) // field -> group
+	sectioned := hasSections(pass.Fset, st)
+	vars := pass.TypesInfo.TypeOf(st).(*types.Struct)
+	next := 0
+	for i, f := range st.Fields.List {
+		if sectioned && (i == 0 || startsSection(pass.Fset, st.Fields.List[i-1], f)) {
+			groups = append(groups, newGroup(sectionLabel(f.Doc)))
+		}
+		n := max(len(f.Names), 1) // embedded fields have no names
+		for ; n > 0; n-- {
+			v := vars.Field(next)
+			next++
+			if !sectioned {
+				groups = append(groups, newGroup(""))
+			}
+			g := groups[len(groups)-1]
+			g.fields = append(g.fields, v)
+			index[v] = len(groups) - 1
+		}
+	}
+
+	uf := newUnionFind(len(groups))
+
+	// Without explicit sections, fields sharing a leading word belong together.
+	if !sectioned {
+		first := make(map[string]int)
+		for i, g := range groups {
+			for _, v := range g.fields {
+				w := words(v.Name())
+				if len(w) == 0 {
+					continue
+				}
+				if j, ok := first[w[0]]; ok {
+					uf.union(i, j)
+				} else {
+					first[w[0]] = i
+				}
+			}
+		}
+	}
+
+	// Methods that touch fields from several groups join those groups.
+	uses := fieldUses(pass, decls)
+	for _, fd := range decls {
+		fn := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		prev := -1
+		for v := range uses[fn] {
+			i, ok := index[v]
+			if !ok {
+				continue
+			}
+			if prev >= 0 {
+				uf.union(prev, i)
+			}
+			prev = i
+		}
+	}
+
+	// Collapse the union-find into the final groups.
+	merged := make(map[int]int) // union-find root -> index in out
+	var out []*group
+	for i, g := range groups {
+		root := uf.find(i)
+		j, ok := merged[root]
+		if !ok {
+			j = len(out)
+			merged[root] = j
+			out = append(out, newGroup(""))
+		}
+		m := out[j]
+		if m.label == "" {
+			m.label = g.label
+		} else if g.label != "" && !strings.Contains(m.label, g.label) {
+			m.label += " + " + g.label
+		}
+		m.fields = append(m.fields, g.fields...)
+		for _, v := range g.fields {
+			index[v] = j
+		}
+	}
+	for _, g := range out {
+		for _, v := range g.fields {
+			for _, w := range words(v.Name()) {
+				g.words[w]++
+			}
+		}
+		for _, w := range words(g.label) {
+			g.words[w]++
+		}
+		if g.label == "" {
+			g.label = dominantWord(g)
+		}
+	}
+
+	// Attach each method to a group.
+	var unassigned []*types.Func
+	for _, fd := range decls {
+		fn := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		best, bestScore := -1, 0
+		votes := make(map[int]int)
+		for v := range uses[fn] {
+			if i, ok := index[v]; ok {
+				votes[i]++
+			}
+		}
+		for i, n := range votes {
+			if n > bestScore || (n == bestScore && i < best) {
+				best, bestScore = i, n
+			}
+		}
+		if best < 0 {
+			for i, g := range out {
+				score := 0
+				for _, w := range words(fn.Name()) {
+					score += g.words[w]
+				}
+				if score > bestScore {
+					best, bestScore = i, score
+				}
+			}
+		}
+		if best < 0 {
+			unassigned = append(unassigned, fn)
+			continue
+		}
+		out[best].methods = append(out[best].methods, fn)
+	}
+	if sectioned {
+		out = splitSections(out, uses)
+	}
+	return out, unassigned
+}
+
+// splitSections splits fields off a labelled section when they share no
+// vocabulary with the label or with the fields that match it, and some of
+// the section's methods work on them rather than on the rest: the authTokens
+// and refreshTokens kept under "User management" are an auth service, not
+// part of user management. Fields that no method claims stay in their
+// section.
+func splitSections(groups []*group, uses map[*types.Func]map[*types.Var]bool) []*group {
+	var out []*group
+	for _, g := range groups {
+		out = append(out, g)
+		label := make(map[string]bool)
+		for _, w := range words(g.label) {
+			label[w] = true
+		}
+		if len(label) == 0 || len(g.fields) < 2 {
+			continue
+		}
+
+		// Fields sharing a word, or used by the same method, belong together.
+		uf := newUnionFind(len(g.fields))
+		index := make(map[*types.Var]int)
+		byWord := make(map[string]int)
+		for i, v := range g.fields {
+			index[v] = i
+			for _, w := range words(v.Name()) {
+				if j, ok := byWord[w]; ok {
+					uf.union(i, j)
+				} else {
+					byWord[w] = i
+				}
+			}
+		}
+		for _, fn := range g.methods {
+			prev := -1
+			for v := range uses[fn] {
+				if i, ok := index[v]; ok {
+					if prev >= 0 {
+						uf.union(prev, i)
+					}
+					prev = i
+				}
+			}
+		}
+
+		// The core is whatever matches the label; the rest are candidates.
+		core := -1
+		for w := range label {
+			if i, ok := byWord[w]; ok && (core < 0 || uf.find(i) < core) {
+				core = uf.find(i)
+			}
+		}
+		if core < 0 {
+			continue
+		}
+		parts := make(map[int]*group)
+		var order []int
+		for i, v := range g.fields {
+			root := uf.find(i)
+			if root == core {
+				continue
+			}
+			p, ok := parts[root]
+			if !ok {
+				p = newGroup(v.Name())
+				if w := words(v.Name()); len(w) > 0 {
+					p.label = w[0]
+				}
+				parts[root] = p
+				order = append(order, root)
+			}
+			p.fields = append(p.fields, v)
+			for _, w := range words(v.Name()) {
+				p.words[w]++
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+
+		// Each method moves to the part whose fields it uses, or, if it uses
+		// none of the section's fields, whose vocabulary it matches better
+		// than the label.
+		var kept []*types.Func
+		for _, fn := range g.methods {
+			used := -1
+			for v := range uses[fn] {
+				if i, ok := index[v]; ok {
+					used = uf.find(i)
+				}
+			}
+			var best *group
+			if used >= 0 {
+				best = parts[used]
+			} else {
+				bestScore := 0
+				for _, w := range words(fn.Name()) {
+					if label[w] {
+						bestScore++
+					}
+				}
+				for _, root := range order {
+					score := 0
+					for _, w := range words(fn.Name()) {
+						score += parts[root].words[w]
+					}
+					if score > bestScore {
+						best, bestScore = parts[root], score
+					}
+				}
+			}
+			if best == nil {
+				kept = append(kept, fn)
+				continue
+			}
+			best.methods = append(best.methods, fn)
+		}
+		g.methods = kept
+
+		var fields []*types.Var
+		for _, v := range g.fields {
+			if uf.find(index[v]) == core {
+				fields = append(fields, v)
+			}
+		}
+		for _, root := range order {
+			p := parts[root]
+			if len(p.methods) == 0 {
+				fields = append(fields, p.fields...)
+				continue
+			}
+			out = append(out, p)
+		}
+		g.fields = slices.SortedFunc(slices.Values(fields), func(a, b *types.Var) int {
+			return cmp.Compare(index[a], index[b])
+		})
+	}
+	return out
+}
+
+// fieldUses reports, for each method, the receiver fields it reads or
+// writes either directly or through other methods on the same receiver.
+func fieldUses(pass *analysis.Pass, decls []*ast.FuncDecl) map[*types.Func]map[*types.Var]bool {
+	direct := make(map[*types.Func]map[*types.Var]bool)
+	calls := make(map[*types.Func][]*types.Func)
+	for _, fd := range decls {
+		fn := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		direct[fn] = make(map[*types.Var]bool)
+		if len(fd.Recv.List[0].Names) == 0 {
+			continue
+		}
+		recv := pass.TypesInfo.Defs[fd.Recv.List[0].Names[0]]
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			id, ok := sel.X.(*ast.Ident)
+			if !ok || recv == nil || pass.TypesInfo.Uses[id] != recv {
+				return true
+			}
+			switch obj := pass.TypesInfo.Uses[sel.Sel].(type) {
+			case *types.Var:
+				direct[fn][obj] = true
+			case *types.Func:
+				calls[fn] = append(calls[fn], obj)
+			}
+			return true
+		})
+	}
+
+	uses := make(map[*types.Func]map[*types.Var]bool)
+	var visit func(fn *types.Func, into map[*types.Var]bool, seen map[*types.Func]bool)
+	visit = func(fn *types.Func, into map[*types.Var]bool, seen map[*types.Func]bool) {
+		if seen[fn] {
+			return
+		}
+		seen[fn] = true
+		for v := range direct[fn] {
+			into[v] = true
+		}
+		for _, callee := range calls[fn] {
+			visit(callee, into, seen)
+		}
+	}
+	for fn := range direct {
+		uses[fn] = make(map[*types.Var]bool)
+		visit(fn, uses[fn], make(map[*types.Func]bool))
+	}
+	return uses
+}
+
+// hasSections reports whether the struct's fields are divided into sections
+// by blank lines or comments.
+func hasSections(fset *token.FileSet, st *ast.StructType) bool {
+	list := st.Fields.List
+	for i := 1; i < len(list); i++ {
+		if startsSection(fset, list[i-1], list[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// startsSection reports whether f is separated from the field before it by a
+// comment or a blank line.
+func startsSection(fset *token.FileSet, prev, f *ast.Field) bool {
+	return f.Doc != nil || fset.Position(f.Pos()).Line > fset.Position(prev.End()).Line+1
+}
+
+// sectionLabel turns a section comment such as "// User management" into a
+// group label.
+func sectionLabel(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	text := strings.TrimSpace(doc.Text())
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	if i := strings.IndexAny(text, ":.-("); i > 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
+
+func newGroup(label string) *group {
+	return &group{label: label, words: make(map[string]int)}
+}
+
+func dominantWord(g *group) string {
+	best, n := "", 0
+	for w, c := range g.words {
+		if c > n || (c == n && w < best) {
+			best, n = w, c
+		}
+	}
+	if best == "" && len(g.fields) > 0 {
+		return g.fields[0].Name()
+	}
+	return best
+}
+
+func describe(g *group) string {
+	fields := make([]string, len(g.fields))
+	for i, v := range g.fields {
+		fields[i] = v.Name()
+	}
+	methods := make([]string, len(g.methods))
+	for i, fn := range g.methods {
+		methods[i] = fn.Name()
+	}
+	sort.Strings(methods)
+	if len(fields) == 0 {
+		return fmt.Sprintf("%s: methods %s", g.label, strings.Join(methods, ", "))
+	}
+	return fmt.Sprintf("%s: fields %s; methods %s", g.label, strings.Join(fields, ", "), strings.Join(methods, ", "))
+}
+
+// ignored holds words that say what a method does rather than what it
+// works on, and words too generic to identify a responsibility.
+var ignored = map[string]bool{
+	"add": true, "all": true, "api": true, "by": true, "calculate": true,
+	"cancel": true, "clear": true, "create": true, "current": true,
+	"delete": true, "do": true, "enabled": true, "execute": true,
+	"export": true, "for": true, "from": true, "generate": true, "get": true,
+	"handle": true, "id": true, "is": true, "list": true, "load": true,
+	"management": true, "manager": true, "mark": true, "max": true,
+	"min": true, "new": true, "of": true, "process": true, "processing": true,
+	"read": true, "remove": true, "save": true, "search": true, "send": true,
+	"service": true, "set": true, "system": true, "the": true, "to": true,
+	"track": true, "unread": true, "update": true, "url": true,
+	"validate": true, "with": true,
+}
+
+// words splits a Go identifier or comment label into lower-case, singular
+// words, dropping the ones listed in ignored.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		w := singular(strings.ToLower(string(cur)))
+		cur = cur[:0]
+		if len(w) > 1 && !ignored[w] {
+			out = append(out, w)
+		}
+	}
+	rs := []rune(s)
+	for i, r := range rs {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 &&
+			(unicode.IsLower(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]) && unicode.IsUpper(rs[i-1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+func singular(w string) string {
+	switch {
+	case strings.HasSuffix(w, "ies") && len(w) > 4:
+		return w[:len(w)-3] + "y"
+	case strings.HasSuffix(w, "ses") || strings.HasSuffix(w, "ss") || strings.HasSuffix(w, "us"):
+		return w
+	case strings.HasSuffix(w, "s") && len(w) > 3:
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func receiverNamed(fn *types.Func) *types.Named {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, _ := t.(*types.Named)
+	if named != nil {
+		named = named.Origin()
+	}
+	return named
+}
+
+type unionFind []int
+
+func newUnionFind(n int) unionFind {
+	uf := make(unionFind, n)
+	for i := range uf {
+		uf[i] = i
+	}
+	return uf
+}
+
+func (uf unionFind) find(i int) int {
+	for uf[i] != i {
+		uf[i] = uf[uf[i]]
+		i = uf[i]
+	}
+	return i
+}
+
+func (uf unionFind) union(i, j int) {
+	if ri, rj := uf.find(i), uf.find(j); ri != rj {
+		if ri < rj {
+			uf[rj] = ri
+		} else {
+			uf[ri] = rj
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package godobject_test
+
+import (
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/godobject"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	setFlags(t, map[string]string{"min-methods": "8", "min-fields": "8", "min-groups": "4"})
+	analysistest.Run(t, analysistest.TestData(), godobject.Analyzer, "a")
+}
+
+// TestApplicationManager runs the analyzer with its default thresholds on
+// the original God Object.
+func TestApplicationManager(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), godobject.Analyzer, "godobject")
+}
+
+// setFlags sets the analyzer's flags for the rest of the test and restores
+// them afterwards.
+func setFlags(t *testing.T, values map[string]string) {
+	t.Helper()
+	flags := &godobject.Analyzer.Flags
+	for name, value := range values {
+		old := flags.Lookup(name).Value.String()
+		t.Cleanup(func() {
+			if err := flags.Set(name, old); err != nil {
+				t.Error(err)
+			}
+		})
+		if err := flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
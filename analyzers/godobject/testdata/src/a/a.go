@@ -0,0 +1,71 @@
+package a
+
+import "slices"
+
+// Shop mixes users, orders, mail and logging, each in its own section.
+type Shop struct { // want `Shop is a God Object: 8 methods and 8 fields span 4 responsibilities; consider splitting it into: Users, Orders, Mail, Logging`
+	// Users
+	users    []string
+	sessions map[string]string
+
+	// Orders
+	orders []int
+	queue  []int
+
+	// Mail
+	templates map[string]string
+	outbox    []string
+
+	// Logging
+	logs   []string
+	errors []string
+}
+
+func (s *Shop) AddUser(name string) { s.users = append(s.users, name) }
+func (s *Shop) Login(name, token string) {
+	if slices.Contains(s.users, name) {
+		s.sessions[name] = token
+	}
+}
+func (s *Shop) PlaceOrder(id int) { s.orders = append(s.orders, id); s.queue = append(s.queue, id) }
+func (s *Shop) Pending() int      { return len(s.queue) }
+func (s *Shop) Send(to string)    { s.outbox = append(s.outbox, s.templates["welcome"]+to) }
+func (s *Shop) Outbox() []string  { return s.outbox }
+func (s *Shop) Log(msg string)    { s.logs = append(s.logs, msg) }
+func (s *Shop) Fail(msg string)   { s.errors = append(s.errors, msg) }
+
+// Counter has as many fields and methods, but every method works on the
+// same state, so it is one responsibility.
+type Counter struct {
+	a, b, c, d, e, f, g, h int
+}
+
+func (c *Counter) IncA() {
+	c.a++
+	c.b++
+	c.c++
+	c.d++
+	c.e++
+	c.f++
+	c.g++
+	c.h++
+}
+func (c *Counter) IncB() { c.IncA() }
+func (c *Counter) IncC() { c.IncA() }
+func (c *Counter) IncD() { c.IncA() }
+func (c *Counter) IncE() { c.IncA() }
+func (c *Counter) IncF() { c.IncA() }
+func (c *Counter) IncG() { c.IncA() }
+func (c *Counter) IncH() { c.IncA() }
+
+// Small has separate responsibilities but too few methods to matter.
+type Small struct {
+	// Users
+	users []string
+
+	// Orders
+	orders []int
+}
+
+func (s *Small) AddUser(name string) { s.users = append(s.users, name) }
+func (s *Small) PlaceOrder(id int)   { s.orders = append(s.orders, id) }
@@ -0,0 +1,497 @@
+// This is golangexamples/godobject/god_object.go with the analyzer's
+// expectation. Its suggestions cover the twelve services
+// goodexamples/godobject splits it into, plus Database and Logging.
+package godobject
+
+/*
+ANTI-PATTERN: God Object
+
+A struct that knows too much or does too much. It has too many responsibilities
+and becomes a central point that controls or knows about everything in the system.
+Violates the Single Responsibility Principle.
+*/
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// ApplicationManager - GOD OBJECT: This struct does EVERYTHING.
+// It should be split into separate types for each responsibility.
+type ApplicationManager struct { // want `ApplicationManager is a God Object: 54 methods and 38 fields span 14 responsibilities; consider splitting it into: User management, auth, Database, Product management, inventory, Order management, Payment processing, Email service, Logging, Configuration, Cache management, File management, Notification system, Analytics`
+	// User management
+	users          []User
+	currentUser    *User
+	userSessions   map[string]*Session
+	authTokens     map[string]string
+	refreshTokens  map[string]string
+
+	// Database
+	dbConnection   interface{}
+	dbPool         []interface{}
+	dbTransactions []interface{}
+
+	// Product management
+	products         []Product
+	productCategories []string
+	inventory        map[int]int
+
+	// Order management
+	orders       []Order
+	orderHistory []Order
+	orderQueue   []Order
+
+	// Payment processing
+	paymentMethods []string
+	transactions   []Transaction
+	refunds        []Refund
+
+	// Email service
+	emailTemplates map[string]string
+	emailQueue     []Email
+	smtpConfig     map[string]string
+
+	// Logging
+	logs       []string
+	errorLogs  []string
+	auditTrail []string
+
+	// Configuration
+	config       map[string]interface{}
+	featureFlags map[string]bool
+	environment  string
+
+	// Cache management
+	cache       map[string]interface{}
+	cacheExpiry map[string]time.Time
+
+	// File management
+	uploadedFiles    []string
+	fileStoragePath  string
+	maxFileSize      int64
+
+	// Notification system
+	notifications            []Notification
+	notificationPreferences  map[int]NotificationPrefs
+
+	// Analytics
+	pageViews  []PageView
+	userEvents []Event
+
+	// Third-party integrations
+	stripeAPIKey    string
+	awsCredentials  map[string]string
+	sendgridAPIKey  string
+}
+
+// Supporting types
+type User struct {
+	ID       int
+	Username string
+	Email    string
+}
+
+type Session struct {
+	ID        string
+	UserID    int
+	ExpiresAt time.Time
+}
+
+type Product struct {
+	ID    int
+	Name  string
+	Price float64
+}
+
+type Order struct {
+	ID     int
+	UserID int
+	Items  []int
+	Total  float64
+}
+
+type Transaction struct {
+	ID     string
+	Amount float64
+	Status string
+}
+
+type Refund struct {
+	TransactionID string
+	Amount        float64
+}
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Notification struct {
+	UserID  int
+	Message string
+}
+
+type NotificationPrefs struct {
+	Email bool
+	SMS   bool
+	Push  bool
+}
+
+type PageView struct {
+	UserID int
+	Page   string
+	Time   time.Time
+}
+
+type Event struct {
+	UserID int
+	Name   string
+	Data   map[string]interface{}
+}
+
+// NewApplicationManager creates a new god object
+func NewApplicationManager() *ApplicationManager {
+	return &ApplicationManager{
+		users:                   make([]User, 0),
+		userSessions:            make(map[string]*Session),
+		authTokens:              make(map[string]string),
+		refreshTokens:           make(map[string]string),
+		products:                make([]Product, 0),
+		productCategories:       make([]string, 0),
+		inventory:               make(map[int]int),
+		orders:                  make([]Order, 0),
+		orderHistory:            make([]Order, 0),
+		paymentMethods:          make([]string, 0),
+		transactions:            make([]Transaction, 0),
+		emailTemplates:          make(map[string]string),
+		emailQueue:              make([]Email, 0),
+		logs:                    make([]string, 0),
+		errorLogs:               make([]string, 0),
+		config:                  make(map[string]interface{}),
+		featureFlags:            make(map[string]bool),
+		cache:                   make(map[string]interface{}),
+		cacheExpiry:             make(map[string]time.Time),
+		notifications:           make([]Notification, 0),
+		notificationPreferences: make(map[int]NotificationPrefs),
+		pageViews:               make([]PageView, 0),
+		userEvents:              make([]Event, 0),
+	}
+}
+
+// USER MANAGEMENT METHODS - Should be in UserManager
+func (am *ApplicationManager) CreateUser(username, email, password string) (*User, error) {
+	// Should be in UserManager struct
+	return nil, nil
+}
+
+func (am *ApplicationManager) DeleteUser(userID int) error {
+	// Should be in UserManager struct
+	return nil
+}
+
+func (am *ApplicationManager) UpdateUserProfile(userID int, data map[string]interface{}) error {
+	// Should be in UserManager struct
+	return nil
+}
+
+func (am *ApplicationManager) GetUserByID(userID int) (*User, error) {
+	// Should be in UserManager struct
+	return nil, nil
+}
+
+// AUTHENTICATION METHODS - Should be in AuthenticationService
+func (am *ApplicationManager) AuthenticateUser(username, password string) (string, error) {
+	// Should be in AuthenticationService struct
+	return "", nil
+}
+
+func (am *ApplicationManager) LogoutUser(userID int) error {
+	// Should be in AuthenticationService struct
+	return nil
+}
+
+func (am *ApplicationManager) ResetPassword(email string) error {
+	// Should be in AuthenticationService struct
+	return nil
+}
+
+func (am *ApplicationManager) ValidateToken(token string) (bool, error) {
+	// Should be in AuthenticationService struct
+	return false, nil
+}
+
+func (am *ApplicationManager) RefreshAuthToken(refreshToken string) (string, error) {
+	// Should be in AuthenticationService struct
+	return "", nil
+}
+
+// DATABASE METHODS - Should be in DatabaseManager
+func (am *ApplicationManager) ConnectToDatabase() error {
+	// Should be in DatabaseManager struct
+	return nil
+}
+
+func (am *ApplicationManager) ExecuteQuery(query string, args ...interface{}) (interface{}, error) {
+	// Should be in DatabaseManager struct
+	return nil, nil
+}
+
+func (am *ApplicationManager) MigrateDatabase() error {
+	// Should be in DatabaseManager struct
+	return nil
+}
+
+func (am *ApplicationManager) BackupDatabase() error {
+	// Should be in DatabaseManager struct
+	return nil
+}
+
+func (am *ApplicationManager) RollbackTransaction() error {
+	// Should be in DatabaseManager struct
+	return nil
+}
+
+// PRODUCT METHODS - Should be in ProductService
+func (am *ApplicationManager) AddProduct(product Product) error {
+	// Should be in ProductService struct
+	return nil
+}
+
+func (am *ApplicationManager) RemoveProduct(productID int) error {
+	// Should be in ProductService struct
+	return nil
+}
+
+func (am *ApplicationManager) UpdateProductPrice(productID int, newPrice float64) error {
+	// Should be in ProductService struct
+	return nil
+}
+
+func (am *ApplicationManager) SearchProducts(query string) ([]Product, error) {
+	// Should be in ProductService struct
+	return nil, nil
+}
+
+func (am *ApplicationManager) GetProductRecommendations(userID int) ([]Product, error) {
+	// Should be in RecommendationEngine struct
+	return nil, nil
+}
+
+func (am *ApplicationManager) UpdateInventory(productID, quantity int) error {
+	// Should be in InventoryService struct
+	return nil
+}
+
+// ORDER METHODS - Should be in OrderService
+func (am *ApplicationManager) CreateOrder(userID int, items []int) (*Order, error) {
+	// Should be in OrderService struct
+	return nil, nil
+}
+
+func (am *ApplicationManager) CancelOrder(orderID int) error {
+	// Should be in OrderService struct
+	return nil
+}
+
+func (am *ApplicationManager) GetOrderStatus(orderID int) (string, error) {
+	// Should be in OrderService struct
+	return "", nil
+}
+
+func (am *ApplicationManager) CalculateShipping(orderID int) (float64, error) {
+	// Should be in ShippingService struct
+	return 0, nil
+}
+
+func (am *ApplicationManager) TrackOrder(orderID int) (string, error) {
+	// Should be in ShippingService struct
+	return "", nil
+}
+
+// PAYMENT METHODS - Should be in PaymentService
+func (am *ApplicationManager) ProcessPayment(orderID int, paymentMethod string) error {
+	// Should be in PaymentService struct
+	return nil
+}
+
+func (am *ApplicationManager) RefundPayment(transactionID string) error {
+	// Should be in PaymentService struct
+	return nil
+}
+
+func (am *ApplicationManager) ValidateCreditCard(cardNumber string) (bool, error) {
+	// Should be in PaymentService struct
+	return false, nil
+}
+
+func (am *ApplicationManager) GetPaymentHistory(userID int) ([]Transaction, error) {
+	// Should be in PaymentService struct
+	return nil, nil
+}
+
+// EMAIL METHODS - Should be in EmailService
+func (am *ApplicationManager) SendEmail(to, subject, body string) error {
+	// Should be in EmailService struct
+	return nil
+}
+
+func (am *ApplicationManager) SendWelcomeEmail(userID int) error {
+	// Should be in EmailService struct
+	return nil
+}
+
+func (am *ApplicationManager) SendOrderConfirmation(orderID int) error {
+	// Should be in EmailService struct
+	return nil
+}
+
+func (am *ApplicationManager) SendPasswordResetEmail(email string) error {
+	// Should be in EmailService struct
+	return nil
+}
+
+func (am *ApplicationManager) QueueEmail(email Email) error {
+	// Should be in EmailService struct
+	return nil
+}
+
+// LOGGING METHODS - Should be in Logger
+func (am *ApplicationManager) LogInfo(message string) {
+	// Should be in Logger struct
+	am.logs = append(am.logs, fmt.Sprintf("[INFO] %s", message))
+}
+
+func (am *ApplicationManager) LogError(message string) {
+	// Should be in Logger struct
+	am.errorLogs = append(am.errorLogs, fmt.Sprintf("[ERROR] %s", message))
+}
+
+func (am *ApplicationManager) ExportLogs(format string) (string, error) {
+	// Should be in Logger struct
+	return "", nil
+}
+
+func (am *ApplicationManager) ClearLogs() {
+	// Should be in Logger struct
+	am.logs = make([]string, 0)
+}
+
+// CACHE METHODS - Should be in CacheManager
+func (am *ApplicationManager) CacheSet(key string, value interface{}, ttl time.Duration) {
+	// Should be in CacheManager struct
+	am.cache[key] = value
+	am.cacheExpiry[key] = time.Now().Add(ttl)
+}
+
+func (am *ApplicationManager) CacheGet(key string) (interface{}, bool) {
+	// Should be in CacheManager struct
+	value, exists := am.cache[key]
+	return value, exists
+}
+
+func (am *ApplicationManager) CacheInvalidate(key string) {
+	// Should be in CacheManager struct
+	delete(am.cache, key)
+}
+
+func (am *ApplicationManager) CacheClearAll() {
+	// Should be in CacheManager struct
+	am.cache = make(map[string]interface{})
+}
+
+// FILE MANAGEMENT METHODS - Should be in FileStorageService
+func (am *ApplicationManager) UploadFile(file []byte, userID int) (string, error) {
+	// Should be in FileStorageService struct
+	return "", nil
+}
+
+func (am *ApplicationManager) DeleteFile(fileID string) error {
+	// Should be in FileStorageService struct
+	return nil
+}
+
+func (am *ApplicationManager) GetFileURL(fileID string) (string, error) {
+	// Should be in FileStorageService struct
+	return "", nil
+}
+
+// NOTIFICATION METHODS - Should be in NotificationService
+func (am *ApplicationManager) SendNotification(userID int, message string) error {
+	// Should be in NotificationService struct
+	return nil
+}
+
+func (am *ApplicationManager) MarkNotificationRead(notificationID int) error {
+	// Should be in NotificationService struct
+	return nil
+}
+
+func (am *ApplicationManager) GetUnreadNotifications(userID int) ([]Notification, error) {
+	// Should be in NotificationService struct
+	return nil, nil
+}
+
+// ANALYTICS METHODS - Should be in AnalyticsService
+func (am *ApplicationManager) TrackPageView(userID int, page string) {
+	// Should be in AnalyticsService struct
+	am.pageViews = append(am.pageViews, PageView{UserID: userID, Page: page, Time: time.Now()})
+}
+
+func (am *ApplicationManager) TrackEvent(userID int, eventName string, properties map[string]interface{}) {
+	// Should be in AnalyticsService struct
+	am.userEvents = append(am.userEvents, Event{UserID: userID, Name: eventName, Data: properties})
+}
+
+func (am *ApplicationManager) GenerateAnalyticsReport(startDate, endDate time.Time) (string, error) {
+	// Should be in AnalyticsService struct
+	return "", nil
+}
+
+// CONFIGURATION METHODS - Should be in ConfigurationManager
+func (am *ApplicationManager) GetConfig(key string) (interface{}, bool) {
+	// Should be in ConfigurationManager struct
+	value, exists := am.config[key]
+	return value, exists
+}
+
+func (am *ApplicationManager) SetConfig(key string, value interface{}) {
+	// Should be in ConfigurationManager struct
+	am.config[key] = value
+}
+
+func (am *ApplicationManager) IsFeatureEnabled(featureName string) bool {
+	// Should be in FeatureFlagManager struct
+	return am.featureFlags[featureName]
+}
+
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
+	/*
+		This God Object violates:
+		- Single Responsibility Principle (it has dozens of responsibilities)
+		- Open/Closed Principle (any change requires modifying this massive struct)
+		- Interface Segregation Principle (users depend on methods they don't use)
+
+		It should be refactored into separate structs:
+		- UserManager
+		- AuthenticationService
+		- DatabaseManager
+		- ProductService
+		- OrderService
+		- PaymentService
+		- EmailService
+		- Logger
+		- CacheManager
+		- FileStorageService
+		- NotificationService
+		- AnalyticsService
+		- ConfigurationManager
+		- Various integration services
+	*/
+
+	am := NewApplicationManager()
+	fmt.Fprintf(w, "God object created with %d responsibilities\n", 15)
+	_ = am
+}
@@ -0,0 +1,450 @@
+// Package goldenhammer defines an Analyzer that reports familiar tools
+// applied to problems they do not fit: regular expressions doing
+// arithmetic, goroutines that are waited for at once, reflection standing
+// in for a type assertion, and pointers to scalars returned for no reason.
+package goldenhammer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+)
+
+const Doc = `report the golden hammer: a favourite tool used where it does not fit
+
+The goldenhammer analyzer reports:
+
+  - regexp functions and methods applied to the decimal text of a number,
+    as in testing evenness with ^-?\d*[02468]$ (regexp)
+  - goroutines whose result is received from an unbuffered channel by the
+    very next statement, which is a function call with extra steps (await)
+  - goroutines that do nothing but print, which may be lost when the
+    program exits and are printed in no particular order (print)
+  - fmt.Sprintf("%T", v) or reflect.TypeOf(v).String() compared with a
+    type name instead of a type assertion or type switch (typename)
+  - functions returning a pointer to a bool, number or string that exists
+    only to be returned (scalarptr)
+
+Each check can be turned off with its flag, e.g. -await=false.`
+
+// Analyzer reports golden hammers.
+var Analyzer = &analysis.Analyzer{
+	Name:     "goldenhammer",
+	Doc:      Doc,
+	URL:      "https://github.com/bclements/antipatterns/tree/main/analyzers/goldenhammer",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// Categories of the diagnostics, each named after the flag that enables it.
+const (
+	CategoryRegexp    = "regexp"
+	CategoryAwait     = "await"
+	CategoryPrint     = "print"
+	CategoryTypeName  = "typename"
+	CategoryScalarPtr = "scalarptr"
+)
+
+var enabled = map[string]*bool{
+	CategoryRegexp:    new(bool),
+	CategoryAwait:     new(bool),
+	CategoryPrint:     new(bool),
+	CategoryTypeName:  new(bool),
+	CategoryScalarPtr: new(bool),
+}
+
+func init() {
+	Analyzer.Flags.BoolVar(enabled[CategoryRegexp], CategoryRegexp, true, "report regular expressions applied to formatted numbers")
+	Analyzer.Flags.BoolVar(enabled[CategoryAwait], CategoryAwait, true, "report goroutines awaited at once on an unbuffered channel")
+	Analyzer.Flags.BoolVar(enabled[CategoryPrint], CategoryPrint, true, "report goroutines that only print")
+	Analyzer.Flags.BoolVar(enabled[CategoryTypeName], CategoryTypeName, true, "report type checks made by comparing type names as strings")
+	Analyzer.Flags.BoolVar(enabled[CategoryScalarPtr], CategoryScalarPtr, true, "report functions returning pointers to scalars that exist only to be returned")
+}
+
+type checker struct {
+	pass *analysis.Pass
+	defs map[types.Object]ast.Expr // the initial value of each local "x := e"
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	c := &checker{pass: pass, defs: make(map[types.Object]ast.Expr)}
+	inspect.Preorder([]ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					if obj := pass.TypesInfo.Defs[lhs.(*ast.Ident)]; obj != nil {
+						c.defs[obj] = n.Rhs[i]
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) == len(n.Values) {
+				for i, id := range n.Names {
+					if obj := pass.TypesInfo.Defs[id]; obj != nil && obj.Parent() != pass.Pkg.Scope() {
+						c.defs[obj] = n.Values[i]
+					}
+				}
+			}
+		}
+	})
+
+	nodes := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.GoStmt)(nil),
+		(*ast.BinaryExpr)(nil),
+		(*ast.BlockStmt)(nil),
+		(*ast.CaseClause)(nil),
+		(*ast.CommClause)(nil),
+		(*ast.FuncDecl)(nil),
+	}
+	inspect.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			c.regexp(n)
+		case *ast.GoStmt:
+			c.print(n)
+		case *ast.BinaryExpr:
+			c.typeName(n)
+		case *ast.BlockStmt:
+			c.await(n.List)
+		case *ast.CaseClause:
+			c.await(n.Body)
+		case *ast.CommClause:
+			c.await(n.Body)
+		case *ast.FuncDecl:
+			c.scalarPtr(n)
+		}
+	})
+	return nil, nil
+}
+
+func (c *checker) report(n ast.Node, category, msg string) {
+	if !*enabled[category] {
+		return
+	}
+	c.pass.Report(analysis.Diagnostic{
+		Pos:      n.Pos(),
+		End:      n.End(),
+		Category: category,
+		Message:  msg,
+	})
+}
+
+// regexp reports a regexp function or method given the decimal text of a
+// number to match, search or rewrite.
+func (c *checker) regexp(call *ast.CallExpr) {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "regexp" {
+		return
+	}
+	for _, arg := range call.Args {
+		if c.isNumberText(arg, 0) {
+			name := "regexp." + fn.Name()
+			if recv := fn.Signature().Recv(); recv != nil {
+				name = "(*regexp.Regexp)." + fn.Name()
+			}
+			c.report(call, CategoryRegexp, name+" is applied to a number formatted as text; use arithmetic on the number, e.g. n%2 == 0 or a + b")
+			return
+		}
+	}
+}
+
+// isNumberText reports whether e is a string made only from formatting
+// numbers: strconv.Itoa(n), fmt.Sprintf("%d%d", a, b), or a local variable
+// initialized to one of those.
+func (c *checker) isNumberText(e ast.Expr, depth int) bool {
+	e = ast.Unparen(e)
+	if id, ok := e.(*ast.Ident); ok && depth < 4 {
+		init, ok := c.defs[c.pass.TypesInfo.Uses[id]]
+		return ok && c.isNumberText(init, depth+1)
+	}
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	switch {
+	case c.isCall(call, "strconv", "Itoa", "FormatInt", "FormatUint", "FormatFloat"):
+		return true
+	case c.isCall(call, "fmt", "Sprint"):
+		return len(call.Args) > 0 && c.allNumbers(call.Args)
+	case c.isCall(call, "fmt", "Sprintf"):
+		if len(call.Args) < 2 || !c.allNumbers(call.Args[1:]) {
+			return false
+		}
+		format := c.stringValue(call.Args[0])
+		for _, verb := range []string{"%d", "%v", "%f", "%g"} {
+			format = strings.ReplaceAll(format, verb, "")
+		}
+		return format == ""
+	}
+	return false
+}
+
+func (c *checker) allNumbers(args []ast.Expr) bool {
+	for _, arg := range args {
+		b, ok := c.pass.TypesInfo.TypeOf(arg).Underlying().(*types.Basic)
+		if !ok || b.Info()&types.IsNumeric == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// print reports a go statement whose goroutine makes a single call to a
+// print function.
+func (c *checker) print(gs *ast.GoStmt) {
+	call := gs.Call
+	if lit, ok := ast.Unparen(call.Fun).(*ast.FuncLit); ok {
+		if len(lit.Body.List) != 1 {
+			return
+		}
+		es, ok := lit.Body.List[0].(*ast.ExprStmt)
+		if !ok {
+			return
+		}
+		if call, ok = ast.Unparen(es.X).(*ast.CallExpr); !ok {
+			return
+		}
+	}
+	if c.isCall(call, "fmt", "Print", "Printf", "Println", "Fprint", "Fprintf", "Fprintln") ||
+		c.isCall(call, "log", "Print", "Printf", "Println") ||
+		c.isBuiltin(call.Fun, "print") || c.isBuiltin(call.Fun, "println") {
+		c.report(gs, CategoryPrint, "goroutine only prints; its output may come out of order or not at all, so print directly")
+	}
+}
+
+// await reports a go statement followed at once by a receive from an
+// unbuffered channel made in the same function, which blocks until the
+// goroutine has finished its work.
+func (c *checker) await(list []ast.Stmt) {
+	for i, s := range list[:max(0, len(list)-1)] {
+		gs, ok := s.(*ast.GoStmt)
+		if !ok {
+			continue
+		}
+		recv := received(list[i+1])
+		if recv == nil {
+			continue
+		}
+		id, ok := ast.Unparen(recv).(*ast.Ident)
+		if !ok {
+			continue
+		}
+		obj := c.pass.TypesInfo.Uses[id]
+		if !c.isUnbufferedChan(c.defs[obj]) || !uses(c.pass.TypesInfo, gs, obj) {
+			continue
+		}
+		c.report(gs, CategoryAwait, "goroutine is awaited at once on unbuffered channel "+id.Name+"; call the code directly")
+	}
+}
+
+// received returns the channel that s begins by receiving from, as in
+// "<-ch", "x := <-ch" or "return <-ch".
+func received(s ast.Stmt) ast.Expr {
+	var e ast.Expr
+	switch s := s.(type) {
+	case *ast.ExprStmt:
+		e = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			e = s.Rhs[0]
+		}
+	case *ast.ReturnStmt:
+		if len(s.Results) == 1 {
+			e = s.Results[0]
+		}
+	}
+	if u, ok := ast.Unparen(e).(*ast.UnaryExpr); ok && u.Op == token.ARROW {
+		return u.X
+	}
+	return nil
+}
+
+// isUnbufferedChan reports whether e is make(chan T) or make(chan T, 0).
+func (c *checker) isUnbufferedChan(e ast.Expr) bool {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || !c.isBuiltin(call.Fun, "make") {
+		return false
+	}
+	if _, ok := c.pass.TypesInfo.TypeOf(call).Underlying().(*types.Chan); !ok {
+		return false
+	}
+	if len(call.Args) == 1 {
+		return true
+	}
+	tv := c.pass.TypesInfo.Types[call.Args[1]]
+	return tv.Value != nil && constant.Sign(tv.Value) == 0
+}
+
+// uses reports whether n refers to obj.
+func uses(info *types.Info, n ast.Node, obj types.Object) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// typeName reports fmt.Sprintf("%T", v) == "name" and
+// reflect.TypeOf(v).String() == "name".
+func (c *checker) typeName(be *ast.BinaryExpr) {
+	if be.Op != token.EQL && be.Op != token.NEQ {
+		return
+	}
+	x, lit := be.X, be.Y
+	name := c.stringValue(lit)
+	if name == "" {
+		x, lit = be.Y, be.X
+		name = c.stringValue(lit)
+	}
+	if name == "" {
+		return
+	}
+	call, ok := ast.Unparen(x).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	var v ast.Expr
+	switch {
+	case c.isCall(call, "fmt", "Sprintf") && len(call.Args) == 2 && c.stringValue(call.Args[0]) == "%T":
+		v = call.Args[1]
+	case c.isCall(call, "reflect", "String") && len(call.Args) == 0:
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		inner, ok := ast.Unparen(sel.X).(*ast.CallExpr)
+		if !ok || !c.isCall(inner, "reflect", "TypeOf") || len(inner.Args) != 1 {
+			return
+		}
+		v = inner.Args[0]
+	default:
+		return
+	}
+	c.report(be, CategoryTypeName, "type check by comparing the type's name with "+types.ExprString(lit)+
+		"; use a type assertion such as _, ok := "+types.ExprString(v)+".("+name+") or a type switch")
+}
+
+// scalarPtr reports a function with a single pointer-to-scalar result
+// whose every return statement returns the address of a local variable
+// that is used for nothing else.
+func (c *checker) scalarPtr(fd *ast.FuncDecl) {
+	if fd.Body == nil {
+		return
+	}
+	fn, ok := c.pass.TypesInfo.Defs[fd.Name].(*types.Func)
+	if !ok || fn.Signature().Results().Len() != 1 {
+		return
+	}
+	ptr, ok := fn.Signature().Results().At(0).Type().(*types.Pointer)
+	if !ok {
+		return
+	}
+	elem, ok := ptr.Elem().(*types.Basic)
+	if !ok || elem.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) == 0 {
+		return
+	}
+
+	// Every return must be &v for a variable v declared in the body.
+	returned := make(map[ast.Expr]bool) // the &v operands of the returns
+	locals := make(map[types.Object]bool)
+	ok = true
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				ok = false
+				return false
+			}
+			u, isAddr := ast.Unparen(n.Results[0]).(*ast.UnaryExpr)
+			if !isAddr || u.Op != token.AND {
+				ok = false
+				return false
+			}
+			id, isIdent := ast.Unparen(u.X).(*ast.Ident)
+			obj, isVar := c.pass.TypesInfo.Uses[id].(*types.Var)
+			if !isIdent || !isVar || obj.Pos() < fd.Body.Pos() || obj.Pos() >= fd.Body.End() {
+				ok = false
+				return false
+			}
+			returned[u] = true
+			locals[obj] = true
+		}
+		return true
+	})
+	if !ok || len(locals) == 0 {
+		return
+	}
+
+	// The variables' addresses must not be taken elsewhere, nor may a
+	// closure capture them.
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			for obj := range locals {
+				if uses(c.pass.TypesInfo, n, obj) {
+					ok = false
+				}
+			}
+			return false
+		case *ast.UnaryExpr:
+			if id, isIdent := ast.Unparen(n.X).(*ast.Ident); isIdent && n.Op == token.AND && !returned[n] && locals[c.pass.TypesInfo.Uses[id]] {
+				ok = false
+			}
+		}
+		return ok
+	})
+	if !ok {
+		return
+	}
+	c.report(fd.Name, CategoryScalarPtr, astutil.FuncName(fd)+" returns *"+elem.Name()+", the address of a local that exists only to be returned; return the "+elem.Name()+" itself")
+}
+
+// isCall reports whether call calls one of the named functions or methods
+// of the package with the given path.
+func (c *checker) isCall(call *ast.CallExpr, pkg string, names ...string) bool {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkg {
+		return false
+	}
+	for _, name := range names {
+		if fn.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) isBuiltin(fun ast.Expr, name string) bool {
+	id, ok := ast.Unparen(fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := c.pass.TypesInfo.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// stringValue returns the value of a constant string expression, or "".
+func (c *checker) stringValue(e ast.Expr) string {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
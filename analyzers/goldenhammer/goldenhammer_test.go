@@ -0,0 +1,21 @@
+package goldenhammer_test
+
+import (
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/goldenhammer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), goldenhammer.Analyzer, "hammer")
+}
+
+func TestDisabled(t *testing.T) {
+	flags := goldenhammer.Analyzer.Flags
+	if err := flags.Set(goldenhammer.CategoryAwait, "false"); err != nil {
+		t.Fatal(err)
+	}
+	defer flags.Set(goldenhammer.CategoryAwait, "true")
+	analysistest.Run(t, analysistest.TestData(), goldenhammer.Analyzer, "noawait")
+}
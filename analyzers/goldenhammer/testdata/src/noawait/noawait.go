@@ -0,0 +1,14 @@
+package noawait
+
+// With -await=false, a goroutine awaited at once is not reported.
+func square(n int) int {
+	ch := make(chan int)
+	go func() { ch <- n * n }()
+	return <-ch
+}
+
+// The other checks still run.
+func answer() *int { // want `answer returns \*int, the address of a local that exists only to be returned; return the int itself`
+	n := 42
+	return &n
+}
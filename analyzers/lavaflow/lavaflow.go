@@ -0,0 +1,366 @@
+// Package lavaflow defines an Analyzer that digs up Lava Flow: code kept
+// alive by old TODOs, warnings not to touch it, and configuration switches
+// that nobody ever turns on.
+//
+// The analyzer's result lists every marker comment it finds, with the years
+// mentioned around it, so that the lavaflow command can join them with git
+// blame and rank the whole package.
+package lavaflow
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+)
+
+const Doc = `report dated TODO-style comments and boolean switches that are never turned on
+
+The lavaflow analyzer reports:
+
+  - TODO, FIXME, HACK, XXX, NOTE, WARNING and DEPRECATED comments, and
+    warnings such as "DO NOT REMOVE" or "don't touch", whose comment
+    mentions a year at least -min-age years ago, or that forbid removal
+    outright (marker)
+  - unexported bool struct fields that the package only ever sets to false,
+    together with the if statements they guard, whose bodies therefore
+    never run (stale-flag)`
+
+// Analyzer reports lava flow. Its result is a *Result.
+var Analyzer = &analysis.Analyzer{
+	Name:       "lavaflow",
+	Doc:        Doc,
+	URL:        "https://github.com/bclements/antipatterns/tree/main/analyzers/lavaflow",
+	Run:        run,
+	ResultType: reflect.TypeOf((*Result)(nil)),
+}
+
+var minAge int
+
+func init() {
+	Analyzer.Flags.IntVar(&minAge, "min-age", 3, "report marker comments mentioning a year at least this many years ago")
+}
+
+// Categories of the diagnostics.
+const (
+	CategoryMarker    = "marker"
+	CategoryStaleFlag = "stale-flag"
+)
+
+// Kinds of marker comment, from least to most alarming.
+const (
+	KindNote       = "NOTE"
+	KindTODO       = "TODO"
+	KindDeprecated = "DEPRECATED"
+	KindDoNotTouch = "DO NOT REMOVE"
+)
+
+// Result is everything the analyzer found in a package, reported or not.
+type Result struct {
+	Markers []*Marker
+	Flags   []*Flag
+}
+
+// A Marker is one line of a comment carrying a marker such as TODO.
+type Marker struct {
+	Kind  string // one of the Kind constants
+	Tag   string // the marker as written, e.g. "FIXME" or "Don't touch"
+	Text  string // the whole comment line, trimmed
+	Years []int  // years mentioned anywhere in the comment, ascending
+	Decl  string // the enclosing top-level declaration, if any
+	Pos   token.Pos
+}
+
+// A Flag is a bool field that is only ever assigned false.
+type Flag struct {
+	Name   string // e.g. "DataProcessor.legacyMode"
+	Pos    token.Pos
+	Guards []*Guard
+}
+
+// A Guard is an if statement whose body only runs when a Flag is set.
+type Guard struct {
+	Pos   token.Pos
+	Calls []string // functions called in the dead body, e.g. "dp.legacyProcess"
+}
+
+var (
+	markers = []struct {
+		kind string
+		re   *regexp.Regexp
+	}{
+		{KindDoNotTouch, regexp.MustCompile(`(?i)\b(?:do not|don't|never) (?:remove|delete|modify|touch|change)\b`)},
+		{KindDeprecated, regexp.MustCompile(`\bDEPRECATED\b|^Deprecated:`)},
+		{KindTODO, regexp.MustCompile(`\b(?:TODO|FIXME|HACK|XXX)\b`)},
+		{KindNote, regexp.MustCompile(`\b(?:NOTE|WARNING)\b|\b(?:Note|Warning):`)},
+	}
+	yearRE = regexp.MustCompile(`\b(?:19[7-9]\d|20\d\d)\b`)
+)
+
+func run(pass *analysis.Pass) (any, error) {
+	res := new(Result)
+	thisYear := time.Now().Year()
+	for _, f := range pass.Files {
+		for _, cg := range f.Comments {
+			for _, m := range findMarkers(cg) {
+				if d := astutil.EnclosingDecl(f, m.Pos); d != nil {
+					m.Decl = astutil.DeclName(d)
+				}
+				res.Markers = append(res.Markers, m)
+
+				oldest := 0
+				if len(m.Years) > 0 {
+					oldest = m.Years[0]
+				}
+				switch {
+				case oldest > 0 && thisYear-oldest >= minAge:
+					pass.Report(analysis.Diagnostic{
+						Pos:      m.Pos,
+						Category: CategoryMarker,
+						Message:  fmt.Sprintf("%s comment refers to %d, %d years ago: %s", m.Kind, oldest, thisYear-oldest, m.Text),
+					})
+				case m.Kind == KindDoNotTouch || m.Kind == KindDeprecated:
+					pass.Report(analysis.Diagnostic{
+						Pos:      m.Pos,
+						Category: CategoryMarker,
+						Message:  fmt.Sprintf("%s comment keeps code alive without saying why: %s", m.Kind, m.Text),
+					})
+				}
+			}
+		}
+	}
+
+	res.Flags = staleFlags(pass)
+	for _, fl := range res.Flags {
+		var related []analysis.RelatedInformation
+		for _, g := range fl.Guards {
+			msg := "never runs"
+			if len(g.Calls) > 0 {
+				msg += "; calls " + strings.Join(g.Calls, ", ")
+			}
+			related = append(related, analysis.RelatedInformation{Pos: g.Pos, Message: msg})
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      fl.Pos,
+			Category: CategoryStaleFlag,
+			Message:  fmt.Sprintf("%s is only ever set to false%s", fl.Name, guarded(len(fl.Guards))),
+			Related:  related,
+		})
+	}
+	return res, nil
+}
+
+// findMarkers returns the marker lines of a comment group, the most
+// alarming marker winning when a line has several.
+func findMarkers(cg *ast.CommentGroup) []*Marker {
+	var years []int
+	seen := make(map[int]bool)
+	for _, y := range yearRE.FindAllString(cg.Text(), -1) {
+		n, _ := strconv.Atoi(y)
+		if !seen[n] {
+			seen[n] = true
+			years = append(years, n)
+		}
+	}
+	sort.Ints(years)
+
+	var out []*Marker
+	for _, c := range cg.List {
+		offset := 0
+		for _, line := range strings.SplitAfter(c.Text, "\n") {
+			pos := c.Slash + token.Pos(offset)
+			offset += len(line)
+			text := strings.TrimSpace(line)
+			text = strings.TrimPrefix(text, "//")
+			text = strings.TrimPrefix(text, "/*")
+			text = strings.TrimSpace(strings.TrimSuffix(text, "*/"))
+			for _, mk := range markers {
+				loc := mk.re.FindStringIndex(text)
+				if loc == nil {
+					continue
+				}
+				out = append(out, &Marker{
+					Kind:  mk.kind,
+					Tag:   text[loc[0]:loc[1]],
+					Text:  text,
+					Years: years,
+					Pos:   pos + token.Pos(len(line)-len(strings.TrimLeft(line, " \t"))),
+				})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// staleFlags finds the unexported bool fields of the package's structs that
+// are assigned somewhere but never to anything other than false.
+func staleFlags(pass *analysis.Pass) []*Flag {
+	candidates := make(map[*types.Var]*Flag)
+	var order []*types.Var
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			for _, field := range st.Fields.List {
+				for _, name := range field.Names {
+					v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+					if !ok || v.Exported() || !types.Identical(v.Type(), types.Typ[types.Bool]) {
+						continue
+					}
+					candidates[v] = &Flag{Name: ts.Name.Name + "." + name.Name, Pos: name.Pos()}
+					order = append(order, v)
+				}
+			}
+			return false
+		})
+	}
+
+	assigned := make(map[*types.Var]bool)
+	set := make(map[*types.Var]bool) // assigned something other than false
+	assign := func(lhs, rhs ast.Expr) {
+		v := fieldOf(pass.TypesInfo, lhs)
+		if candidates[v] == nil {
+			return
+		}
+		assigned[v] = true
+		if rhs == nil || !isFalse(pass.TypesInfo, rhs) {
+			set[v] = true
+		}
+	}
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					var rhs ast.Expr
+					if len(n.Rhs) == len(n.Lhs) {
+						rhs = n.Rhs[i]
+					}
+					assign(lhs, rhs)
+				}
+			case *ast.KeyValueExpr:
+				if id, ok := n.Key.(*ast.Ident); ok {
+					assign(id, n.Value)
+				}
+			case *ast.CompositeLit:
+				st, ok := pass.TypesInfo.TypeOf(n).Underlying().(*types.Struct)
+				if !ok {
+					break
+				}
+				for i, elt := range n.Elts {
+					if _, keyed := elt.(*ast.KeyValueExpr); !keyed && i < st.NumFields() {
+						if candidates[st.Field(i)] != nil {
+							assigned[st.Field(i)] = true
+							if !isFalse(pass.TypesInfo, elt) {
+								set[st.Field(i)] = true
+							}
+						}
+					}
+				}
+			case *ast.UnaryExpr:
+				// A field whose address is taken may be set through it.
+				if n.Op == token.AND {
+					if v := fieldOf(pass.TypesInfo, n.X); candidates[v] != nil {
+						set[v] = true
+					}
+				}
+			case *ast.IfStmt:
+				for _, operand := range conjuncts(n.Cond) {
+					if v := fieldOf(pass.TypesInfo, operand); candidates[v] != nil {
+						candidates[v].Guards = append(candidates[v].Guards, &Guard{
+							Pos:   n.Pos(),
+							Calls: calls(n.Body),
+						})
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	var flags []*Flag
+	for _, v := range order {
+		if assigned[v] && !set[v] {
+			flags = append(flags, candidates[v])
+		}
+	}
+	return flags
+}
+
+// fieldOf returns the field that e, a selector or a composite literal key,
+// denotes, or nil.
+func fieldOf(info *types.Info, e ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(e).(type) {
+	case *ast.SelectorExpr:
+		id = e.Sel
+	case *ast.Ident:
+		id = e
+	default:
+		return nil
+	}
+	v, ok := info.Uses[id].(*types.Var)
+	if !ok || !v.IsField() {
+		return nil
+	}
+	return v
+}
+
+func isFalse(info *types.Info, e ast.Expr) bool {
+	tv, ok := info.Types[e]
+	return ok && tv.Value != nil && tv.Value.Kind() == constant.Bool && !constant.BoolVal(tv.Value)
+}
+
+// conjuncts splits a && b && c into its operands.
+func conjuncts(e ast.Expr) []ast.Expr {
+	e = ast.Unparen(e)
+	if b, ok := e.(*ast.BinaryExpr); ok && b.Op == token.LAND {
+		return append(conjuncts(b.X), conjuncts(b.Y)...)
+	}
+	return []ast.Expr{e}
+}
+
+// calls lists the functions called in n, in order, without repeats.
+func calls(n ast.Node) []string {
+	var out []string
+	seen := make(map[string]bool)
+	ast.Inspect(n, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			name := types.ExprString(call.Fun)
+			if !seen[name] {
+				seen[name] = true
+				out = append(out, name)
+			}
+		}
+		return true
+	})
+	return out
+}
+
+func guarded(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return ", so the branch it guards never runs"
+	}
+	return fmt.Sprintf(", so the %d branches it guards never run", n)
+}
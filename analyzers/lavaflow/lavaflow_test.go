@@ -0,0 +1,41 @@
+package lavaflow_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/lavaflow"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), lavaflow.Analyzer, "a")
+	res := results[0].Result.(*lavaflow.Result)
+
+	// Every marker is in the result, reported or not.
+	var kinds []string
+	for _, m := range res.Markers {
+		kinds = append(kinds, m.Kind+" "+m.Decl)
+	}
+	want := []string{
+		"TODO importOld",
+		"DO NOT REMOVE billingHook",
+		"DEPRECATED importLegacy",
+		"TODO importNew",
+		"NOTE importFuture",
+	}
+	if !slices.Equal(kinds, want) {
+		t.Errorf("markers %q, want %q", kinds, want)
+	}
+
+	if len(res.Flags) != 1 {
+		t.Fatalf("%d stale flags, want 1", len(res.Flags))
+	}
+	var calls []string
+	for _, g := range res.Flags[0].Guards {
+		calls = append(calls, g.Calls...)
+	}
+	if want := []string{"importLegacy", "billingHook"}; !slices.Equal(calls, want) {
+		t.Errorf("guarded calls %q, want %q", calls, want)
+	}
+}
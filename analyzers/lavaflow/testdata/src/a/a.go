@@ -0,0 +1,40 @@
+package a
+
+import "fmt"
+
+// TODO: drop once the 2015 importer is gone // want `TODO comment refers to 2015, \d+ years ago: TODO: drop`
+func importOld() {}
+
+// DO NOT REMOVE: billing calls this // want `DO NOT REMOVE comment keeps code alive without saying why`
+func billingHook() {}
+
+// Deprecated: use importNew. // want `DEPRECATED comment keeps code alive without saying why`
+func importLegacy() {}
+
+// TODO: add retries to importNew.
+func importNew() {}
+
+// NOTE: the 2099 schema is not out yet, so nothing here is old.
+func importFuture() {}
+
+type Processor struct {
+	legacyMode bool // want `Processor.legacyMode is only ever set to false, so the 2 branches it guards never run`
+	verbose    bool
+}
+
+func NewProcessor(verbose bool) *Processor {
+	return &Processor{legacyMode: false, verbose: verbose}
+}
+
+func (p *Processor) Run() {
+	if p.legacyMode {
+		importLegacy()
+	}
+	if p.verbose {
+		fmt.Println("running")
+	}
+	importNew()
+	if p.legacyMode {
+		billingHook()
+	}
+}
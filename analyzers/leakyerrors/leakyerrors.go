@@ -0,0 +1,391 @@
+// Package leakyerrors defines an Analyzer that reports callers holding an
+// interface that compare errors with == against sentinels belonging to one
+// concrete implementation of it, so that the interface leaks what it was
+// meant to hide.
+package leakyerrors
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const Doc = `report comparisons with another implementation's sentinel errors through an interface
+
+The leakyerrors analyzer finds sentinel errors, package-level variables
+initialized with errors.New or fmt.Errorf, that are returned by the methods
+of exactly one concrete type, together with the sentinels declared beside
+them in the same var block. Such a sentinel belongs to that implementation;
+io.EOF, returned by many readers, belongs to nobody in particular.
+
+It then reports code that calls a method through an interface and
+compares the error it gets with such a sentinel, with == or != or in a
+switch. The caller only holds the interface, yet must know which
+implementation sits behind it, and the comparison fails as soon as the
+implementation wraps its errors. The diagnostic suggests errors.Is, with a
+fix when the file imports errors, and names an implementation-neutral
+error type with an Unwrap method, such as a CacheError for a Cache, for the
+implementation to wrap its errors in.`
+
+// Analyzer reports leaky sentinel errors.
+var Analyzer = &analysis.Analyzer{
+	Name:      "leakyerrors",
+	Doc:       Doc,
+	URL:       "https://github.com/bclements/antipatterns/tree/main/analyzers/leakyerrors",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(ownerFact)},
+}
+
+// CategorySentinel is the category of every diagnostic.
+const CategorySentinel = "sentinel"
+
+// An ownerFact marks a sentinel error variable as belonging to the
+// concrete type whose methods return it.
+type ownerFact struct {
+	Type string // the name of the type, declared in the sentinel's package
+}
+
+func (*ownerFact) AFact() {}
+
+func (f *ownerFact) String() string { return "sentinel of " + f.Type }
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	exportOwners(pass, inspect)
+
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fd := n.(*ast.FuncDecl)
+		if fd.Body == nil {
+			return
+		}
+		sources := errorSources(pass.TypesInfo, fd.Body)
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BinaryExpr:
+				if n.Op != token.EQL && n.Op != token.NEQ {
+					break
+				}
+				if !check(pass, n, n.X, n.Y, sources) {
+					check(pass, n, n.Y, n.X, sources)
+				}
+			case *ast.SwitchStmt:
+				if n.Tag == nil {
+					break
+				}
+				for _, s := range n.Body.List {
+					for _, e := range s.(*ast.CaseClause).List {
+						check(pass, nil, n.Tag, e, sources)
+					}
+				}
+			}
+			return true
+		})
+	})
+	return nil, nil
+}
+
+// exportOwners finds the package's sentinel errors that belong to a
+// single concrete type and records them as facts.
+func exportOwners(pass *analysis.Pass, inspect *inspector.Inspector) {
+	sentinels := make(map[*types.Var]*ast.GenDecl)
+	inspect.Preorder([]ast.Node{(*ast.GenDecl)(nil)}, func(n ast.Node) {
+		gd := n.(*ast.GenDecl)
+		if gd.Tok != token.VAR {
+			return
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Names) != len(vs.Values) {
+				continue
+			}
+			for i, id := range vs.Names {
+				v, ok := pass.TypesInfo.Defs[id].(*types.Var)
+				if !ok || v.Parent() != pass.Pkg.Scope() || !isError(v.Type()) {
+					continue
+				}
+				call, ok := ast.Unparen(vs.Values[i]).(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				if fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func); ok && fn.Pkg() != nil &&
+					(fn.Pkg().Path() == "errors" && fn.Name() == "New" || fn.Pkg().Path() == "fmt" && fn.Name() == "Errorf") {
+					sentinels[v] = gd
+				}
+			}
+		}
+	})
+	if len(sentinels) == 0 {
+		return
+	}
+
+	// owners[v] is the set of concrete types whose methods return v.
+	owners := make(map[*types.Var]map[*types.TypeName]bool)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fd := n.(*ast.FuncDecl)
+		fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		if !ok || fd.Body == nil || fn.Signature().Recv() == nil {
+			return
+		}
+		recv := fn.Signature().Recv().Type()
+		if p, ok := recv.(*types.Pointer); ok {
+			recv = p.Elem()
+		}
+		named, ok := types.Unalias(recv).(*types.Named)
+		if !ok || types.IsInterface(named) {
+			return
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			ret, ok := n.(*ast.ReturnStmt)
+			if !ok {
+				return true
+			}
+			for _, r := range ret.Results {
+				if v := sentinelOf(pass.TypesInfo, r); sentinels[v] != nil {
+					if owners[v] == nil {
+						owners[v] = make(map[*types.TypeName]bool)
+					}
+					owners[v][named.Obj()] = true
+				}
+			}
+			return true
+		})
+	})
+
+	// A sentinel declared in the same block as one owned by a type
+	// belongs to it too, unless the block is shared between types.
+	blockOwners := make(map[*ast.GenDecl]map[*types.TypeName]bool)
+	for v, ts := range owners {
+		gd := sentinels[v]
+		if blockOwners[gd] == nil {
+			blockOwners[gd] = make(map[*types.TypeName]bool)
+		}
+		for t := range ts {
+			blockOwners[gd][t] = true
+		}
+	}
+	for v, gd := range sentinels {
+		ts := owners[v]
+		if len(ts) == 0 {
+			ts = blockOwners[gd]
+		}
+		if len(ts) != 1 {
+			continue
+		}
+		for t := range ts {
+			pass.ExportObjectFact(v, &ownerFact{Type: t.Name()})
+		}
+	}
+}
+
+// errorSources maps each error variable of body to the expressions it is
+// assigned from.
+func errorSources(info *types.Info, body *ast.BlockStmt) map[types.Object][]ast.Expr {
+	sources := make(map[types.Object][]ast.Expr)
+	add := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			id, ok := ast.Unparen(l).(*ast.Ident)
+			if !ok {
+				continue
+			}
+			obj := info.ObjectOf(id)
+			if obj == nil || !isError(obj.Type()) {
+				continue
+			}
+			switch {
+			case len(rhs) == len(lhs):
+				sources[obj] = append(sources[obj], rhs[i])
+			case len(rhs) == 1:
+				sources[obj] = append(sources[obj], rhs[0])
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			add(n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			names := make([]ast.Expr, len(n.Names))
+			for i, id := range n.Names {
+				names[i] = id
+			}
+			add(names, n.Values)
+		}
+		return true
+	})
+	return sources
+}
+
+// check reports the comparison of err, an error obtained through an
+// interface, with sentinel, if sentinel belongs to an implementation of
+// that interface. be is the comparison, or nil for a switch case. check
+// reports whether sentinel is such a sentinel.
+func check(pass *analysis.Pass, be *ast.BinaryExpr, err, sentinel ast.Expr, sources map[types.Object][]ast.Expr) bool {
+	v := sentinelOf(pass.TypesInfo, sentinel)
+	var fact ownerFact
+	if v == nil || !pass.ImportObjectFact(v, &fact) {
+		return false
+	}
+	exprs := []ast.Expr{err}
+	if id, ok := ast.Unparen(err).(*ast.Ident); ok {
+		exprs = sources[pass.TypesInfo.Uses[id]]
+	}
+	for _, e := range exprs {
+		iface, method := interfaceCall(pass.TypesInfo, e)
+		if iface == nil || !implements(v.Pkg(), fact.Type, iface) {
+			continue
+		}
+		ifaceName := types.TypeString(iface, qualifier(pass.Pkg))
+		msg := fmt.Sprintf("%s from %s.%s is compared with %s, a sentinel of the %s implementation; use errors.Is, and %s",
+			types.ExprString(err), ifaceName, method, types.ExprString(sentinel), fact.Type, wrapAdvice(pass, iface, fact.Type))
+		diag := analysis.Diagnostic{
+			Pos:      sentinel.Pos(),
+			End:      sentinel.End(),
+			Category: CategorySentinel,
+			Message:  msg,
+		}
+		if be != nil {
+			diag.Pos, diag.End = be.Pos(), be.End()
+			if name, ok := errorsImport(pass, be.Pos()); ok {
+				call := fmt.Sprintf("%s.Is(%s, %s)", name, types.ExprString(err), types.ExprString(sentinel))
+				if be.Op == token.NEQ {
+					call = "!" + call
+				}
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Use errors.Is",
+					TextEdits: []analysis.TextEdit{{Pos: be.Pos(), End: be.End(), NewText: []byte(call)}},
+				}}
+			}
+		}
+		pass.Report(diag)
+		return true
+	}
+	return true
+}
+
+// interfaceCall returns the interface type and method name of e if it is
+// a call of an interface method.
+func interfaceCall(info *types.Info, e ast.Expr) (*types.Named, string) {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return nil, ""
+	}
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, ""
+	}
+	s, ok := info.Selections[sel]
+	if !ok || s.Kind() != types.MethodVal {
+		return nil, ""
+	}
+	named, ok := types.Unalias(s.Recv()).(*types.Named)
+	if !ok || !types.IsInterface(named) {
+		return nil, ""
+	}
+	return named, sel.Sel.Name
+}
+
+// implements reports whether the type called name in pkg, or a pointer to
+// it, implements iface. A type that cannot be found is assumed to.
+func implements(pkg *types.Package, name string, iface *types.Named) bool {
+	tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return true
+	}
+	it := iface.Underlying().(*types.Interface)
+	return types.Implements(tn.Type(), it) || types.Implements(types.NewPointer(tn.Type()), it)
+}
+
+// wrapAdvice recommends an error type for the implementation to wrap its
+// errors in: one already declared beside the interface, such as a
+// CacheError for a Cache, or else one to write.
+func wrapAdvice(pass *analysis.Pass, iface *types.Named, impl string) string {
+	scope := iface.Obj().Pkg().Scope()
+	var found string
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() || !strings.HasSuffix(name, "Error") || !hasUnwrap(tn.Type()) {
+			continue
+		}
+		if found == "" || strings.HasPrefix(name, iface.Obj().Name()) {
+			found = types.TypeString(tn.Type(), qualifier(pass.Pkg))
+		}
+	}
+	if found != "" {
+		return fmt.Sprintf("have %s wrap its errors in %s, which has an Unwrap method", impl, found)
+	}
+	return fmt.Sprintf("have %s wrap its errors in an implementation-neutral type such as %sError{Op, Err} with an Unwrap method",
+		impl, iface.Obj().Name())
+}
+
+func hasUnwrap(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, nil, "Unwrap")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	res := fn.Signature().Results()
+	return fn.Signature().Params().Len() == 0 && res.Len() == 1 && isError(res.At(0).Type())
+}
+
+// errorsImport returns the name under which the file containing pos
+// imports the errors package.
+func errorsImport(pass *analysis.Pass, pos token.Pos) (string, bool) {
+	for _, f := range pass.Files {
+		if f.FileStart > pos || pos > f.FileEnd {
+			continue
+		}
+		for _, imp := range f.Imports {
+			if imp.Path.Value != `"errors"` {
+				continue
+			}
+			if imp.Name == nil {
+				return "errors", true
+			}
+			if name := imp.Name.Name; name != "_" && name != "." {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// sentinelOf returns the package-level variable e refers to, or nil.
+func sentinelOf(info *types.Info, e ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	v, ok := info.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return nil
+	}
+	return v
+}
+
+// qualifier names the packages other than pkg as they are usually
+// imported, e.g. "impl.Store".
+func qualifier(pkg *types.Package) types.Qualifier {
+	return func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
@@ -0,0 +1,12 @@
+package leakyerrors_test
+
+import (
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/leakyerrors"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), leakyerrors.Analyzer, "redis", "app", "cache")
+}
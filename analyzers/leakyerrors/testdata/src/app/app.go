@@ -0,0 +1,45 @@
+package app
+
+import (
+	"errors"
+	"io"
+
+	"redis"
+	"store"
+)
+
+func lookup(s store.Store, key string) string {
+	v, err := s.Get(key)
+	if err == redis.ErrMiss { // want `err from store.Store.Get is compared with redis.ErrMiss, a sentinel of the Redis implementation; use errors.Is, and have Redis wrap its errors in store.StoreError, which has an Unwrap method`
+		return ""
+	}
+	switch err {
+	case redis.ErrDown: // want `err from store.Store.Get is compared with redis.ErrDown, a sentinel of the Redis implementation`
+		return "down"
+	case redis.ErrClosed:
+		return "closed"
+	}
+	return v
+}
+
+func found(s store.Store) bool {
+	_, err := s.Get("k")
+	return err != redis.ErrMiss // want `err from store.Store.Get is compared with redis.ErrMiss`
+}
+
+// The caller holds the concrete type, so it may know its sentinels.
+func direct(r *redis.Redis) bool {
+	_, err := r.Get("k")
+	return err == redis.ErrMiss
+}
+
+// io.EOF belongs to nobody in particular.
+func atEnd(r io.Reader) bool {
+	_, err := r.Read(nil)
+	return err == io.EOF
+}
+
+func missing(s store.Store) bool {
+	_, err := s.Get("k")
+	return errors.Is(err, redis.ErrMiss)
+}
@@ -0,0 +1,113 @@
+// Package cache is Example 1 of golangexamples/leakyabstractions, with the
+// analyzer's expectations on ProcessDataBAD, and ProcessDataGOOD, which
+// knows only what Cache promises and must not be reported.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Cache interface claims to abstract storage, but errors leak the implementation
+type Cache interface {
+	Get(key string) (string, error)
+	Set(key string, value string) error
+	Delete(key string) error
+}
+
+// RedisCache implementation
+type RedisCache struct {
+	connected bool
+}
+
+// LEAK: Redis-specific errors
+var (
+	ErrRedisPoolExhausted = errors.New("redis: connection pool exhausted") // want ErrRedisPoolExhausted:"sentinel of RedisCache"
+	ErrRedisTimeout       = errors.New("redis: operation timeout") // want ErrRedisTimeout:"sentinel of RedisCache"
+	ErrRedisConnectionLost = errors.New("redis: connection lost") // want ErrRedisConnectionLost:"sentinel of RedisCache"
+)
+
+func (r *RedisCache) Get(key string) (string, error) {
+	if !r.connected {
+		return "", ErrRedisConnectionLost // LEAK: Implementation-specific error
+	}
+	return "value", nil
+}
+
+func (r *RedisCache) Set(key string, value string) error {
+	if !r.connected {
+		return ErrRedisPoolExhausted // LEAK: Implementation-specific error
+	}
+	return nil
+}
+
+func (r *RedisCache) Delete(key string) error {
+	return nil
+}
+
+// LEAKY ABSTRACTION: Code that uses Cache is coupled to Redis implementation
+func ProcessDataBAD(w io.Writer, cache Cache) error {
+	/*
+		PROBLEM: The interface claims to hide the implementation, but we're
+		forced to check for Redis-specific errors!
+
+		You must understand:
+		- Redis connection pooling
+		- Redis-specific error conditions
+		- Implementation details that should be hidden
+
+		The abstraction leaks through error types.
+	*/
+	err := cache.Set("key", "value")
+
+	// LEAK: Checking for implementation-specific error!
+	if err == ErrRedisPoolExhausted { // want `err from Cache.Set is compared with ErrRedisPoolExhausted, a sentinel of the RedisCache implementation; use errors.Is, and have RedisCache wrap its errors in CacheError, which has an Unwrap method`
+		// Now we're coupled to Redis - the abstraction has leaked
+		fmt.Fprintln(w, "Redis pool exhausted - retry logic")
+		time.Sleep(100 * time.Millisecond)
+		return cache.Set("key", "value")
+	}
+
+	// LEAK: Another Redis-specific error check
+	if err == ErrRedisTimeout { // want `err from Cache.Set is compared with ErrRedisTimeout, a sentinel of the RedisCache implementation`
+		fmt.Fprintln(w, "Redis timeout - adjust timeout settings")
+	}
+
+	return err
+}
+
+// Better approach: Use error wrapping to hide implementation
+type CacheError struct {
+	Op  string
+	Err error
+}
+
+func (e *CacheError) Error() string {
+	return fmt.Sprintf("cache %s: %v", e.Op, e.Err)
+}
+
+func (e *CacheError) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnavailable is returned, wrapped in a *CacheError, by any Cache that
+// cannot be reached.
+var ErrUnavailable = errors.New("cache unavailable")
+
+// ProcessDataGOOD retries a failed Set and reports what it tried, without
+// knowing which implementation failed.
+func ProcessDataGOOD(w io.Writer, cache Cache) error {
+	err := cache.Set("key", "value")
+	var cerr *CacheError
+	if errors.As(err, &cerr) {
+		fmt.Fprintf(w, "cache %s failed - retry logic\n", cerr.Op)
+		time.Sleep(100 * time.Millisecond)
+		err = cache.Set("key", "value")
+	}
+	if errors.Is(err, ErrUnavailable) {
+		return fmt.Errorf("process data: %w", err)
+	}
+	return err
+}
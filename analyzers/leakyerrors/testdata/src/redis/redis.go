@@ -0,0 +1,40 @@
+package redis
+
+import "errors"
+
+// ErrMiss is returned by Redis alone, and ErrDown is declared beside it, so
+// both belong to Redis.
+var (
+	ErrMiss = errors.New("redis: miss") // want ErrMiss:"sentinel of Redis"
+	ErrDown = errors.New("redis: down") // want ErrDown:"sentinel of Redis"
+)
+
+// ErrClosed is returned by both types, so it belongs to neither.
+var ErrClosed = errors.New("closed")
+
+type Redis struct {
+	data   map[string]string
+	closed bool
+}
+
+func (r *Redis) Get(key string) (string, error) {
+	if r.closed {
+		return "", ErrClosed
+	}
+	v, ok := r.data[key]
+	if !ok {
+		return "", ErrMiss
+	}
+	return v, nil
+}
+
+type Memory struct {
+	closed bool
+}
+
+func (m *Memory) Get(key string) (string, error) {
+	if m.closed {
+		return "", ErrClosed
+	}
+	return "", nil
+}
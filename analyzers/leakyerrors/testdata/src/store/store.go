@@ -0,0 +1,15 @@
+package store
+
+type Store interface {
+	Get(key string) (string, error)
+}
+
+// StoreError is what implementations should wrap their errors in.
+type StoreError struct {
+	Op  string
+	Err error
+}
+
+func (e *StoreError) Error() string { return e.Op + ": " + e.Err.Error() }
+
+func (e *StoreError) Unwrap() error { return e.Err }
@@ -0,0 +1,552 @@
+// Package reinvent defines an Analyzer that recognizes hand-rolled
+// versions of standard library functionality by their shape and names the
+// package or function that already does the job.
+package reinvent
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+)
+
+const Doc = `report hand-rolled reimplementations of standard library functionality
+
+The reinvent analyzer recognizes, by the shape of the code rather than its
+names:
+
+  - sorts built from nested loops that swap a[i] and a[j] (sort)
+  - loops comparing s[i:i+len(sub)] == sub (contains)
+  - two-parameter functions returning the smaller or larger argument (minmax)
+  - lines split on "\n" and then on "," (csv)
+  - strings.Replace loops substituting {{key}} placeholders (template)
+  - loops over arguments looking for "-" prefixes and splitting on "=" (flags)
+  - loops folding characters into h = h*K + c (hash)
+  - characters picked from a string with math/rand, and math/rand in
+    functions that make IDs, tokens, keys or passwords (rand)
+
+Each diagnostic names the standard replacement.`
+
+// Analyzer reports reinvented wheels.
+var Analyzer = &analysis.Analyzer{
+	Name:     "reinvent",
+	Doc:      Doc,
+	URL:      "https://github.com/bclements/antipatterns/tree/main/analyzers/reinvent",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// Categories of the diagnostics, one per recognized shape.
+const (
+	CategorySort     = "sort"
+	CategoryContains = "contains"
+	CategoryMinMax   = "minmax"
+	CategoryCSV      = "csv"
+	CategoryTemplate = "template"
+	CategoryFlags    = "flags"
+	CategoryHash     = "hash"
+	CategoryRand     = "rand"
+)
+
+type checker struct {
+	pass *analysis.Pass
+	fd   *ast.FuncDecl
+	name string
+	seen map[string]bool // categories already reported for fd
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		fd := n.(*ast.FuncDecl)
+		if fd.Body == nil {
+			return
+		}
+		c := &checker{pass: pass, fd: fd, name: astutil.FuncName(fd), seen: make(map[string]bool)}
+		c.minMax()
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				c.swap(n)
+				c.polyHash(n)
+			case *ast.BinaryExpr:
+				c.substring(n)
+			case *ast.RangeStmt:
+				c.csv(n)
+				c.flags(n)
+			case *ast.CallExpr:
+				c.template(n)
+				c.rand(n)
+			}
+			return true
+		})
+	})
+	return nil, nil
+}
+
+// report reports a category once per function, at the function's name.
+func (c *checker) report(category, format string, args ...any) {
+	if c.seen[category] {
+		return
+	}
+	c.seen[category] = true
+	c.pass.Report(analysis.Diagnostic{
+		Pos:      c.fd.Name.Pos(),
+		End:      c.fd.Name.End(),
+		Category: category,
+		Message:  c.name + " " + fmt.Sprintf(format, args...),
+	})
+}
+
+// swap recognizes a[i], a[j] = a[j], a[i] inside two nested loops.
+func (c *checker) swap(as *ast.AssignStmt) {
+	if as.Tok != token.ASSIGN || len(as.Lhs) != 2 || len(as.Rhs) != 2 {
+		return
+	}
+	l0, ok0 := as.Lhs[0].(*ast.IndexExpr)
+	l1, ok1 := as.Lhs[1].(*ast.IndexExpr)
+	r0, ok2 := as.Rhs[0].(*ast.IndexExpr)
+	r1, ok3 := as.Rhs[1].(*ast.IndexExpr)
+	if !ok0 || !ok1 || !ok2 || !ok3 {
+		return
+	}
+	base := types.ExprString(l0.X)
+	for _, e := range []*ast.IndexExpr{l1, r0, r1} {
+		if types.ExprString(e.X) != base {
+			return
+		}
+	}
+	if !sameExpr(l0.Index, r1.Index) || !sameExpr(l1.Index, r0.Index) || c.loopDepth(as) < 2 {
+		return
+	}
+	t, ok := c.pass.TypesInfo.TypeOf(l0.X).Underlying().(*types.Slice)
+	if !ok {
+		return
+	}
+	elem := t.Elem()
+	switch {
+	case types.Identical(elem, types.Typ[types.Int]):
+		c.report(CategorySort, "is a hand-written sort; use slices.Sort or sort.Ints")
+	case types.Identical(elem, types.Typ[types.String]):
+		c.report(CategorySort, "is a hand-written sort; use slices.Sort or sort.Strings")
+	case isOrdered(elem):
+		c.report(CategorySort, "is a hand-written sort; use slices.Sort")
+	default:
+		c.report(CategorySort, "is a hand-written sort; use slices.SortFunc or sort.Slice")
+	}
+}
+
+// loopDepth returns the number of for and range statements in c.fd that
+// enclose n.
+func (c *checker) loopDepth(n ast.Node) int {
+	depth := 0
+	ast.Inspect(c.fd.Body, func(m ast.Node) bool {
+		if m == nil || m.Pos() > n.Pos() || m.End() < n.End() {
+			return false
+		}
+		switch m.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			depth++
+		}
+		return true
+	})
+	return depth
+}
+
+// substring recognizes s[i:i+len(sub)] == sub.
+func (c *checker) substring(be *ast.BinaryExpr) {
+	if be.Op != token.EQL && be.Op != token.NEQ {
+		return
+	}
+	for _, pair := range [][2]ast.Expr{{be.X, be.Y}, {be.Y, be.X}} {
+		sl, ok := ast.Unparen(pair[0]).(*ast.SliceExpr)
+		if !ok || sl.Low == nil || sl.High == nil {
+			continue
+		}
+		sub := pair[1]
+		// High must be Low + len(sub).
+		hi, ok := ast.Unparen(sl.High).(*ast.BinaryExpr)
+		if !ok || hi.Op != token.ADD || !sameExpr(hi.X, sl.Low) || !c.isLenOf(hi.Y, sub) {
+			continue
+		}
+		pkg := "strings"
+		if _, isSlice := c.pass.TypesInfo.TypeOf(sl.X).Underlying().(*types.Slice); isSlice {
+			pkg = "bytes"
+		}
+		switch {
+		case strings.HasSuffix(strings.ToLower(c.fd.Name.Name), "index"):
+			c.report(CategoryContains, "scans for a substring by hand; use %s.Index", pkg)
+		case types.ExprString(sl.Low) == "0":
+			c.report(CategoryContains, "compares a prefix by hand; use %s.HasPrefix", pkg)
+		default:
+			c.report(CategoryContains, "scans for a substring by hand; use %s.Contains or %s.Index", pkg, pkg)
+		}
+		return
+	}
+}
+
+func (c *checker) isLenOf(e, of ast.Expr) bool {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 || !c.isBuiltin(call.Fun, "len") {
+		return false
+	}
+	return sameExpr(call.Args[0], of)
+}
+
+// minMax recognizes a function of two ordered parameters of the same type
+// that returns whichever is smaller or larger.
+func (c *checker) minMax() {
+	sig, ok := c.pass.TypesInfo.Defs[c.fd.Name].Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 2 || sig.Results().Len() != 1 || sig.Recv() != nil {
+		return
+	}
+	a, b := sig.Params().At(0), sig.Params().At(1)
+	if !types.Identical(a.Type(), b.Type()) || !types.Identical(a.Type(), sig.Results().At(0).Type()) || !isOrdered(a.Type()) {
+		return
+	}
+	body := c.fd.Body.List
+	var ifs *ast.IfStmt
+	var otherwise ast.Stmt
+	switch len(body) {
+	case 1:
+		ifs, _ = body[0].(*ast.IfStmt)
+		if ifs != nil {
+			if blk, ok := ifs.Else.(*ast.BlockStmt); ok && len(blk.List) == 1 {
+				otherwise = blk.List[0]
+			}
+		}
+	case 2:
+		ifs, _ = body[0].(*ast.IfStmt)
+		otherwise = body[1]
+	}
+	if ifs == nil || ifs.Init != nil || len(ifs.Body.List) != 1 || otherwise == nil {
+		return
+	}
+	cond, ok := ast.Unparen(ifs.Cond).(*ast.BinaryExpr)
+	if !ok {
+		return
+	}
+	x, y := c.paramOf(cond.X, a, b), c.paramOf(cond.Y, a, b)
+	then, other := c.returned(ifs.Body.List[0], a, b), c.returned(otherwise, a, b)
+	if x == nil || y == nil || x == y || then == nil || other == nil || then == other {
+		return
+	}
+	var smaller bool
+	switch cond.Op {
+	case token.LSS, token.LEQ:
+		smaller = then == x
+	case token.GTR, token.GEQ:
+		smaller = then == y
+	default:
+		return
+	}
+	builtin := "max"
+	if smaller {
+		builtin = "min"
+	}
+	if c.fd.Name.Name == builtin {
+		c.report(CategoryMinMax, "reimplements and shadows the built-in %s (Go 1.21); delete it", builtin)
+	} else {
+		c.report(CategoryMinMax, "reimplements the built-in %s (Go 1.21); call %s(a, b) instead", builtin, builtin)
+	}
+}
+
+func (c *checker) paramOf(e ast.Expr, a, b *types.Var) *types.Var {
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	switch c.pass.TypesInfo.Uses[id] {
+	case a:
+		return a
+	case b:
+		return b
+	}
+	return nil
+}
+
+func (c *checker) returned(s ast.Stmt, a, b *types.Var) *types.Var {
+	ret, ok := s.(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	return c.paramOf(ret.Results[0], a, b)
+}
+
+// csv recognizes a range over lines that splits each one on commas.
+func (c *checker) csv(rs *ast.RangeStmt) {
+	v, ok := rs.Value.(*ast.Ident)
+	if !ok || !c.isLines(rs.X) {
+		return
+	}
+	obj := c.pass.TypesInfo.ObjectOf(v)
+	ast.Inspect(rs.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !c.isCall(call, "strings", "Split", "SplitN", "FieldsFunc") || len(call.Args) < 2 {
+			return true
+		}
+		if id, ok := ast.Unparen(call.Args[0]).(*ast.Ident); ok && c.pass.TypesInfo.Uses[id] == obj && c.stringValue(call.Args[1]) == "," {
+			c.report(CategoryCSV, "splits lines on commas by hand, which breaks on quoted fields; use encoding/csv: csv.NewReader(r).ReadAll()")
+			return false
+		}
+		return true
+	})
+}
+
+// isLines reports whether e is strings.Split(s, "\n"), or a variable
+// assigned from it in the current function.
+func (c *checker) isLines(e ast.Expr) bool {
+	isSplit := func(e ast.Expr) bool {
+		call, ok := ast.Unparen(e).(*ast.CallExpr)
+		return ok && c.isCall(call, "strings", "Split") && len(call.Args) == 2 && c.stringValue(call.Args[1]) == "\n"
+	}
+	if isSplit(e) {
+		return true
+	}
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj := c.pass.TypesInfo.Uses[id]
+	found := false
+	ast.Inspect(c.fd.Body, func(n ast.Node) bool {
+		as, ok := n.(*ast.AssignStmt)
+		if !ok || len(as.Lhs) != len(as.Rhs) {
+			return !found
+		}
+		for i, lhs := range as.Lhs {
+			if lid, ok := lhs.(*ast.Ident); ok && c.pass.TypesInfo.ObjectOf(lid) == obj && isSplit(as.Rhs[i]) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// template recognizes strings.Replace(All) substituting a {{key}} or ${key}
+// placeholder.
+func (c *checker) template(call *ast.CallExpr) {
+	if !c.isCall(call, "strings", "Replace", "ReplaceAll") || len(call.Args) < 3 || c.loopDepth(call) == 0 {
+		return
+	}
+	if c.isPlaceholder(call.Args[1]) {
+		c.report(CategoryTemplate, "substitutes placeholders with strings.Replace; use text/template (or html/template for HTML)")
+	}
+}
+
+// isPlaceholder reports whether e builds a string such as "{{" + key + "}}"
+// or fmt.Sprintf("{{%s}}", key), directly or through a local variable.
+func (c *checker) isPlaceholder(e ast.Expr) bool {
+	hasDelims := func(e ast.Expr) bool {
+		found := false
+		ast.Inspect(e, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				s := c.stringValue(lit)
+				found = found || strings.Contains(s, "{{") || strings.Contains(s, "${")
+			}
+			return !found
+		})
+		return found
+	}
+	e = ast.Unparen(e)
+	if _, ok := e.(*ast.BasicLit); !ok && hasDelims(e) {
+		return true
+	}
+	id, ok := e.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj := c.pass.TypesInfo.Uses[id]
+	found := false
+	ast.Inspect(c.fd.Body, func(n ast.Node) bool {
+		as, ok := n.(*ast.AssignStmt)
+		if ok && len(as.Lhs) == len(as.Rhs) {
+			for i, lhs := range as.Lhs {
+				if lid, ok := lhs.(*ast.Ident); ok && c.pass.TypesInfo.ObjectOf(lid) == obj && hasDelims(as.Rhs[i]) {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// flags recognizes a loop over arguments that checks for a "-" or "--"
+// prefix and splits on "=".
+func (c *checker) flags(rs *ast.RangeStmt) {
+	var dash, equals bool
+	ast.Inspect(rs.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		switch {
+		case c.isCall(call, "strings", "HasPrefix", "TrimPrefix", "CutPrefix"):
+			if s := c.stringValue(call.Args[1]); s == "-" || s == "--" {
+				dash = true
+			}
+		case c.isCall(call, "strings", "Split", "SplitN", "Cut", "Index"):
+			if c.stringValue(call.Args[1]) == "=" {
+				equals = true
+			}
+		}
+		return true
+	})
+	if dash && equals {
+		c.report(CategoryFlags, "parses --key=value arguments by hand; use the flag package")
+	}
+}
+
+// polyHash recognizes h = h*K + c and h = (h*K + c) % M inside a loop.
+func (c *checker) polyHash(as *ast.AssignStmt) {
+	if len(as.Lhs) != 1 || len(as.Rhs) != 1 || c.loopDepth(as) == 0 {
+		return
+	}
+	h, ok := as.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	hobj := c.pass.TypesInfo.ObjectOf(h)
+	if t := c.pass.TypesInfo.TypeOf(h); t == nil || !isInteger(t) {
+		return
+	}
+	rhs := ast.Unparen(as.Rhs[0])
+	if b, ok := rhs.(*ast.BinaryExpr); ok && b.Op == token.REM {
+		rhs = ast.Unparen(b.X)
+	}
+	sum, ok := rhs.(*ast.BinaryExpr)
+	if as.Tok != token.ASSIGN || !ok || (sum.Op != token.ADD && sum.Op != token.XOR) {
+		return
+	}
+	for _, operand := range []ast.Expr{sum.X, sum.Y} {
+		mul, ok := ast.Unparen(operand).(*ast.BinaryExpr)
+		if !ok || mul.Op != token.MUL {
+			continue
+		}
+		for _, pair := range [][2]ast.Expr{{mul.X, mul.Y}, {mul.Y, mul.X}} {
+			id, ok := ast.Unparen(pair[0]).(*ast.Ident)
+			if ok && c.pass.TypesInfo.Uses[id] == hobj && c.isConst(pair[1]) {
+				c.report(CategoryHash, "hand-rolls a polynomial string hash; use hash/fnv or hash/maphash, or crypto/sha256 if it must resist attack")
+				return
+			}
+		}
+	}
+}
+
+// rand recognizes math/rand used to pick characters or to make values that
+// must not be guessable.
+func (c *checker) rand(call *ast.CallExpr) {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || (fn.Pkg().Path() != "math/rand" && fn.Pkg().Path() != "math/rand/v2") {
+		return
+	}
+	words := nameWords(c.fd.Name.Name)
+	replacement := "crypto/rand.Text or crypto/rand.Read"
+	if words["uuid"] {
+		replacement = "github.com/google/uuid, or crypto/rand.Read for the bytes"
+	}
+	for _, word := range []string{"uuid", "id", "token", "secret", "password", "key", "nonce", "salt", "session"} {
+		if words[word] {
+			c.report(CategoryRand, "makes an identifier with %s, which is predictable; use %s", fn.Pkg().Path(), replacement)
+			return
+		}
+	}
+	// chars[rand.Intn(len(chars))] builds a random string.
+	ast.Inspect(c.fd.Body, func(n ast.Node) bool {
+		ix, ok := n.(*ast.IndexExpr)
+		if !ok || ast.Unparen(ix.Index) != call {
+			return true
+		}
+		if t := c.pass.TypesInfo.TypeOf(ix.X); t != nil && isString(t) {
+			c.report(CategoryRand, "picks characters with %s, which is predictable; use %s", fn.Pkg().Path(), replacement)
+		}
+		return false
+	})
+}
+
+// isCall reports whether call calls one of the named functions of the
+// package with the given path.
+func (c *checker) isCall(call *ast.CallExpr, pkg string, names ...string) bool {
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkg {
+		return false
+	}
+	for _, name := range names {
+		if fn.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) isBuiltin(fun ast.Expr, name string) bool {
+	id, ok := ast.Unparen(fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := c.pass.TypesInfo.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+func (c *checker) isConst(e ast.Expr) bool {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	return ok && tv.Value != nil
+}
+
+// stringValue returns the value of a constant string expression, or "".
+func (c *checker) stringValue(e ast.Expr) string {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+// nameWords splits a mixedCaps name into its lower-case words, so that
+// "generateUUID" gives generate and uuid.
+func nameWords(name string) map[string]bool {
+	words := make(map[string]bool)
+	start := 0
+	for i := 1; i <= len(name); i++ {
+		if i == len(name) || name[i] == '_' ||
+			(isUpper(name[i]) && (!isUpper(name[i-1]) || (i+1 < len(name) && !isUpper(name[i+1]) && name[i+1] != '_'))) {
+			if w := strings.Trim(name[start:i], "_"); w != "" {
+				words[strings.ToLower(w)] = true
+			}
+			start = i
+		}
+	}
+	return words
+}
+
+func isUpper(b byte) bool { return 'A' <= b && b <= 'Z' }
+
+func sameExpr(a, b ast.Expr) bool {
+	return types.ExprString(ast.Unparen(a)) == types.ExprString(ast.Unparen(b))
+}
+
+func isOrdered(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsOrdered != 0
+}
+
+func isInteger(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsInteger != 0
+}
+
+func isString(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
@@ -0,0 +1,12 @@
+package reinvent_test
+
+import (
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/reinvent"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), reinvent.Analyzer, "wheel")
+}
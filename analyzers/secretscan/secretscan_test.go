@@ -0,0 +1,52 @@
+package secretscan_test
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/secretscan"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// setFlags sets the analyzer's flags for one test, restoring them after.
+func setFlags(t *testing.T, flags map[string]string) {
+	for name, value := range flags {
+		f := secretscan.Analyzer.Flags.Lookup(name)
+		old := f.Value.String()
+		if err := f.Value.Set(value); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { f.Value.Set(old) })
+	}
+}
+
+func TestHardCoding(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), secretscan.Analyzer, "hardcoding")
+}
+
+func TestEntropy(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), secretscan.Analyzer, "entropy")
+
+	setFlags(t, map[string]string{"entropy": "3.9"})
+	analysistest.Run(t, analysistest.TestData(), secretscan.Analyzer, "strict")
+}
+
+func TestAllowlist(t *testing.T) {
+	setFlags(t, map[string]string{"allowlist": filepath.Join(analysistest.TestData(), "allowlist")})
+	analysistest.Run(t, analysistest.TestData(), secretscan.Analyzer, "allowed", "vendored")
+}
+
+func TestEntropyValues(t *testing.T) {
+	for s, want := range map[string]float64{
+		"":         0,
+		"aaaa":     0,
+		"abab":     1,
+		"abcd":     2,
+		"changeme": 2.75,
+	} {
+		if got := secretscan.Entropy(s); math.Abs(got-want) > 0.01 {
+			t.Errorf("Entropy(%q) = %.2f, want %.2f", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package allowed
+
+// StripeTestKey matches a value: entry and RedisPassword a name: entry of
+// testdata/allowlist; StripeKey matches neither.
+var (
+	StripeTestKey = "sk_live_0000000000fixture"
+	StripeKey     = "sk_live_abc123def456ghi789" // want `hard-coded Stripe secret key`
+)
+
+type Config struct {
+	RedisPassword string
+	DBPassword    string // want `credential field DBPassword is a plain string`
+}
+
+var config = Config{
+	RedisPassword: "RedisPassword123",
+	DBPassword:    "SuperSecret123!", // want `hard-coded credential in DBPassword`
+}
@@ -0,0 +1,12 @@
+package entropy
+
+// Values assigned to credential names are reported when they are long
+// enough and their entropy reaches the default 3 bits per character.
+func setup() []string {
+	password := "k8Jq2xLp9Zr4" // want `hard-coded credential in password \(k8Jq2x…, 3.6 bits/char\)`
+	secret := "aaaaaaaaaaaa"   // one repeated character: 0 bits per character
+	apiKey := "changeme"       // 2.8 bits per character
+	token := "k8Jq2x"          // shorter than -min-length
+	username := "k8Jq2xLp9Zr4" // not a credential name
+	return []string{password, secret, apiKey, token, username}
+}
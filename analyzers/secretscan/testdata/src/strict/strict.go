@@ -0,0 +1,8 @@
+package strict
+
+// With -entropy=3.9, only values close to random are reported.
+func setup() []string {
+	password := "k8Jq2xLp9Zr4"   // 3.6 bits per character
+	secret := "k8Jq2xLp9Zr4WmTb" // want `hard-coded credential in secret \(k8Jq2x…, 4.0 bits/char\)`
+	return []string{password, secret}
+}
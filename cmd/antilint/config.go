@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"gopkg.in/yaml.v3"
+)
+
+// configName is the file antilint looks for when -config is not given.
+const configName = ".antilint.yaml"
+
+// A severity says whether a check's findings are reported. There are no
+// levels in between: every finding antilint prints makes it exit with
+// status 3, so a check that must not fail the build is turned off.
+type severity string
+
+const (
+	severityError severity = "error"
+	severityOff   severity = "off"
+)
+
+func (s *severity) UnmarshalYAML(n *yaml.Node) error {
+	switch v := severity(n.Value); v {
+	case severityError, severityOff:
+		*s = v
+		return nil
+	}
+	return fmt.Errorf("line %d: unknown severity %q (want error or off)", n.Line, n.Value)
+}
+
+// config is the content of an .antilint.yaml file.
+type config struct {
+	Checks map[string]severity `yaml:"checks"`
+}
+
+var configs = struct {
+	sync.Mutex
+	byPath map[string]*config // "" for no file
+	byDir  map[string]string  // directory -> the config file that applies
+}{byPath: make(map[string]*config), byDir: make(map[string]string)}
+
+// loadConfig returns the configuration that applies to the package of
+// pass: the -config file, or the nearest .antilint.yaml above the
+// package's directory, or the defaults.
+func loadConfig(pass *analysis.Pass) (*config, error) {
+	configs.Lock()
+	defer configs.Unlock()
+
+	path := *configPath
+	if path == "" && len(pass.Files) > 0 {
+		path = findConfig(filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name()))
+	}
+	if cfg, ok := configs.byPath[path]; ok {
+		return cfg, nil
+	}
+	cfg := new(config)
+	if path != "" {
+		var err error
+		if cfg, err = readConfig(path); err != nil {
+			return nil, err
+		}
+	}
+	configs.byPath[path] = cfg
+	return cfg, nil
+}
+
+// readConfig reads an .antilint.yaml file.
+func readConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(config)
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	for name := range cfg.Checks {
+		if !isCheck(name) {
+			return nil, fmt.Errorf("%s: unknown check %q", path, name)
+		}
+	}
+	return cfg, nil
+}
+
+// findConfig returns the nearest .antilint.yaml in dir or its parents, or
+// "" if there is none.
+func findConfig(dir string) string {
+	if path, ok := configs.byDir[dir]; ok {
+		return path
+	}
+	path := filepath.Join(dir, configName)
+	if _, err := os.Stat(path); err != nil {
+		path = ""
+		if parent := filepath.Dir(dir); parent != dir {
+			path = findConfig(parent)
+		}
+	}
+	configs.byDir[dir] = path
+	return path
+}
+
+func isCheck(name string) bool {
+	for _, c := range checks {
+		if c.analyzer.Name == name {
+			return true
+		}
+	}
+	return false
+}
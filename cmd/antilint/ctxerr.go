@@ -0,0 +1,148 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ctxErr reports errors from calls given a context that can expire, when
+// the function never asks whether the context expired. Such an error may
+// be context.DeadlineExceeded, context.Canceled or the callee's own
+// failure, and the caller, which set the deadline, treats them all alike:
+// how the callee reports a timeout has leaked into every caller, which
+// cannot tell.
+var ctxErr = &analysis.Analyzer{
+	Name:     "ctxerr",
+	Doc:      "report errors from calls given an expiring context that are handled without checking whether the context expired",
+	URL:      "https://github.com/bclements/antipatterns/tree/main/cmd/antilint",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runCtxErr,
+}
+
+// expiring are the context functions whose contexts can end early.
+var expiring = map[string]bool{
+	"WithCancel":        true,
+	"WithCancelCause":   true,
+	"WithDeadline":      true,
+	"WithDeadlineCause": true,
+	"WithTimeout":       true,
+	"WithTimeoutCause":  true,
+}
+
+func runCtxErr(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body == nil {
+			return
+		}
+		ctxs := expiringContexts(pass.TypesInfo, body)
+		if len(ctxs) == 0 || checksExpiry(pass.TypesInfo, body, ctxs) {
+			return
+		}
+		ast.Inspect(body, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok && lit.Body != body {
+				return false // checked on its own
+			}
+			as, ok := n.(*ast.AssignStmt)
+			if !ok || len(as.Rhs) != 1 {
+				return true
+			}
+			call, ok := ast.Unparen(as.Rhs[0]).(*ast.CallExpr)
+			if !ok || !passes(pass.TypesInfo, call, ctxs) {
+				return true
+			}
+			for i, lhs := range as.Lhs {
+				id, ok := lhs.(*ast.Ident)
+				if !ok || id.Name == "_" || !isErrorResult(pass.TypesInfo, call, i) {
+					continue
+				}
+				pass.Reportf(call.Pos(), "error from %s, given a context that can expire, is handled without checking whether it did; test errors.Is(%s, context.DeadlineExceeded) or ctx.Err() before treating it as the callee's failure",
+					types.ExprString(call.Fun), id.Name)
+			}
+			return true
+		})
+	})
+	return nil, nil
+}
+
+// expiringContexts returns the variables of body assigned a context from
+// one of the expiring functions.
+func expiringContexts(info *types.Info, body *ast.BlockStmt) map[types.Object]bool {
+	ctxs := make(map[types.Object]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		as, ok := n.(*ast.AssignStmt)
+		if !ok || len(as.Rhs) != 1 || len(as.Lhs) == 0 {
+			return true
+		}
+		call, ok := ast.Unparen(as.Rhs[0]).(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := typeutil.Callee(info, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "context" || !expiring[fn.Name()] {
+			return true
+		}
+		if id, ok := as.Lhs[0].(*ast.Ident); ok && info.ObjectOf(id) != nil {
+			ctxs[info.ObjectOf(id)] = true
+		}
+		return true
+	})
+	return ctxs
+}
+
+// checksExpiry reports whether body mentions context.DeadlineExceeded or
+// context.Canceled, calls context.Cause, or calls Err on one of ctxs.
+func checksExpiry(info *types.Info, body *ast.BlockStmt, ctxs map[types.Object]bool) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || found {
+			return !found
+		}
+		if obj := info.Uses[sel.Sel]; obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == "context" &&
+			(obj.Name() == "DeadlineExceeded" || obj.Name() == "Canceled" || obj.Name() == "Cause") {
+			found = true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && sel.Sel.Name == "Err" && ctxs[info.Uses[id]] {
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+// passes reports whether one of call's arguments is one of ctxs.
+func passes(info *types.Info, call *ast.CallExpr, ctxs map[types.Object]bool) bool {
+	for _, arg := range call.Args {
+		if id, ok := ast.Unparen(arg).(*ast.Ident); ok && ctxs[info.Uses[id]] {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorResult reports whether call's i'th result is an error.
+func isErrorResult(info *types.Info, call *ast.CallExpr, i int) bool {
+	t := info.TypeOf(call)
+	if tuple, ok := t.(*types.Tuple); ok {
+		if i >= tuple.Len() {
+			return false
+		}
+		t = tuple.At(i).Type()
+	} else if i > 0 {
+		return false
+	}
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
@@ -0,0 +1,208 @@
+// Command antilint runs a bundle of the standard vet passes and reports
+// each finding under the anti-pattern it is evidence of.
+//
+// Usage:
+//
+//	antilint [-config=file] [-<check>=false] packages...
+//
+// The checks and the anti-patterns they map to are:
+//
+//	unreachable  Dead Code           statements that can never run
+//	nilness      Dead Code           nil comparisons that are always true or false
+//	unusedwrite  Boat Anchor         struct fields written and never read
+//	shadow       Spaghetti Code      variables shadowing the ones they seem to set
+//	copylocks    Leaky Abstractions  locks copied along with the value that hides them
+//	lostcancel   Leaky Abstractions  context cancel functions that are never called
+//	ctxerr       Leaky Abstractions  errors from calls given an expiring context, handled
+//	                                 without checking whether the context expired
+//
+// All but ctxerr are golang.org/x/tools passes. ctxerr is antilint's own: it
+// reports the leak lostcancel cannot see in CallServiceBAD, which cancels
+// properly but cannot tell a timeout from any other failure.
+//
+// Each diagnostic reads "<anti-pattern> (<check>): <message>", with
+// Category set to the anti-pattern, e.g. "dead-code", in -json output.
+//
+// Severities come from .antilint.yaml, found in the package's directory or
+// the nearest parent that has one, or from the file named by -config:
+//
+//	checks:
+//	  shadow: off
+//	  nilness: error
+//
+// A severity is error, the default, or off. Findings of a check that is
+// off are dropped; any other finding is printed and makes antilint exit
+// with status 3. There are no warnings: a check that must not fail the
+// build is turned off.
+//
+// A finding can be suppressed where it occurs with a comment naming the
+// check, or its anti-pattern, and saying why:
+//
+//	//antilint:ignore unreachable kept for the debugger
+//
+// The comment applies to its own line and the line below it; in a
+// function's doc comment it applies to the whole function. A suppression
+// without a reason does not suppress, and the finding says so.
+//
+// The examples under golangexamples are the corpus: the findings antilint
+// is expected to report on them are in testdata/golangexamples.golden.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/multichecker"
+	"golang.org/x/tools/go/analysis/passes/copylock"
+	"golang.org/x/tools/go/analysis/passes/lostcancel"
+	"golang.org/x/tools/go/analysis/passes/nilness"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/unreachable"
+	"golang.org/x/tools/go/analysis/passes/unusedwrite"
+)
+
+// An antiPattern is one of the anti-patterns the examples demonstrate.
+type antiPattern struct {
+	name     string // as the examples title it, e.g. "Dead Code"
+	category string // as a diagnostic category, e.g. "dead-code"
+}
+
+var (
+	deadCode          = antiPattern{"Dead Code", "dead-code"}
+	boatAnchor        = antiPattern{"Boat Anchor", "boat-anchor"}
+	spaghettiCode     = antiPattern{"Spaghetti Code", "spaghetti-code"}
+	leakyAbstractions = antiPattern{"Leaky Abstractions", "leaky-abstractions"}
+)
+
+// checks are the bundled passes, with the anti-pattern their findings
+// evidence.
+var checks = []struct {
+	analyzer *analysis.Analyzer
+	pattern  antiPattern
+}{
+	{unreachable.Analyzer, deadCode},
+	{nilness.Analyzer, deadCode},
+	{unusedwrite.Analyzer, boatAnchor},
+	{shadow.Analyzer, spaghettiCode},
+	{copylock.Analyzer, leakyAbstractions},
+	{lostcancel.Analyzer, leakyAbstractions},
+	{ctxErr, leakyAbstractions},
+}
+
+var configPath = flag.String("config", "", "severity configuration file (default: the nearest .antilint.yaml)")
+
+func main() {
+	var analyzers []*analysis.Analyzer
+	for _, c := range checks {
+		analyzers = append(analyzers, wrap(c.analyzer, c.pattern))
+	}
+	multichecker.Main(analyzers...)
+}
+
+// wrap returns a copy of a whose diagnostics are relabelled with the
+// anti-pattern, and filtered by the configuration and the //antilint:ignore
+// comments of the package.
+func wrap(a *analysis.Analyzer, pattern antiPattern) *analysis.Analyzer {
+	w := *a
+	w.Run = func(pass *analysis.Pass) (any, error) {
+		cfg, err := loadConfig(pass)
+		if err != nil {
+			return nil, err
+		}
+		off := cfg.Checks[a.Name] == severityOff
+		ignores := ignoreDirectives(pass)
+
+		p := *pass
+		p.Report = func(d analysis.Diagnostic) {
+			if off {
+				return
+			}
+			msg := d.Message
+			switch ig := ignores.find(d.Pos, a.Name, pattern.category); {
+			case ig == nil:
+			case ig.reason == "":
+				msg += " (antilint:ignore needs a reason to suppress this)"
+			default:
+				return
+			}
+			d.Category = pattern.category
+			d.Message = fmt.Sprintf("%s (%s): %s", pattern.name, a.Name, msg)
+			pass.Report(d)
+		}
+		return a.Run(&p)
+	}
+	return &w
+}
+
+// An ignore is one //antilint:ignore comment.
+type ignore struct {
+	check  string // a check name or an anti-pattern category
+	reason string
+	from   token.Pos // the range of code it applies to
+	to     token.Pos
+}
+
+type ignores []*ignore
+
+// find returns the ignore that applies to a finding of check at pos,
+// preferring one that gives a reason.
+func (igs ignores) find(pos token.Pos, check, category string) *ignore {
+	var found *ignore
+	for _, ig := range igs {
+		if (ig.check == check || ig.check == category) && ig.from <= pos && pos < ig.to {
+			if found == nil || found.reason == "" {
+				found = ig
+			}
+		}
+	}
+	return found
+}
+
+const ignorePrefix = "//antilint:ignore"
+
+// ignoreDirectives returns the //antilint:ignore comments of the package
+// with the code each applies to.
+func ignoreDirectives(pass *analysis.Pass) ignores {
+	var out ignores
+	for _, f := range pass.Files {
+		tf := pass.Fset.File(f.Pos())
+		if tf == nil {
+			continue
+		}
+		docs := make(map[*ast.CommentGroup]*ast.FuncDecl)
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok && fd.Doc != nil {
+				docs[fd.Doc] = fd
+			}
+		}
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				rest, ok := strings.CutPrefix(c.Text, ignorePrefix)
+				if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+					continue
+				}
+				check, reason, _ := strings.Cut(strings.TrimSpace(rest), " ")
+				if check == "" {
+					continue
+				}
+				ig := &ignore{check: check, reason: strings.TrimSpace(reason)}
+				if fd := docs[cg]; fd != nil {
+					ig.from, ig.to = fd.Pos(), fd.End()
+				} else {
+					line := tf.Line(c.Pos())
+					ig.from = tf.LineStart(line)
+					ig.to = token.Pos(tf.Base() + tf.Size())
+					if line+2 <= tf.LineCount() {
+						ig.to = tf.LineStart(line + 2)
+					}
+				}
+				out = append(out, ig)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/passes/unreachable"
+)
+
+func TestCtxErr(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), ctxErr, "ctx")
+}
+
+// TestSeverity runs the wrapped unreachable check on packages whose
+// .antilint.yaml, or their parent's, turns it off or on.
+func TestSeverity(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), wrap(unreachable.Analyzer, deadCode), "off", "off/nested", "configured")
+}
+
+func TestIgnore(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), wrap(unreachable.Analyzer, deadCode), "ignore")
+}
+
+func TestReadConfig(t *testing.T) {
+	for _, test := range []struct {
+		name, yaml string
+		want       map[string]severity
+		err        string
+	}{
+		{
+			name: "error and off",
+			yaml: "checks:\n  unreachable: error\n  shadow: off\n",
+			want: map[string]severity{"unreachable": severityError, "shadow": severityOff},
+		},
+		{
+			name: "empty",
+			yaml: "",
+		},
+		{
+			name: "warning",
+			yaml: "checks:\n  shadow: warning\n",
+			err:  `line 2: unknown severity "warning" (want error or off)`,
+		},
+		{
+			name: "info",
+			yaml: "checks:\n  shadow: info\n",
+			err:  `line 2: unknown severity "info" (want error or off)`,
+		},
+		{
+			name: "bad value",
+			yaml: "checks:\n  shadow: [off]\n",
+			err:  `line 2: unknown severity "" (want error or off)`,
+		},
+		{
+			name: "unknown check",
+			yaml: "checks:\n  deadcode: off\n",
+			err:  `unknown check "deadcode"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), configName)
+			if err := os.WriteFile(path, []byte(test.yaml), 0o666); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := readConfig(path)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("readConfig: %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(cfg.Checks, test.want) {
+				t.Errorf("checks %v, want %v", cfg.Checks, test.want)
+			}
+		})
+	}
+}
+
+// TestGolden runs antilint over the examples and compares what it reports
+// with testdata/golangexamples.golden.
+func TestGolden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs antilint")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(t.TempDir(), "antilint")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	cmd := exec.Command(bin, "./golangexamples/...")
+	cmd.Dir = root
+	out, err := cmd.CombinedOutput()
+	if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 3 {
+		t.Fatalf("antilint: %v, want exit status 3\n%s", err, out)
+	}
+	got := strings.ReplaceAll(string(out), root+string(filepath.Separator), "")
+
+	want, err := os.ReadFile(filepath.Join("testdata", "golangexamples.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("antilint ./golangexamples/... differs from testdata/golangexamples.golden:\n--- got\n%s--- want\n%s", got, want)
+	}
+}
@@ -0,0 +1,7 @@
+// Package configured has .antilint.yaml set unreachable to error.
+package configured
+
+func f() int {
+	return 1
+	println("unreachable") // want `^Dead Code \(unreachable\): unreachable code$`
+}
@@ -0,0 +1,56 @@
+package ctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func fetch(ctx context.Context, key string) (string, error) { return key, ctx.Err() }
+
+func save(ctx context.Context) error { return ctx.Err() }
+
+func opaque() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	v, err := fetch(ctx, "k") // want `error from fetch, given a context that can expire, is handled without checking whether it did`
+	if err != nil {
+		fmt.Println("fetch failed:", err)
+	}
+	err = save(ctx) // want `error from save, given a context that can expire`
+	fmt.Println(v, err)
+}
+
+func checked() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := save(ctx); errors.Is(err, context.DeadlineExceeded) {
+		fmt.Println("timed out")
+	}
+}
+
+func asksContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := save(ctx); err != nil && ctx.Err() == nil {
+		fmt.Println("save failed:", err)
+	}
+}
+
+// A background context never expires, so its errors are the callee's own.
+func background() {
+	if err := save(context.Background()); err != nil {
+		fmt.Println("save failed:", err)
+	}
+}
+
+// Nested function literals are checked on their own.
+func nested() func() {
+	return func() {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+		defer cancel()
+		_, err := fetch(ctx, "k") // want `error from fetch`
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,45 @@
+// Package ignore suppresses unreachable code with //antilint:ignore.
+package ignore
+
+func sameLine() int {
+	return 1
+	println("x") //antilint:ignore unreachable kept for the debugger
+}
+
+func nextLine() int {
+	return 1
+	//antilint:ignore unreachable kept for the debugger
+	println("x")
+}
+
+// wholeFunction is a sketch.
+//
+//antilint:ignore unreachable the whole function is a sketch
+func wholeFunction() int {
+	return 1
+	println("x")
+}
+
+func category() int {
+	return 1
+	println("x") //antilint:ignore dead-code kept for the debugger
+}
+
+func noReason() int {
+	return 1
+	//antilint:ignore unreachable
+	println("x") // want `^Dead Code \(unreachable\): unreachable code \(antilint:ignore needs a reason to suppress this\)$`
+}
+
+func otherCheck() int {
+	return 1
+	//antilint:ignore shadow kept for the debugger
+	println("x") // want `^Dead Code \(unreachable\): unreachable code$`
+}
+
+func tooFar() int {
+	return 1
+	//antilint:ignore unreachable kept for the debugger
+
+	println("x") // want `^Dead Code \(unreachable\): unreachable code$`
+}
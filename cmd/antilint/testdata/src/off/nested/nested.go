@@ -0,0 +1,7 @@
+// Package nested has no .antilint.yaml of its own and takes its parent's.
+package nested
+
+func f() int {
+	return 1
+	println("unreachable, but off")
+}
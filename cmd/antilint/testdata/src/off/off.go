@@ -0,0 +1,7 @@
+// Package off has .antilint.yaml turn unreachable off.
+package off
+
+func f() int {
+	return 1
+	println("unreachable, but off")
+}
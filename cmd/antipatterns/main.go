@@ -0,0 +1,78 @@
+// Command antipatterns lists and runs the Go anti-pattern examples.
+//
+// Usage:
+//
+//	antipatterns              # list the available examples
+//	antipatterns god_object   # run one example
+//	antipatterns all          # run every example in presentation order
+//	antipatterns -good all    # run the idiomatic rewrites instead
+//
+// The rewrites print what the examples print, except where the output is
+// itself a symptom of the anti-pattern, so
+//
+//	diff <(antipatterns all) <(antipatterns -good all)
+//
+// shows only the behavior the refactoring fixed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bclements/antipatterns/golangexamples"
+	"github.com/bclements/antipatterns/goodexamples"
+)
+
+var good = flag.Bool("good", false, "run the idiomatic rewrites in goodexamples instead of the anti-patterns")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: antipatterns [-good] [all | example ...]\n\nExamples:\n")
+		list(flag.CommandLine.Output())
+	}
+	flag.Parse()
+
+	all, lookup := golangexamples.All, golangexamples.Lookup
+	if *good {
+		all, lookup = goodexamples.All, goodexamples.Lookup
+	}
+
+	if flag.NArg() == 0 {
+		list(os.Stdout)
+		return
+	}
+
+	var examples []golangexamples.Example
+	if flag.NArg() == 1 && flag.Arg(0) == "all" {
+		examples = all
+	} else {
+		for _, name := range flag.Args() {
+			ex, ok := lookup(strings.TrimSuffix(name, ".go"))
+			if !ok {
+				fmt.Fprintf(os.Stderr, "antipatterns: unknown example %q\n", name)
+				flag.Usage()
+				os.Exit(2)
+			}
+			examples = append(examples, ex)
+		}
+	}
+
+	for i, ex := range examples {
+		if len(examples) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("### %s (%s)\n\n", ex.Title, ex.Name)
+		}
+		ex.Run(os.Stdout)
+	}
+}
+
+func list(w io.Writer) {
+	for _, ex := range golangexamples.All {
+		fmt.Fprintf(w, "  %-24s %s\n", ex.Name, ex.Title)
+	}
+}
@@ -0,0 +1,89 @@
+// Command appconfig loads and validates the configuration of the Hard
+// Coding rewrite in goodexamples/hardcoding.
+//
+// Usage:
+//
+//	appconfig [-config=file] [-secrets=source] [-print-config] [-database.host=...] ...
+//
+// Each setting comes from the first of these that sets it: a flag named by
+// its key, such as -database.host; its environment variable, such as
+// DB_HOST; the -config file, in YAML, JSON or TOML; or the built-in
+// default. -help lists the settings with their variables.
+//
+// Credentials come only from the -secrets source, by their variable names:
+//
+//	env         environment variables (the default)
+//	file:dir    one file per secret in dir, as Docker and Kubernetes mount them
+//	https://…   a Vault-style key-value store, with the token in $VAULT_TOKEN
+//
+// appconfig prints every problem it finds and exits 1, or exits 0 if the
+// configuration is valid. With -print-config it also prints each value,
+// secrets redacted, and the layer that set it, followed by the URLs of the
+// endpoints profile and where each came from.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding"
+)
+
+var (
+	configFile  = flag.String("config", "", "YAML, JSON or TOML configuration file")
+	secrets     = flag.String("secrets", "env", "where credentials come from: env, file:dir or a Vault-style http(s) URL")
+	printConfig = flag.Bool("print-config", false, "print the configuration, secrets redacted, with where each value came from")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("appconfig: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: appconfig [flags]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	hardcoding.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	provider, err := secretProvider(*secrets)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg, prov, err := hardcoding.Load(context.Background(), hardcoding.Sources{
+		File:    *configFile,
+		Getenv:  os.Getenv,
+		Flags:   flag.CommandLine,
+		Secrets: provider,
+	})
+	if *printConfig {
+		if perr := hardcoding.PrintConfig(os.Stdout, cfg, prov); perr != nil {
+			log.Fatal(perr)
+		}
+	}
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			log.Print(line)
+		}
+		os.Exit(1)
+	}
+}
+
+func secretProvider(spec string) (hardcoding.SecretProvider, error) {
+	switch {
+	case spec == "env":
+		return hardcoding.EnvSecrets(os.Getenv), nil
+	case strings.HasPrefix(spec, "file:"):
+		return hardcoding.FileSecrets{Dir: strings.TrimPrefix(spec, "file:")}, nil
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return &hardcoding.HTTPSecrets{Addr: spec, Token: hardcoding.NewSecret(os.Getenv("VAULT_TOKEN"))}, nil
+	}
+	return nil, fmt.Errorf("bad -secrets %q: want env, file:dir or an http(s) URL", spec)
+}
@@ -0,0 +1,111 @@
+// Command characterize checks that a refactoring of the god object in
+// golangexamples/godobject has not changed what it does.
+//
+// Usage:
+//
+//	characterize [-transcript=file] [-against=legacy|strangler|flags]
+//	characterize -record [-transcript=file]
+//
+// With -record, characterize calls every exported ApplicationManager method
+// of a new godobject.ApplicationManager, in the order of a fixed script, and
+// writes the arguments and results to the transcript, a golden master. It
+// refuses if the script leaves a method out.
+//
+// Otherwise it replays the transcript against a new value of the
+// implementation -against names: the original, the strangler-fig facade of
+// goodexamples/godobject/strangler, or that facade with its Features
+// component replaced by the flag evaluator of goodexamples/hardcoding/flags.
+// It prints each call whose results differ from the recorded ones and exits
+// 3 if there are any.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/bclements/antipatterns/golangexamples/godobject"
+	"github.com/bclements/antipatterns/goodexamples/godobject/strangler"
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+	"github.com/bclements/antipatterns/internal/characterize"
+)
+
+var (
+	transcript = flag.String("transcript", "cmd/characterize/testdata/applicationmanager.json", "the transcript file")
+	record     = flag.Bool("record", false, "record the transcript from the original ApplicationManager")
+	against    = flag.String("against", "strangler", "the implementation to replay against: legacy, strangler or flags")
+)
+
+// targets are the implementations the transcript can be replayed against.
+var targets = map[string]func() any{
+	"legacy":    func() any { return godobject.NewApplicationManager() },
+	"strangler": func() any { return strangler.New() },
+	"flags": func() any {
+		// The god object's flag map, which nothing ever set, is a flag
+		// evaluator with no flags.
+		m := strangler.New()
+		e, err := flags.New(nil, flags.Options{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		m.Features = strangler.FlagFeatures{Flags: e}
+		return m
+	},
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("characterize: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: characterize [flags]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *record {
+		legacy := godobject.NewApplicationManager()
+		t, err := characterize.Record(legacy, script)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if missing := characterize.Missing(t, legacy); len(missing) > 0 {
+			log.Fatalf("the script does not call %s", strings.Join(missing, ", "))
+		}
+		if err := t.Write(*transcript); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("recorded %d calls to %s\n", len(t.Calls), *transcript)
+		return
+	}
+
+	newTarget, ok := targets[*against]
+	if !ok {
+		log.Fatalf("unknown -against %q: want legacy, strangler or flags", *against)
+	}
+	t, err := characterize.Read(*transcript)
+	if err != nil {
+		log.Fatal(err)
+	}
+	target := newTarget()
+	divergences, err := characterize.Replay(t, target)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, d := range divergences {
+		fmt.Println(d)
+	}
+	for _, m := range characterize.Missing(t, target) {
+		fmt.Printf("not covered by the transcript: %s\n", m)
+	}
+	if len(divergences) > 0 {
+		fmt.Printf("%d of %d calls diverge\n", len(divergences), len(t.Calls))
+		os.Exit(3)
+	}
+	fmt.Printf("%d calls match\n", len(t.Calls))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/bclements/antipatterns/golangexamples/godobject"
+	"github.com/bclements/antipatterns/internal/characterize"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+const golden = "testdata/applicationmanager.json"
+
+// TestRecord checks that the script still records the golden master, so
+// that the transcript the other targets are replayed against is what the
+// original does today.
+func TestRecord(t *testing.T) {
+	legacy := godobject.NewApplicationManager()
+	tr, err := characterize.Record(legacy, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing := characterize.Missing(tr, legacy); len(missing) > 0 {
+		t.Errorf("the script does not call %q", missing)
+	}
+	if *update {
+		if err := tr.Write(golden); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	path := filepath.Join(t.TempDir(), "transcript.json")
+	if err := tr.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("recording differs from %s; run go test -update if the original changed on purpose", golden)
+	}
+}
+
+// TestReplay replays the golden master against every target.
+func TestReplay(t *testing.T) {
+	tr, err := characterize.Read(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(targets)) {
+		t.Run(name, func(t *testing.T) {
+			target := targets[name]()
+			divergences, err := characterize.Replay(tr, target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range divergences {
+				t.Error(d)
+			}
+			if missing := characterize.Missing(tr, target); len(missing) > 0 {
+				t.Errorf("not covered by the transcript: %q", missing)
+			}
+		})
+	}
+}
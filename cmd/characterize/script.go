@@ -0,0 +1,114 @@
+package main
+
+import (
+	"time"
+
+	"github.com/bclements/antipatterns/golangexamples/godobject"
+	"github.com/bclements/antipatterns/internal/characterize"
+)
+
+// script exercises every method of the ApplicationManager, with the calls
+// that share state interleaved so that the transcript pins down how they
+// interact: cache entries surviving their TTL, config overwrites, feature
+// flags nothing can set.
+var script = []characterize.Step{
+	// Users
+	{Method: "CreateUser", Args: []any{"alice", "alice@example.com", "s3cret"}},
+	{Method: "CreateUser", Args: []any{"alice", "alice@example.com", "s3cret"}},
+	{Method: "GetUserByID", Args: []any{1}},
+	{Method: "UpdateUserProfile", Args: []any{1, map[string]any{"email": "alice@example.org", "age": 30}}},
+	{Method: "DeleteUser", Args: []any{1}},
+	{Method: "GetUserByID", Args: []any{1}},
+
+	// Authentication
+	{Method: "AuthenticateUser", Args: []any{"alice", "s3cret"}},
+	{Method: "AuthenticateUser", Args: []any{"alice", "wrong"}},
+	{Method: "ValidateToken", Args: []any{"token-1"}},
+	{Method: "RefreshAuthToken", Args: []any{"refresh-1"}},
+	{Method: "ResetPassword", Args: []any{"alice@example.com"}},
+	{Method: "LogoutUser", Args: []any{1}},
+
+	// Database
+	{Method: "ConnectToDatabase", Args: []any{}},
+	{Method: "ExecuteQuery", Args: []any{"SELECT 1", []any{}}},
+	{Method: "ExecuteQuery", Args: []any{"SELECT * FROM users WHERE id = ? AND name = ?", []any{1, "alice"}}},
+	{Method: "MigrateDatabase", Args: []any{}},
+	{Method: "BackupDatabase", Args: []any{}},
+	{Method: "RollbackTransaction", Args: []any{}},
+
+	// Products and inventory
+	{Method: "AddProduct", Args: []any{godobject.Product{ID: 1, Name: "Laptop", Price: 999.99}}},
+	{Method: "SearchProducts", Args: []any{"Laptop"}},
+	{Method: "UpdateProductPrice", Args: []any{1, 899.99}},
+	{Method: "UpdateInventory", Args: []any{1, 10}},
+	{Method: "UpdateInventory", Args: []any{1, -11}},
+	{Method: "GetProductRecommendations", Args: []any{1}},
+	{Method: "RemoveProduct", Args: []any{1}},
+
+	// Orders and shipping
+	{Method: "CreateOrder", Args: []any{1, []int{1, 2}}},
+	{Method: "CreateOrder", Args: []any{1, []int{}}},
+	{Method: "GetOrderStatus", Args: []any{1}},
+	{Method: "CalculateShipping", Args: []any{1}},
+	{Method: "TrackOrder", Args: []any{1}},
+	{Method: "CancelOrder", Args: []any{1}},
+
+	// Payments
+	{Method: "ValidateCreditCard", Args: []any{"4111111111111111"}},
+	{Method: "ValidateCreditCard", Args: []any{"1234"}},
+	{Method: "ProcessPayment", Args: []any{1, "credit_card"}},
+	{Method: "RefundPayment", Args: []any{"txn-1"}},
+	{Method: "GetPaymentHistory", Args: []any{1}},
+
+	// Email
+	{Method: "SendEmail", Args: []any{"bob@example.com", "Hi", "Hello, Bob"}},
+	{Method: "SendWelcomeEmail", Args: []any{1}},
+	{Method: "SendOrderConfirmation", Args: []any{1}},
+	{Method: "SendPasswordResetEmail", Args: []any{"alice@example.com"}},
+	{Method: "QueueEmail", Args: []any{godobject.Email{To: "bob@example.com", Subject: "Queued", Body: "Later"}}},
+
+	// Logging
+	{Method: "LogInfo", Args: []any{"started"}},
+	{Method: "LogError", Args: []any{"failed"}},
+	{Method: "ExportLogs", Args: []any{"json"}},
+	{Method: "ClearLogs", Args: []any{}},
+	{Method: "ExportLogs", Args: []any{"text"}},
+
+	// Cache
+	{Method: "CacheGet", Args: []any{"user:1"}},
+	{Method: "CacheSet", Args: []any{"user:1", map[string]any{"name": "alice"}, time.Minute}},
+	{Method: "CacheGet", Args: []any{"user:1"}},
+	{Method: "CacheSet", Args: []any{"stale", "value", -time.Hour}},
+	{Method: "CacheGet", Args: []any{"stale"}},
+	{Method: "CacheSet", Args: []any{"user:1", 42, time.Minute}},
+	{Method: "CacheGet", Args: []any{"user:1"}},
+	{Method: "CacheInvalidate", Args: []any{"user:1"}},
+	{Method: "CacheGet", Args: []any{"user:1"}},
+	{Method: "CacheClearAll", Args: []any{}},
+	{Method: "CacheGet", Args: []any{"stale"}},
+
+	// Files
+	{Method: "UploadFile", Args: []any{[]byte("hello"), 1}},
+	{Method: "GetFileURL", Args: []any{"file-1"}},
+	{Method: "DeleteFile", Args: []any{"file-1"}},
+
+	// Notifications
+	{Method: "SendNotification", Args: []any{1, "Your order shipped"}},
+	{Method: "GetUnreadNotifications", Args: []any{1}},
+	{Method: "MarkNotificationRead", Args: []any{1}},
+
+	// Analytics
+	{Method: "TrackPageView", Args: []any{1, "/home"}},
+	{Method: "TrackEvent", Args: []any{1, "checkout", map[string]any{"total": 99.5}}},
+	{Method: "GenerateAnalyticsReport", Args: []any{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}},
+
+	// Configuration and feature flags
+	{Method: "GetConfig", Args: []any{"timeout"}},
+	{Method: "SetConfig", Args: []any{"timeout", 30}},
+	{Method: "GetConfig", Args: []any{"timeout"}},
+	{Method: "SetConfig", Args: []any{"timeout", "30s"}},
+	{Method: "GetConfig", Args: []any{"timeout"}},
+	{Method: "SetConfig", Args: []any{"new_ui", true}},
+	{Method: "IsFeatureEnabled", Args: []any{"new_ui"}},
+	{Method: "IsFeatureEnabled", Args: []any{"dark_mode"}},
+}
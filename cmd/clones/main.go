@@ -0,0 +1,15 @@
+// Command clones runs the clones analyzer, which reports groups of
+// copy-pasted functions.
+//
+// Usage:
+//
+//	clones [-threshold=0.9] [-min-tokens=12] packages...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/clones"
+)
+
+func main() { singlechecker.Main(clones.Analyzer) }
@@ -0,0 +1,17 @@
+// Command commentedcode runs the commentedcode analyzer, which reports
+// comments that contain Go code rather than prose.
+//
+// Usage:
+//
+//	commentedcode [-fix] [-diff] packages...
+//
+// With -fix, the commented-out code is deleted from the source files.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/commentedcode"
+)
+
+func main() { singlechecker.Main(commentedcode.Analyzer) }
@@ -0,0 +1,160 @@
+// Command complexity reports functions whose cyclomatic complexity,
+// cognitive complexity or if nesting exceed configurable thresholds.
+//
+// Usage:
+//
+//	complexity [-format=text|json|sarif] [-all] [-cyclomatic=N] [-cognitive=N] [-nesting=N] packages...
+//
+// The text format prints one line per exceeded threshold, like go vet. The
+// json format prints the metrics of every reported function (or, with
+// -all, of every function) as a JSON array. The sarif format prints a SARIF
+// 2.1.0 log for code-review tooling. The command exits 3 when anything was
+// reported.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/bclements/antipatterns/analyzers/complexity"
+	"github.com/bclements/antipatterns/internal/driver"
+	"github.com/bclements/antipatterns/internal/sarif"
+)
+
+var (
+	format = flag.String("format", "text", "output format: text, json or sarif")
+	all    = flag.Bool("all", false, "with -format=json, include functions under every threshold")
+)
+
+// funcReport is the JSON form of one function's metrics.
+type funcReport struct {
+	Package    string   `json:"package"`
+	Function   string   `json:"function"`
+	File       string   `json:"file"`
+	Line       int      `json:"line"`
+	Cyclomatic int      `json:"cyclomatic"`
+	Cognitive  int      `json:"cognitive"`
+	Nesting    int      `json:"nesting"`
+	Exceeds    []string `json:"exceeds,omitempty"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("complexity: ")
+	complexity.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: complexity [flags] packages...\n\n%s\n\nFlags:\n", complexity.Doc)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	pkgs, err := driver.Load("", false, flag.Args()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	graph, err := driver.Analyze(pkgs, complexity.Analyzer)
+	if err != nil {
+		log.Fatal(err)
+	}
+	findings, err := driver.Findings(graph)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch *format {
+	case "text":
+		for _, f := range findings {
+			fmt.Printf("%s: %s\n", f.Pos, f.Message)
+		}
+	case "json":
+		err = writeJSON(os.Stdout, graph, findings)
+	case "sarif":
+		wd, _ := os.Getwd()
+		err = writeSARIF(os.Stdout, wd, findings)
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(findings) > 0 {
+		os.Exit(3)
+	}
+}
+
+func writeJSON(w io.Writer, graph *checker.Graph, findings []driver.Finding) error {
+	exceeds := make(map[token.Position][]string)
+	for _, f := range findings {
+		exceeds[f.Pos] = append(exceeds[f.Pos], f.Category)
+	}
+	reports := []funcReport{}
+	for _, act := range graph.Roots {
+		for _, fn := range act.Result.([]*complexity.Func) {
+			pos := act.Package.Fset.Position(fn.Pos)
+			if !*all && len(exceeds[pos]) == 0 {
+				continue
+			}
+			reports = append(reports, funcReport{
+				Package:    act.Package.PkgPath,
+				Function:   fn.Name,
+				File:       pos.Filename,
+				Line:       pos.Line,
+				Cyclomatic: fn.Cyclomatic,
+				Cognitive:  fn.Cognitive,
+				Nesting:    fn.MaxNesting,
+				Exceeds:    exceeds[pos],
+			})
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].File != reports[j].File {
+			return reports[i].File < reports[j].File
+		}
+		return reports[i].Line < reports[j].Line
+	})
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+// writeSARIF writes findings as a SARIF log whose file locations are
+// relative to root.
+func writeSARIF(w io.Writer, root string, findings []driver.Finding) error {
+	doc := sarif.New(sarif.Driver{
+		Name:           complexity.Analyzer.Name,
+		InformationURI: complexity.Analyzer.URL,
+	})
+	run := doc.Run()
+	for _, rule := range []sarif.Rule{
+		{ID: complexity.CategoryCyclomatic, Name: "CyclomaticComplexity", ShortDescription: &sarif.Message{Text: "Function has too many independent paths"}},
+		{ID: complexity.CategoryCognitive, Name: "CognitiveComplexity", ShortDescription: &sarif.Message{Text: "Function is hard to follow"}},
+		{ID: complexity.CategoryNesting, Name: "IfNestingDepth", ShortDescription: &sarif.Message{Text: "If statements are nested too deeply"}},
+	} {
+		rule.HelpURI = complexity.Analyzer.URL
+		rule.DefaultConfiguration = &sarif.Configuration{Level: sarif.LevelWarning}
+		run.AddRule(rule)
+	}
+	for _, f := range findings {
+		run.Results = append(run.Results, sarif.Result{
+			RuleID:    f.Category,
+			Level:     sarif.LevelWarning,
+			Message:   sarif.Message{Text: f.Message},
+			Locations: []sarif.Location{sarif.FileLocation(root, f.Pos.Filename, f.Pos.Line, f.Pos.Column, f.End.Line, f.End.Column)},
+		})
+	}
+	return doc.Write(w)
+}
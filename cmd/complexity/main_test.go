@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bclements/antipatterns/analyzers/complexity"
+	"github.com/bclements/antipatterns/internal/driver"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// TestSARIF checks the SARIF log for the Spaghetti Code example against
+// testdata/spaghetticode.sarif.
+func TestSARIF(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := driver.Load(root, false, "./golangexamples/spaghetticode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, err := driver.Analyze(pkgs, complexity.Analyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := driver.Findings(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := writeSARIF(&buf, root, findings); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "spaghetticode.sarif")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("SARIF log differs from %s; run go test -update to accept it:\n%s", golden, got)
+	}
+}
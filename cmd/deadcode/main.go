@@ -0,0 +1,18 @@
+// Command deadcode runs the deadcode analyzer, which reports unreachable
+// statements, duplicate and impossible conditions and loops over empty
+// literals.
+//
+// Usage:
+//
+//	deadcode [-fix] [-diff] packages...
+//
+// With -fix, the suggested deletions are applied to the source files.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/deadcode"
+)
+
+func main() { singlechecker.Main(deadcode.Analyzer) }
@@ -0,0 +1,389 @@
+// Command debtscore ranks Go files and packages by anti-pattern debt and
+// shows how the debt has moved over recent commits.
+//
+// Usage:
+//
+//	debtscore [-weights=name=value,...] [-history=N] [-html=file] [-top=N] dirs...
+//
+// A directory ending in /... includes its subdirectories. Every non-test Go
+// file is parsed, without type checking, and measured:
+//
+//	methods      the most methods declared on one type
+//	nesting      the deepest nesting of if, for, switch and select
+//	duplication  the share of its code lines that occur more than once in its package
+//	unreachable  statements after a return, panic, break, continue or goto
+//	credentials  string literals that look like keys, passwords or tokens
+//	markers      anti-pattern marker comments such as // DEAD CODE:
+//
+// A file's score is
+//
+//	methods·max(0, M-5) + nesting·max(0, N-3) + duplication·100·D
+//	  + unreachable·U + credentials·C + markers·K
+//
+// with the weights given by -weights, e.g. -weights=markers=0,nesting=5;
+// the defaults are listed by -help. A package's score is the sum of its
+// files'.
+//
+// The ranking is printed as a table. With -html, debtscore also writes an
+// HTML report that charts each file's and package's score over the last
+// -history commits of the git repository the directories are in, ending
+// with the working tree.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var (
+	weightsFlag = flag.String("weights", "", "comma-separated name=weight overrides of the default weights")
+	history     = flag.Int("history", 10, "number of commits to chart in the HTML report")
+	htmlOut     = flag.String("html", "", "write an HTML report to this file")
+	top         = flag.Int("top", 0, "print only the N highest-scoring files (0 for all)")
+)
+
+// weights multiply each metric's contribution to a score.
+type weights struct {
+	Methods, Nesting, Duplication, Unreachable, Credentials, Markers float64
+}
+
+var defaultWeights = weights{
+	Methods:     2,
+	Nesting:     4,
+	Duplication: 0.5,
+	Unreachable: 1,
+	Credentials: 2,
+	Markers:     0.25,
+}
+
+func (w *weights) set(spec string) error {
+	fields := map[string]*float64{
+		"methods":     &w.Methods,
+		"nesting":     &w.Nesting,
+		"duplication": &w.Duplication,
+		"unreachable": &w.Unreachable,
+		"credentials": &w.Credentials,
+		"markers":     &w.Markers,
+	}
+	for _, kv := range strings.Split(spec, ",") {
+		if strings.TrimSpace(kv) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(kv, "=")
+		p := fields[strings.TrimSpace(name)]
+		if !ok || p == nil {
+			return fmt.Errorf("bad weight %q: want one of methods, nesting, duplication, unreachable, credentials or markers, then =number", kv)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("bad weight %q: %v", kv, err)
+		}
+		*p = f
+	}
+	return nil
+}
+
+func (w weights) score(m metrics) float64 {
+	s := w.Methods*float64(max(0, m.Methods-5)) +
+		w.Nesting*float64(max(0, m.Nesting-3)) +
+		w.Duplication*100*m.DupRatio +
+		w.Unreachable*float64(m.Unreachable) +
+		w.Credentials*float64(m.Credentials) +
+		w.Markers*float64(m.Markers)
+	return float64(int(s*10+0.5)) / 10
+}
+
+// A fileScore is one file's measurements at one point in time.
+type fileScore struct {
+	File    string
+	Package string
+	Score   float64
+	metrics
+}
+
+// A snapshot is the scores of every file at one commit, or of the working
+// tree.
+type snapshot struct {
+	Label string // short commit hash, or "working tree"
+	Date  string
+	Title string
+	Files map[string]*fileScore
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("debtscore: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: debtscore [flags] dirs...\n\nDefault weights: methods=%g,nesting=%g,duplication=%g,unreachable=%g,credentials=%g,markers=%g\n\nFlags:\n",
+			defaultWeights.Methods, defaultWeights.Nesting, defaultWeights.Duplication, defaultWeights.Unreachable, defaultWeights.Credentials, defaultWeights.Markers)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	w := defaultWeights
+	if err := w.set(*weightsFlag); err != nil {
+		log.Fatal(err)
+	}
+
+	root := gitRoot()
+	inGit := root != ""
+	if !inGit {
+		root, _ = os.Getwd()
+	}
+	var dirs []dirPattern
+	for _, arg := range flag.Args() {
+		d, err := parseDir(root, arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dirs = append(dirs, d)
+	}
+
+	files, err := workTree(root, dirs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	current, err := measure(w, files)
+	if err != nil {
+		log.Fatal(err)
+	}
+	current.Label = "working tree"
+
+	printed := sortedFiles(current)
+	if *top > 0 && len(printed) > *top {
+		printed = printed[:*top]
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "SCORE\tMETHODS\tNESTING\tDUP%\tUNREACHABLE\tCREDENTIALS\tMARKERS\t\tFILE")
+	for _, f := range printed {
+		fmt.Fprintf(tw, "%.1f\t%d\t%d\t%.0f\t%d\t%d\t%d\t\t%s\n", f.Score, f.Methods, f.Nesting, 100*f.DupRatio, f.Unreachable, f.Credentials, f.Markers, f.File)
+	}
+	tw.Flush()
+
+	if *htmlOut == "" {
+		return
+	}
+	var snaps []*snapshot
+	if inGit && *history > 0 {
+		commits, err := recentCommits(root, *history)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i := len(commits) - 1; i >= 0; i-- {
+			c := commits[i]
+			files, err := commitTree(root, c.hash, dirs)
+			if err != nil {
+				log.Fatal(err)
+			}
+			s, err := measure(w, files)
+			if err != nil {
+				log.Printf("skipping %s: %v", c.hash[:7], err)
+				continue
+			}
+			s.Label, s.Date, s.Title = c.hash[:7], c.date, c.title
+			snaps = append(snaps, s)
+		}
+	}
+	snaps = append(snaps, current)
+
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, w, snaps); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*htmlOut, buf.Bytes(), 0o666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// measure scores files package by package.
+func measure(w weights, files []source) (*snapshot, error) {
+	byPkg := make(map[string][]source)
+	for _, f := range files {
+		dir := path.Dir(f.name)
+		byPkg[dir] = append(byPkg[dir], f)
+	}
+	s := &snapshot{Files: make(map[string]*fileScore)}
+	for pkg, srcs := range byPkg {
+		ms, err := measurePackage(srcs)
+		if err != nil {
+			return nil, err
+		}
+		for name, m := range ms {
+			s.Files[name] = &fileScore{File: name, Package: pkg, Score: w.score(m), metrics: m}
+		}
+	}
+	return s, nil
+}
+
+// sortedFiles returns the files of s, highest score first.
+func sortedFiles(s *snapshot) []*fileScore {
+	var out []*fileScore
+	for _, f := range s.Files {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].File < out[j].File
+	})
+	return out
+}
+
+// A dirPattern is a directory relative to the root, and whether its
+// subdirectories are included.
+type dirPattern struct {
+	dir       string // slash-separated; "." for the root
+	recursive bool
+}
+
+func parseDir(root, arg string) (dirPattern, error) {
+	d := dirPattern{}
+	if rest, ok := strings.CutSuffix(arg, "/..."); ok {
+		arg, d.recursive = rest, true
+	} else if arg == "..." {
+		arg, d.recursive = ".", true
+	}
+	abs, err := filepath.Abs(arg)
+	if err != nil {
+		return d, err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || !filepath.IsLocal(rel) && rel != "." {
+		return d, fmt.Errorf("%s is outside %s", arg, root)
+	}
+	d.dir = filepath.ToSlash(rel)
+	return d, nil
+}
+
+// matches reports whether the file name, relative to the root, is a
+// non-test Go file the pattern covers.
+func (d dirPattern) matches(name string) bool {
+	if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+	dir := path.Dir(name)
+	if !d.recursive {
+		return dir == d.dir
+	}
+	if d.dir != "." && dir != d.dir && !strings.HasPrefix(dir, d.dir+"/") {
+		return false
+	}
+	for _, elem := range strings.Split(dir, "/") {
+		if elem == "testdata" || elem == "vendor" || strings.HasPrefix(elem, ".") && elem != "." || strings.HasPrefix(elem, "_") {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(dirs []dirPattern, name string) bool {
+	for _, d := range dirs {
+		if d.matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// workTree returns the matching files on disk.
+func workTree(root string, dirs []dirPattern) ([]source, error) {
+	var out []source
+	err := filepath.WalkDir(root, func(p string, e os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			if p != root && (strings.HasPrefix(e.Name(), ".") || e.Name() == "vendor" || e.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if !matchesAny(dirs, rel) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		out = append(out, source{name: rel, data: data})
+		return nil
+	})
+	return out, err
+}
+
+// commitTree returns the matching files as they were at a commit.
+func commitTree(root, hash string, dirs []dirPattern) ([]source, error) {
+	names, err := git(root, "ls-tree", "-r", "--name-only", hash)
+	if err != nil {
+		return nil, err
+	}
+	var out []source
+	for _, name := range strings.Split(strings.TrimSpace(string(names)), "\n") {
+		if !matchesAny(dirs, name) {
+			continue
+		}
+		data, err := git(root, "show", hash+":"+name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, source{name: name, data: data})
+	}
+	return out, nil
+}
+
+type commit struct {
+	hash, date, title string
+}
+
+// recentCommits returns up to n commits reachable from HEAD, newest first.
+func recentCommits(root string, n int) ([]commit, error) {
+	out, err := git(root, "log", "-n", strconv.Itoa(n), "--format=%H%x00%cs%x00%s")
+	if err != nil {
+		return nil, err
+	}
+	var commits []commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if f := strings.Split(line, "\x00"); len(f) == 3 {
+			commits = append(commits, commit{f[0], f[1], f[2]})
+		}
+	}
+	return commits, nil
+}
+
+// gitRoot returns the top directory of the git work tree containing the
+// current directory, or "" outside one.
+func gitRoot() string {
+	out, err := git("", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
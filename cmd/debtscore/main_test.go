@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRanking scores the examples with the default weights: the God Object
+// and the Spaghetti Code carry the most debt.
+func TestRanking(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := workTree(root, []dirPattern{{dir: "golangexamples", recursive: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := measure(defaultWeights, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranked := sortedFiles(s)
+	if len(ranked) < 10 {
+		t.Fatalf("%d files scored, want the 10 examples and more", len(ranked))
+	}
+	want := []string{"golangexamples/godobject/god_object.go", "golangexamples/spaghetticode/spaghetti_code.go"}
+	for i, name := range want {
+		if ranked[i].File != name {
+			t.Errorf("#%d is %s (%.1f), want %s", i+1, ranked[i].File, ranked[i].Score, name)
+		}
+	}
+}
+
+func TestWeightsSet(t *testing.T) {
+	for _, test := range []struct {
+		spec string
+		want weights
+		err  string
+	}{
+		{spec: "", want: defaultWeights},
+		{spec: "markers=0,nesting=5", want: weights{Methods: 2, Nesting: 5, Duplication: 0.5, Unreachable: 1, Credentials: 2}},
+		{spec: " methods = 1.5 ,, credentials=0 ", want: weights{Methods: 1.5, Nesting: 4, Duplication: 0.5, Unreachable: 1, Markers: 0.25}},
+		{spec: "complexity=1", err: `bad weight "complexity=1": want one of`},
+		{spec: "methods", err: `bad weight "methods": want one of`},
+		{spec: "methods=many", err: `bad weight "methods=many": strconv.ParseFloat`},
+	} {
+		w := defaultWeights
+		err := w.set(test.spec)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("set(%q): %v, want an error containing %q", test.spec, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("set(%q): %v", test.spec, err)
+		} else if w != test.want {
+			t.Errorf("set(%q) = %+v, want %+v", test.spec, w, test.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	for _, test := range []struct {
+		m    metrics
+		want float64
+	}{
+		{metrics{}, 0},
+		{metrics{Methods: 5, Nesting: 3}, 0},                  // at the thresholds
+		{metrics{Methods: 8}, 6},                              // 2·3
+		{metrics{Nesting: 6}, 12},                             // 4·3
+		{metrics{DupRatio: 0.25}, 12.5},                       // 0.5·100·0.25
+		{metrics{Unreachable: 3, Credentials: 2}, 7},          // 1·3 + 2·2
+		{metrics{Markers: 3}, 0.8},                            // 0.75, rounded to one decimal
+		{metrics{Methods: 54, DupRatio: 1.0 / 30}, 99.7},      // 98 + 1.67
+		{metrics{Nesting: 4, DupRatio: 0.1, Markers: 1}, 9.3}, // 4 + 5 + 0.25
+	} {
+		if got := defaultWeights.score(test.m); got != test.want {
+			t.Errorf("score(%+v) = %v, want %v", test.m, got, test.want)
+		}
+	}
+}
+
+const nested = `package p
+
+type T struct{}
+
+func (T) A() {}
+func (*T) B() {}
+func (T) C() {}
+
+type U struct{}
+
+func (U) A() {}
+
+func f(xs []int, ch chan int) {
+	for _, x := range xs {
+		if x > 0 {
+			switch {
+			case x > 1:
+				select {
+				case ch <- x:
+				}
+			}
+		}
+	}
+	go func() {
+		if true {
+		}
+	}()
+}
+`
+
+const unreachableSrc = `package p
+
+func g(x int) int {
+	switch x {
+	case 1:
+		return 1
+		x++
+	}
+	for {
+		break
+		x--
+		x--
+	}
+	if x > 0 {
+		panic("x")
+		println(x)
+	}
+	goto end
+	x = 2
+end:
+	return x
+	println("never")
+}
+`
+
+// The first two lines of each function body are the same in both files.
+const (
+	dup1 = `package p
+
+func a(values []string) string {
+	result := strings.Join(values, ", ")
+	result = strings.TrimSpace(result)
+	return "a" + result
+}
+`
+	dup2 = `package p
+
+func b(values []string) string {
+	result := strings.Join(values, ", ")
+	result = strings.TrimSpace(result)
+	if result == "" { return "" }
+	return strings.ToUpper(result)
+}
+`
+)
+
+func TestMeasurePackage(t *testing.T) {
+	ms, err := measurePackage([]source{
+		{"p/nested.go", []byte(nested)},
+		{"p/unreachable.go", []byte(unreachableSrc)},
+		{"p/dup1.go", []byte(dup1)},
+		{"p/dup2.go", []byte(dup2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := ms["p/nested.go"]; m.Methods != 3 || m.Nesting != 4 {
+		t.Errorf("nested.go: %d methods per type, nesting %d; want 3, 4", m.Methods, m.Nesting)
+	}
+	// x++, both x--, println(x), and x = 2 up to the label. Only what
+	// follows a block's first terminating statement counts, so the
+	// println after the labeled return does not.
+	if m := ms["p/unreachable.go"]; m.Unreachable != 5 {
+		t.Errorf("unreachable.go: %d unreachable statements, want 5", m.Unreachable)
+	}
+	// dup1.go's lines of code are its signature, the two shared lines and
+	// its return; dup2.go adds an if and a different return.
+	for name, want := range map[string]metrics{
+		"p/dup1.go": {Lines: 4, Duplicated: 2, DupRatio: 0.5},
+		"p/dup2.go": {Lines: 5, Duplicated: 2, DupRatio: 0.4},
+	} {
+		m := ms[name]
+		if m.Lines != want.Lines || m.Duplicated != want.Duplicated || m.DupRatio != want.DupRatio {
+			t.Errorf("%s: %d of %d lines duplicated (%.2f), want %d of %d (%.2f)", name, m.Duplicated, m.Lines, m.DupRatio, want.Duplicated, want.Lines, want.DupRatio)
+		}
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		arg  string
+		want dirPattern
+		err  bool
+	}{
+		{arg: ".", want: dirPattern{dir: "."}},
+		{arg: "...", want: dirPattern{dir: ".", recursive: true}},
+		{arg: "testdata", want: dirPattern{dir: "testdata"}},
+		{arg: "./a/b/...", want: dirPattern{dir: "a/b", recursive: true}},
+		{arg: filepath.Join(root, "a") + "/...", want: dirPattern{dir: "a", recursive: true}},
+		{arg: "../...", err: true},
+		{arg: "/", err: true},
+	} {
+		got, err := parseDir(root, test.arg)
+		switch {
+		case test.err && err == nil:
+			t.Errorf("parseDir(%q) = %+v, want an error", test.arg, got)
+		case !test.err && err != nil:
+			t.Errorf("parseDir(%q): %v", test.arg, err)
+		case !test.err && got != test.want:
+			t.Errorf("parseDir(%q) = %+v, want %+v", test.arg, got, test.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	for _, test := range []struct {
+		pattern dirPattern
+		name    string
+		want    bool
+	}{
+		{dirPattern{dir: "a"}, "a/x.go", true},
+		{dirPattern{dir: "a"}, "a/x_test.go", false},
+		{dirPattern{dir: "a"}, "a/x.txt", false},
+		{dirPattern{dir: "a"}, "a/b/x.go", false},
+		{dirPattern{dir: "a"}, "ab/x.go", false},
+		{dirPattern{dir: "a", recursive: true}, "a/x.go", true},
+		{dirPattern{dir: "a", recursive: true}, "a/b/c/x.go", true},
+		{dirPattern{dir: "a", recursive: true}, "ab/x.go", false},
+		{dirPattern{dir: "a", recursive: true}, "a/testdata/x.go", false},
+		{dirPattern{dir: "a", recursive: true}, "a/vendor/x.go", false},
+		{dirPattern{dir: "a", recursive: true}, "a/.git/x.go", false},
+		{dirPattern{dir: "a", recursive: true}, "a/_old/x.go", false},
+		{dirPattern{dir: ".", recursive: true}, "x.go", true},
+		{dirPattern{dir: ".", recursive: true}, "a/b/x.go", true},
+		{dirPattern{dir: "."}, "x.go", true},
+		{dirPattern{dir: "."}, "a/x.go", false},
+	} {
+		if got := test.pattern.matches(test.name); got != test.want {
+			t.Errorf("%+v.matches(%q) = %t, want %t", test.pattern, test.name, got, test.want)
+		}
+	}
+}
+
+// TestWriteHTML charts two snapshots: a.go's debt grows, b.go is new and
+// c.go is gone.
+func TestWriteHTML(t *testing.T) {
+	file := func(name string, score float64) *fileScore {
+		return &fileScore{File: "p/" + name, Package: "p", Score: score}
+	}
+	snaps := []*snapshot{
+		{Label: "abc1234", Date: "2026-10-01", Title: "Add a and c", Files: map[string]*fileScore{
+			"p/a.go": file("a.go", 10),
+			"p/c.go": file("c.go", 2),
+		}},
+		{Label: "working tree", Files: map[string]*fileScore{
+			"p/a.go": file("a.go", 15),
+			"p/b.go": file("b.go", 5),
+		}},
+	}
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, defaultWeights, snaps); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		"<h2>Total over 2 snapshots</h2>",
+		`<polyline points="0.0,48.0 600.0,0.0"/>`, // the total rises from 12 to 20
+		`<td class="name">abc1234</td><td class="name">2026-10-01</td><td>12.0</td><td class="name">Add a and c</td>`,
+		`<td class="name">working tree</td><td class="name"></td><td>20.0</td>`,
+		`<td class="name">p</td><td>2</td><td>20.0</td>` + "\n" + `<td class="up">&#43;8.0</td>` + "\n" + `<td><svg width="100" height="24" viewBox="-2 -2 104 28"><polyline points="0.0,9.6 100.0,0.0"/></svg></td>`,
+		`<td>1</td><td class="name">p/a.go</td><td>15.0</td>` + "\n" + `<td class="up">&#43;5.0</td>` + "\n" + `<td><svg width="100" height="24" viewBox="-2 -2 104 28"><polyline points="0.0,8.0 100.0,0.0"/></svg></td>`,
+		`<td>2</td><td class="name">p/b.go</td><td>5.0</td>` + "\n" + `<td class="">0.0</td>` + "\n" + `<td><svg width="100" height="24" viewBox="-2 -2 104 28"><polyline points="100.0,0.0"/></svg></td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report lacks %q", want)
+		}
+	}
+	if strings.Contains(html, "p/c.go") {
+		t.Error("report lists p/c.go, which is gone")
+	}
+}
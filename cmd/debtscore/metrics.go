@@ -0,0 +1,280 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bclements/antipatterns/internal/markers"
+)
+
+// metrics are the measurements of one file, or of a package's files.
+type metrics struct {
+	Methods     int     `json:"methods_per_type"`      // most methods declared on one type
+	Nesting     int     `json:"max_nesting"`           // deepest nesting of control statements
+	Lines       int     `json:"code_lines"`            // lines counted for duplication
+	Duplicated  int     `json:"duplicated_lines"`      // of those, lines that occur more than once in the package
+	DupRatio    float64 `json:"duplicated_line_ratio"` // Duplicated / Lines
+	Unreachable int     `json:"unreachable_statements"`
+	Credentials int     `json:"credential_literals"`
+	Markers     int     `json:"marker_comments"`
+}
+
+// add accumulates m into a package total: maxima for the structural
+// metrics, sums for the counts.
+func (t *metrics) add(m metrics) {
+	t.Methods = max(t.Methods, m.Methods)
+	t.Nesting = max(t.Nesting, m.Nesting)
+	t.Lines += m.Lines
+	t.Duplicated += m.Duplicated
+	t.DupRatio = ratio(t.Duplicated, t.Lines)
+	t.Unreachable += m.Unreachable
+	t.Credentials += m.Credentials
+	t.Markers += m.Markers
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// A source is one Go file's name and content.
+type source struct {
+	name string // slash-separated, relative to the repository root
+	data []byte
+}
+
+// measurePackage measures the files of one package. Duplicated lines are
+// counted across the whole package, so a function pasted into a second
+// file counts in both.
+func measurePackage(files []source) (map[string]metrics, error) {
+	fset := token.NewFileSet()
+	parsed := make([]*ast.File, len(files))
+	for i, src := range files {
+		f, err := parser.ParseFile(fset, src.name, src.data, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = f
+	}
+
+	lines := make([][]string, len(files))
+	seen := make(map[string]int)
+	for i, src := range files {
+		lines[i] = codeLines(fset, parsed[i], src.data)
+		for _, l := range lines[i] {
+			seen[l]++
+		}
+	}
+
+	out := make(map[string]metrics)
+	for i, f := range parsed {
+		m := metrics{
+			Methods:     methodsPerType(f),
+			Nesting:     maxNesting(f),
+			Lines:       len(lines[i]),
+			Unreachable: unreachable(f),
+			Credentials: credentials(f),
+			Markers:     len(markers.Find(f)),
+		}
+		for _, l := range lines[i] {
+			if seen[l] > 1 {
+				m.Duplicated++
+			}
+		}
+		m.DupRatio = ratio(m.Duplicated, m.Lines)
+		out[files[i].name] = m
+	}
+	return out, nil
+}
+
+// methodsPerType returns the largest number of methods the file declares
+// on a single receiver type.
+func methodsPerType(f *ast.File) int {
+	counts := make(map[string]int)
+	most := 0
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || len(fd.Recv.List) == 0 {
+			continue
+		}
+		t := fd.Recv.List[0].Type
+		if s, ok := t.(*ast.StarExpr); ok {
+			t = s.X
+		}
+		if id, ok := t.(*ast.Ident); ok {
+			counts[id.Name]++
+			most = max(most, counts[id.Name])
+		}
+	}
+	return most
+}
+
+// maxNesting returns the deepest nesting of if, for, range, switch and
+// select statements in any function of the file.
+func maxNesting(f *ast.File) int {
+	deepest := 0
+	var visit func(n ast.Node, depth int)
+	visit = func(n ast.Node, depth int) {
+		ast.Inspect(n, func(c ast.Node) bool {
+			if c == n {
+				return true
+			}
+			switch c.(type) {
+			case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				deepest = max(deepest, depth+1)
+				visit(c, depth+1)
+				return false
+			case *ast.FuncLit:
+				visit(c, 0)
+				return false
+			}
+			return true
+		})
+	}
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Body != nil {
+			visit(fd.Body, 0)
+		}
+	}
+	return deepest
+}
+
+// codeLines returns the file's lines of code, trimmed, leaving out blank
+// lines, comments, imports and lines too short to be worth copying, such
+// as "}" or "return nil".
+func codeLines(fset *token.FileSet, f *ast.File, data []byte) []string {
+	tf := fset.File(f.Pos())
+	skip := make(map[int]bool)
+	for _, cg := range f.Comments {
+		for line := tf.Line(cg.Pos()); line <= tf.Line(cg.End()); line++ {
+			skip[line] = true
+		}
+	}
+	for _, d := range f.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			for line := tf.Line(gd.Pos()); line <= tf.Line(gd.End()); line++ {
+				skip[line] = true
+			}
+		}
+	}
+	var out []string
+	for i, l := range strings.Split(string(data), "\n") {
+		l = strings.Join(strings.Fields(l), " ")
+		if skip[i+1] || len(l) < 16 {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// unreachable counts the statements that follow a return, panic, break,
+// continue or goto in the same block.
+func unreachable(f *ast.File) int {
+	n := 0
+	count := func(list []ast.Stmt) {
+		for i, s := range list {
+			if terminates(s) {
+				for _, rest := range list[i+1:] {
+					if _, ok := rest.(*ast.LabeledStmt); ok {
+						break
+					}
+					n++
+				}
+				return
+			}
+		}
+	}
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.BlockStmt:
+			count(node.List)
+		case *ast.CaseClause:
+			count(node.Body)
+		case *ast.CommClause:
+			count(node.Body)
+		}
+		return true
+	})
+	return n
+}
+
+func terminates(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok != token.FALLTHROUGH
+	case *ast.ExprStmt:
+		if call, ok := s.X.(*ast.CallExpr); ok {
+			if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "panic" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var (
+	credentialName  = regexp.MustCompile(`(?i)(passw(or)?d|pwd|secret|token|api_?key|private_?key|access_?key|credential)`)
+	credentialValue = regexp.MustCompile(`^(?:[sr]k_live_|pk_live_|AKIA|ASIA|AIza|SG\.|ghp_|xox[bp]-|-----BEGIN)|^https://hooks\.slack\.com/|^[a-zA-Z][a-zA-Z0-9+.-]*://[^:/@\s]+:[^@/\s]+@`)
+)
+
+// credentials counts string literals that look like credentials: values
+// with the shape of a well-known key or a URL with a password, and
+// non-empty values assigned to credential-like names.
+func credentials(f *ast.File) int {
+	n := 0
+	named := func(name ast.Expr, value ast.Expr) {
+		id, ok := name.(*ast.Ident)
+		if !ok || !credentialName.MatchString(id.Name) {
+			return
+		}
+		if s, ok := stringLit(value); ok && s != "" && !credentialValue.MatchString(s) {
+			n++
+		}
+	}
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.BasicLit:
+			if s, ok := stringLit(node); ok && credentialValue.MatchString(s) {
+				n++
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if i < len(node.Values) {
+					named(name, node.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) == len(node.Rhs) {
+				for i := range node.Lhs {
+					if sel, ok := node.Lhs[i].(*ast.SelectorExpr); ok {
+						named(sel.Sel, node.Rhs[i])
+					} else {
+						named(node.Lhs[i], node.Rhs[i])
+					}
+				}
+			}
+		case *ast.KeyValueExpr:
+			named(node.Key, node.Value)
+		}
+		return true
+	})
+	return n
+}
+
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
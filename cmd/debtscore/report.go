@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A trend is a score at each snapshot, with gaps where the file or package
+// did not exist.
+type trend struct {
+	Scores []float64
+	Exists []bool
+}
+
+// Points returns the trend as the points of an SVG polyline of the given
+// size, scaled to top.
+func (t trend) Points(width, height int, top float64) string {
+	if top <= 0 {
+		top = 1
+	}
+	n := len(t.Scores)
+	var b strings.Builder
+	for i, s := range t.Scores {
+		if !t.Exists[i] {
+			continue
+		}
+		x := float64(width) / 2
+		if n > 1 {
+			x = float64(i) * float64(width) / float64(n-1)
+		}
+		y := float64(height) - s/top*float64(height)
+		fmt.Fprintf(&b, "%.1f,%.1f ", x, y)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Change returns the score's change from the first snapshot it existed in
+// to the last.
+func (t trend) Change() float64 {
+	for i, ok := range t.Exists {
+		if ok {
+			return t.Scores[len(t.Scores)-1] - t.Scores[i]
+		}
+	}
+	return 0
+}
+
+func (t trend) Max() float64 {
+	m := 0.0
+	for _, s := range t.Scores {
+		m = max(m, s)
+	}
+	return m
+}
+
+type fileRow struct {
+	*fileScore
+	Trend trend
+}
+
+type packageRow struct {
+	Package string
+	Files   int
+	Score   float64
+	metrics
+	Trend trend
+}
+
+type report struct {
+	Weights   weights
+	Snapshots []*snapshot
+	Total     trend
+	Packages  []*packageRow
+	Files     []*fileRow
+}
+
+func writeHTML(w io.Writer, wt weights, snaps []*snapshot) error {
+	r := &report{Weights: wt, Snapshots: snaps}
+	last := snaps[len(snaps)-1]
+	n := len(snaps)
+
+	r.Total = trend{Scores: make([]float64, n), Exists: make([]bool, n)}
+	pkgs := make(map[string]*packageRow)
+	for i, s := range snaps {
+		r.Total.Exists[i] = true
+		for _, f := range s.Files {
+			r.Total.Scores[i] += f.Score
+			p := pkgs[f.Package]
+			if p == nil {
+				p = &packageRow{Package: f.Package, Trend: trend{Scores: make([]float64, n), Exists: make([]bool, n)}}
+				pkgs[f.Package] = p
+			}
+			p.Trend.Scores[i] += f.Score
+			p.Trend.Exists[i] = true
+			if i == n-1 {
+				p.Files++
+				p.Score += f.Score
+				p.metrics.add(f.metrics)
+			}
+		}
+	}
+	for _, p := range pkgs {
+		if p.Files > 0 {
+			r.Packages = append(r.Packages, p)
+		}
+	}
+	sort.Slice(r.Packages, func(i, j int) bool {
+		if r.Packages[i].Score != r.Packages[j].Score {
+			return r.Packages[i].Score > r.Packages[j].Score
+		}
+		return r.Packages[i].Package < r.Packages[j].Package
+	})
+
+	for _, f := range sortedFiles(last) {
+		row := &fileRow{fileScore: f, Trend: trend{Scores: make([]float64, n), Exists: make([]bool, n)}}
+		for i, s := range snaps {
+			if old := s.Files[f.File]; old != nil {
+				row.Trend.Scores[i], row.Trend.Exists[i] = old.Score, true
+			}
+		}
+		r.Files = append(r.Files, row)
+	}
+	return reportTemplate.Execute(w, r)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+	"pct": func(f float64) string { return fmt.Sprintf("%.0f%%", 100*f) },
+	"signed": func(f float64) string {
+		if f > 0 {
+			return fmt.Sprintf("+%.1f", f)
+		}
+		return fmt.Sprintf("%.1f", f)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Anti-pattern debt</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 0.7em; border-bottom: 1px solid #ddd; text-align: right; }
+th { background: #f4f4f4; }
+td.name, th.name { text-align: left; font-family: ui-monospace, monospace; }
+.up { color: #b00; } .down { color: #070; }
+svg polyline { fill: none; stroke: #c33; stroke-width: 1.5; }
+svg.chart { border: 1px solid #ddd; background: #fafafa; }
+.note { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Anti-pattern debt</h1>
+
+<h2>Total over {{len .Snapshots}} snapshots</h2>
+<svg class="chart" width="600" height="120" viewBox="-5 -5 610 130"><polyline points="{{.Total.Points 600 120 .Total.Max}}"/></svg>
+<table>
+<tr><th class="name">Snapshot</th><th class="name">Date</th><th>Score</th><th class="name">Commit</th></tr>
+{{range $i, $s := .Snapshots}}<tr><td class="name">{{$s.Label}}</td><td class="name">{{$s.Date}}</td><td>{{printf "%.1f" (index $.Total.Scores $i)}}</td><td class="name">{{$s.Title}}</td></tr>
+{{end}}</table>
+
+<h2>Packages</h2>
+<table>
+<tr><th class="name">Package</th><th>Files</th><th>Score</th><th>Change</th><th>Trend</th><th>Methods</th><th>Nesting</th><th>Dup</th><th>Unreachable</th><th>Credentials</th><th>Markers</th></tr>
+{{range .Packages}}<tr>
+<td class="name">{{.Package}}</td><td>{{.Files}}</td><td>{{printf "%.1f" .Score}}</td>
+<td class="{{if gt .Trend.Change 0.0}}up{{else if lt .Trend.Change 0.0}}down{{end}}">{{signed .Trend.Change}}</td>
+<td><svg width="100" height="24" viewBox="-2 -2 104 28"><polyline points="{{.Trend.Points 100 24 .Trend.Max}}"/></svg></td>
+<td>{{.Methods}}</td><td>{{.Nesting}}</td><td>{{pct .DupRatio}}</td><td>{{.Unreachable}}</td><td>{{.Credentials}}</td><td>{{.Markers}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Files</h2>
+<table>
+<tr><th>#</th><th class="name">File</th><th>Score</th><th>Change</th><th>Trend</th><th>Methods</th><th>Nesting</th><th>Dup</th><th>Unreachable</th><th>Credentials</th><th>Markers</th></tr>
+{{range $i, $f := .Files}}<tr>
+<td>{{inc $i}}</td><td class="name">{{.File}}</td><td>{{printf "%.1f" .Score}}</td>
+<td class="{{if gt .Trend.Change 0.0}}up{{else if lt .Trend.Change 0.0}}down{{end}}">{{signed .Trend.Change}}</td>
+<td><svg width="100" height="24" viewBox="-2 -2 104 28"><polyline points="{{.Trend.Points 100 24 .Trend.Max}}"/></svg></td>
+<td>{{.Methods}}</td><td>{{.Nesting}}</td><td>{{pct .DupRatio}}</td><td>{{.Unreachable}}</td><td>{{.Credentials}}</td><td>{{.Markers}}</td>
+</tr>
+{{end}}</table>
+
+<p class="note">
+Score = {{.Weights.Methods}}·max(0, methods − 5) + {{.Weights.Nesting}}·max(0, nesting − 3)
++ {{.Weights.Duplication}}·duplicated% + {{.Weights.Unreachable}}·unreachable
++ {{.Weights.Credentials}}·credentials + {{.Weights.Markers}}·markers.
+Methods is the most methods on one type; nesting the deepest control statement nesting;
+a package's score is the sum of its files'.
+</p>
+</body>
+</html>
+`))
@@ -0,0 +1,119 @@
+// Command flagcheck validates a feature flags file for the evaluator in
+// goodexamples/hardcoding/flags and finds the flags that can be deleted.
+//
+// Usage:
+//
+//	flagcheck [-user=name] flags-file
+//	flagcheck -events=file flags-file
+//
+// Without -events, flagcheck prints each flag's rule and whether it is on
+// for -user, anonymous by default, and why. FLAG_<NAME> environment
+// variables override flags as they do in a running program. A malformed
+// file is reported, with every problem in it, and flagcheck exits 1.
+//
+// With -events, it reads the evaluation events a program wrote with
+// flags.JSONRecorder, one JSON object per line, and prints the stale flags:
+// defined but never evaluated, evaluated but always giving the same answer,
+// or evaluated but not defined. It exits 3 if there are any.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+)
+
+var (
+	user   = flag.String("user", "", "the user to evaluate the flags for (default anonymous)")
+	events = flag.String("events", "", "report stale flags from this evaluation event log")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("flagcheck: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: flagcheck [flags] flags-file\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	defined, err := flags.Load(flag.Arg(0))
+	if err != nil {
+		for line := range strings.SplitSeq(err.Error(), "\n") {
+			log.Print(line)
+		}
+		os.Exit(1)
+	}
+
+	if *events != "" {
+		evs, rerr := readEvents(*events)
+		if rerr != nil {
+			log.Fatal(rerr)
+		}
+		findings := flags.Stale(defined, flags.Summarize(evs))
+		for _, f := range findings {
+			fmt.Printf("%s: %s\n", f.Flag, f.Reason)
+		}
+		if len(findings) > 0 {
+			os.Exit(3)
+		}
+		return
+	}
+
+	e, err := flags.New(defined, flags.Options{Getenv: os.Getenv})
+	if err != nil {
+		log.Fatal(err)
+	}
+	slices.SortFunc(defined, func(a, b flags.Flag) int { return strings.Compare(a.Name, b.Name) })
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FLAG\tRULE\tVALUE\tREASON")
+	for _, f := range defined {
+		ev := e.Evaluate(f.Name, *user)
+		fmt.Fprintf(tw, "%s\t%s\t%v\t%s\n", f.Name, rule(f), ev.Value, ev.Reason)
+	}
+	tw.Flush()
+}
+
+func readEvents(path string) ([]flags.Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	evs, err := flags.ReadEvents(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return evs, nil
+}
+
+// rule describes who a flag is on for.
+func rule(f flags.Flag) string {
+	if !f.Enabled {
+		return "off"
+	}
+	var parts []string
+	if len(f.Users) > 0 {
+		parts = append(parts, strings.Join(f.Users, ","))
+	}
+	switch {
+	case f.Rollout == nil || *f.Rollout >= 100:
+		parts = append(parts, "everyone")
+	case *f.Rollout > 0:
+		parts = append(parts, strconv.FormatFloat(*f.Rollout, 'f', -1, 64)+"%")
+	}
+	if len(parts) == 0 {
+		return "nobody"
+	}
+	return strings.Join(parts, " + ")
+}
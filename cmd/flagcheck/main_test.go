@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding"
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+const (
+	flagsFile = "../../goodexamples/hardcoding/testdata/flags.yaml"
+	eventsLog = "testdata/events.jsonl"
+)
+
+// TestEvents records, through a JSONRecorder, the evaluations the Hard
+// Coding rewrite makes for an anonymous user, a targeted partner and forty
+// others, and compares them with testdata/events.jsonl. With -update it
+// rewrites the log instead.
+func TestEvents(t *testing.T) {
+	defined, err := flags.Load(flagsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	now := time.Date(2026, 10, 17, 2, 6, 3, 0, time.UTC)
+	e, err := flags.New(defined, flags.Options{
+		OnEvaluate: flags.JSONRecorder(&buf),
+		Now: func() time.Time {
+			now = now.Add(time.Millisecond)
+			return now
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := []string{"", "partner-acme"}
+	for i := range 40 {
+		users = append(users, fmt.Sprintf("user%d", i+1))
+	}
+	for _, u := range users {
+		hardcoding.FeaturesFor(e, u)
+	}
+
+	if *update {
+		if err := os.WriteFile(eventsLog, buf.Bytes(), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(eventsLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("recorded events differ from %s; run go test -update if the flags changed on purpose", eventsLog)
+	}
+}
+
+// TestStale runs flagcheck over the event log and expects it to list the
+// flags that are always on or always off, and to exit 3.
+func TestStale(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs flagcheck")
+	}
+	bin := filepath.Join(t.TempDir(), "flagcheck")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	out, err := exec.Command(bin, "-events="+eventsLog, flagsFile).CombinedOutput()
+	if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 3 {
+		t.Fatalf("flagcheck: %v, want exit status 3\n%s", err, out)
+	}
+	want := `beta_features: off in all 42 evaluations
+dark_mode: on in all 42 evaluations
+debug_mode: off in all 42 evaluations
+enable_old_api: never evaluated
+new_dashboard: on in all 42 evaluations
+new_ui: on in all 42 evaluations
+`
+	if string(out) != want {
+		t.Errorf("flagcheck printed:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,15 @@
+// Command godobject runs the godobject analyzer, which reports structs that
+// have grown into God Objects.
+//
+// Usage:
+//
+//	godobject [-min-methods=N] [-min-fields=N] [-min-groups=N] packages...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/godobject"
+)
+
+func main() { singlechecker.Main(godobject.Analyzer) }
@@ -0,0 +1,17 @@
+// Command goldenhammer runs the goldenhammer analyzer, which reports
+// regular expressions doing arithmetic, goroutines that are awaited at once
+// or only print, type names compared as strings, and pointers to scalars
+// returned for no reason.
+//
+// Usage:
+//
+//	goldenhammer [-regexp=false] [-await=false] [-print=false] [-typename=false] [-scalarptr=false] packages...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/goldenhammer"
+)
+
+func main() { singlechecker.Main(goldenhammer.Analyzer) }
@@ -0,0 +1,260 @@
+// Command lavaflow ranks the suspicious legacy code in a set of packages.
+//
+// Usage:
+//
+//	lavaflow [-json] [-top=N] packages...
+//
+// It runs the lavaflow analyzer to collect TODO, NOTE, DEPRECATED and
+// "DO NOT REMOVE" comments, with the years mentioned around them, and bool
+// fields that are only ever set to false. It then asks git blame how long
+// each of those lines has gone untouched and prints them, most suspicious
+// first. An item's score is the weight of its kind (1 for notes, 2 for
+// TODOs, 3 for deprecations, do-not-touch warnings and stale flags), plus
+// the age in years of the oldest year its comment mentions, plus the age in
+// years of its line according to git blame. Outside a git work tree the
+// blame age is left out.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bclements/antipatterns/analyzers/lavaflow"
+	"github.com/bclements/antipatterns/internal/driver"
+)
+
+var (
+	jsonOut = flag.Bool("json", false, "print the report as a JSON array")
+	top     = flag.Int("top", 0, "print only the N highest-scoring items (0 for all)")
+)
+
+// kindStaleFlag is the kind of an item built from a lavaflow.Flag.
+const kindStaleFlag = "STALE FLAG"
+
+var weights = map[string]float64{
+	lavaflow.KindNote:       1,
+	lavaflow.KindTODO:       2,
+	lavaflow.KindDeprecated: 3,
+	lavaflow.KindDoNotTouch: 3,
+	kindStaleFlag:           3,
+}
+
+// item is one ranked line of the report.
+type item struct {
+	Score     float64  `json:"score"`
+	Kind      string   `json:"kind"`
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Decl      string   `json:"decl,omitempty"`
+	Text      string   `json:"text"`
+	Years     []int    `json:"years,omitempty"`
+	BlameAge  *float64 `json:"blame_age_years,omitempty"`
+	Committed string   `json:"last_changed,omitempty"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("lavaflow: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: lavaflow [flags] packages...\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	pkgs, err := driver.Load("", false, flag.Args()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	graph, err := driver.Analyze(pkgs, lavaflow.Analyzer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	blamer := &blamer{files: make(map[string]map[int]time.Time)}
+	var items []*item
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			log.Fatalf("%s: %v", act.Package.PkgPath, act.Err)
+		}
+		items = append(items, collect(act.Package.Fset, act.Result.(*lavaflow.Result))...)
+	}
+	rank(items, time.Now(), blamer.lineTime)
+	for _, it := range items {
+		it.File = relative(it.File)
+	}
+	if *top > 0 && len(items) > *top {
+		items = items[:*top]
+	}
+
+	if *jsonOut {
+		if items == nil {
+			items = []*item{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(items); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCORE\tKIND\tLOCATION\tDECL\tLAST CHANGED\tMENTIONS\tTEXT")
+	for _, it := range items {
+		years := make([]string, len(it.Years))
+		for i, y := range it.Years {
+			years[i] = strconv.Itoa(y)
+		}
+		fmt.Fprintf(tw, "%.1f\t%s\t%s:%d\t%s\t%s\t%s\t%s\n", it.Score, it.Kind, it.File, it.Line, it.Decl, it.Committed, strings.Join(years, ","), it.Text)
+	}
+	tw.Flush()
+}
+
+// collect turns the analyzer's markers and flags into unscored items.
+func collect(fset *token.FileSet, res *lavaflow.Result) []*item {
+	var items []*item
+	for _, m := range res.Markers {
+		pos := fset.Position(m.Pos)
+		items = append(items, &item{Kind: m.Kind, File: pos.Filename, Line: pos.Line, Decl: m.Decl, Text: m.Text, Years: m.Years})
+	}
+	for _, fl := range res.Flags {
+		pos := fset.Position(fl.Pos)
+		var calls []string
+		for _, g := range fl.Guards {
+			calls = append(calls, g.Calls...)
+		}
+		text := fl.Name + " is only ever set to false"
+		if len(calls) > 0 {
+			text += "; dead: " + strings.Join(calls, ", ")
+		}
+		items = append(items, &item{Kind: kindStaleFlag, File: pos.Filename, Line: pos.Line, Decl: strings.Split(fl.Name, ".")[0], Text: text})
+	}
+	return items
+}
+
+// rank scores items as of now, taking the time each line last changed from
+// lineTime, and sorts them most suspicious first.
+func rank(items []*item, now time.Time, lineTime func(file string, line int) (time.Time, bool)) {
+	for _, it := range items {
+		it.Score = weights[it.Kind]
+		if len(it.Years) > 0 {
+			it.Score += float64(max(0, now.Year()-it.Years[0]))
+		}
+		if t, ok := lineTime(it.File, it.Line); ok {
+			age := math.Round(now.Sub(t).Hours()/24/365.25*100) / 100
+			it.BlameAge = &age
+			it.Committed = t.Format(time.DateOnly)
+			it.Score += age
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Score != items[j].Score {
+			return items[i].Score > items[j].Score
+		}
+		if items[i].File != items[j].File {
+			return items[i].File < items[j].File
+		}
+		return items[i].Line < items[j].Line
+	})
+}
+
+// blamer caches the author time of every line of the files it is asked
+// about.
+type blamer struct {
+	files map[string]map[int]time.Time
+}
+
+// lineTime returns when line of file was last changed, or false if git
+// cannot tell.
+func (b *blamer) lineTime(file string, line int) (time.Time, bool) {
+	lines, ok := b.files[file]
+	if !ok {
+		lines = blame(file)
+		b.files[file] = lines
+	}
+	t, ok := lines[line]
+	return t, ok
+}
+
+// blame runs git blame on file and returns the author time of each line.
+// Lines not yet committed count as changed now.
+func blame(file string) map[int]time.Time {
+	cmd := exec.Command("git", "blame", "--porcelain", "--", filepath.Base(file))
+	cmd.Dir = filepath.Dir(file)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil
+	}
+	lines, err := parseBlame(&stdout, time.Now())
+	if err != nil {
+		return nil
+	}
+	return lines
+}
+
+// parseBlame reads the output of git blame --porcelain and returns the
+// author time of each line, by final line number. Lines not yet committed
+// get the time now.
+func parseBlame(r io.Reader, now time.Time) (map[int]time.Time, error) {
+	times := make(map[string]time.Time) // commit -> author time
+	lines := make(map[int]time.Time)
+	var commit string
+	var lineNo int
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		text := sc.Text()
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			// The line's content ends its entry.
+			if commit == strings.Repeat("0", len(commit)) {
+				lines[lineNo] = now
+			} else {
+				lines[lineNo] = times[commit]
+			}
+		case strings.HasPrefix(text, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64); err == nil {
+				times[commit] = time.Unix(sec, 0)
+			}
+		default:
+			// "<commit> <orig line> <final line> [<group size>]"
+			fields := strings.Fields(text)
+			if len(fields) >= 3 && len(fields[0]) >= 40 {
+				if n, err := strconv.Atoi(fields[2]); err == nil {
+					commit, lineNo = fields[0], n
+				}
+			}
+		}
+	}
+	return lines, sc.Err()
+}
+
+func relative(name string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return name
+	}
+	if rel, err := filepath.Rel(wd, name); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return name
+}
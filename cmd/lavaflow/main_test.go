@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bclements/antipatterns/analyzers/lavaflow"
+)
+
+// testdata/blame.txt is git blame --porcelain of a file whose lines 1-4 were
+// committed on 2016-03-01, lines 5-7 on 2024-06-15, and lines 8-10 not yet.
+
+var (
+	legacyCommit  = time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	reportsCommit = time.Date(2024, 6, 15, 9, 30, 0, 0, time.UTC)
+	now           = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestParseBlame(t *testing.T) {
+	f, err := os.Open("testdata/blame.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines, err := parseBlame(f, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 10 {
+		t.Errorf("%d lines, want 10", len(lines))
+	}
+	for line := 1; line <= 10; line++ {
+		want := now
+		switch {
+		case line <= 4:
+			want = legacyCommit
+		case line <= 7:
+			want = reportsCommit
+		}
+		if got, ok := lines[line]; !ok || !got.Equal(want) {
+			t.Errorf("line %d changed at %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	f, err := os.Open("testdata/blame.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines, err := parseBlame(f, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lineTime := func(file string, line int) (time.Time, bool) {
+		t, ok := lines[line]
+		return t, ok && file == "legacy.go"
+	}
+
+	items := []*item{
+		{Kind: lavaflow.KindNote, File: "legacy.go", Line: 7},
+		{Kind: lavaflow.KindNote, File: "legacy.go", Line: 6},
+		{Kind: lavaflow.KindDeprecated, File: "legacy.go", Line: 9},
+		{Kind: kindStaleFlag, File: "legacy.go", Line: 4},
+		{Kind: lavaflow.KindDoNotTouch, File: "other.go", Line: 1, Years: []int{2020}},
+		{Kind: lavaflow.KindTODO, File: "legacy.go", Line: 3, Years: []int{2015, 2017}},
+	}
+	rank(items, now, lineTime)
+
+	// Commits are dated in local time.
+	legacy, reports := legacyCommit.Local().Format(time.DateOnly), reportsCommit.Local().Format(time.DateOnly)
+	want := []string{
+		"23.00 TODO legacy.go:3 " + legacy,       // 2 + 11 years since 2015 + 10 years untouched
+		"13.00 STALE FLAG legacy.go:4 " + legacy, // 3 + 10 years untouched
+		"9.00 DO NOT REMOVE other.go:1 ",         // 3 + 6 years since 2020, no blame
+		"3.00 DEPRECATED legacy.go:9 2026-03-01", // 3, not committed yet
+		"2.71 NOTE legacy.go:6 " + reports,       // 1 + 1.71 years untouched
+		"2.71 NOTE legacy.go:7 " + reports,
+	}
+	for i, it := range items {
+		got := fmt.Sprintf("%.2f %s %s:%d %s", it.Score, it.Kind, it.File, it.Line, it.Committed)
+		if i >= len(want) || got != want[i] {
+			t.Errorf("item %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if items[2].BlameAge != nil {
+		t.Errorf("blame age %v for a file git does not know", *items[2].BlameAge)
+	}
+}
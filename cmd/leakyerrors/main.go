@@ -0,0 +1,17 @@
+// Command leakyerrors runs the leakyerrors analyzer, which reports code
+// that holds an interface but compares the errors it returns with the
+// sentinel errors of one implementation, and suggests errors.Is and a
+// wrapping error type instead.
+//
+// Usage:
+//
+//	leakyerrors [-fix] packages...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/leakyerrors"
+)
+
+func main() { singlechecker.Main(leakyerrors.Analyzer) }
@@ -0,0 +1,314 @@
+// Command markers exports the anti-pattern markers of a set of packages,
+// comments such as "// DEAD CODE: never executed" or "// LEAK: ...", so
+// that the examples can be loaded into code-review tools as annotated
+// walkthroughs.
+//
+// Usage:
+//
+//	markers [-format=text|json|sarif|checkstyle] [-summary=file] packages...
+//
+// Each marker is tied to the anti-pattern its tag names and to the
+// top-level declaration it sits in. The text format prints one line per
+// marker. The json format prints one JSON object per line. The sarif
+// format prints a SARIF 2.1.0 log with a rule per anti-pattern, whose help
+// is the "Why This Matters" section of the anti-pattern in -summary, which
+// defaults to ANTI_PATTERNS_SUMMARY.md at the root of the packages' module;
+// it fails if the summary cannot be read.
+// The checkstyle format prints Checkstyle XML. Paths are relative to the
+// module root.
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/bclements/antipatterns/internal/driver"
+	"github.com/bclements/antipatterns/internal/markers"
+	"github.com/bclements/antipatterns/internal/sarif"
+)
+
+var (
+	format      = flag.String("format", "text", "output format: text, json, sarif or checkstyle")
+	summaryPath = flag.String("summary", "", "the anti-pattern summary supplying SARIF rule help (default: ANTI_PATTERNS_SUMMARY.md in the module root)")
+)
+
+const (
+	toolURL    = "https://github.com/bclements/antipatterns/tree/main/cmd/markers"
+	summaryURL = "https://github.com/bclements/antipatterns/blob/main/ANTI_PATTERNS_SUMMARY.md"
+)
+
+// marker is a markers.Marker resolved to file positions.
+type marker struct {
+	*markers.Marker
+	Package string
+	Pos     token.Position
+	End     token.Position
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("markers: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: markers [flags] packages...\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	pkgs, err := driver.Load("", false, flag.Args()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	root, _ := os.Getwd()
+	if pkgs[0].Module != nil && pkgs[0].Module.Dir != "" {
+		root = pkgs[0].Module.Dir
+	}
+	found := collect(pkgs, root)
+
+	switch *format {
+	case "text":
+		for _, m := range found {
+			in := ""
+			if m.Decl != "" {
+				in = " [" + m.Decl + "]"
+			}
+			fmt.Printf("%s: %s: %s%s\n", m.Pos, m.Pattern.Name, m.Text, in)
+		}
+	case "json":
+		err = writeJSON(os.Stdout, found)
+	case "sarif":
+		path := *summaryPath
+		if path == "" {
+			path = filepath.Join(root, "ANTI_PATTERNS_SUMMARY.md")
+		}
+		var sections map[string]*markers.Section
+		if sections, err = readSummary(path); err != nil {
+			log.Fatal(err)
+		}
+		err = writeSARIF(os.Stdout, found, sections)
+	case "checkstyle":
+		err = writeCheckstyle(os.Stdout, found)
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// collect returns the markers in pkgs in file order, with file names
+// relative to root.
+func collect(pkgs []*packages.Package, root string) []marker {
+	var found []marker
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Syntax {
+			for _, m := range markers.Find(f) {
+				found = append(found, marker{
+					Marker:  m,
+					Package: pkg.PkgPath,
+					Pos:     pkg.Fset.Position(m.Pos),
+					End:     pkg.Fset.Position(m.End),
+				})
+			}
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i].Pos, found[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	for i := range found {
+		found[i].Pos.Filename = relative(root, found[i].Pos.Filename)
+		found[i].End.Filename = found[i].Pos.Filename
+	}
+	return found
+}
+
+// readSummary reads the "Why This Matters" sections of the anti-pattern
+// summary at path. A summary without them is an error: the SARIF rules
+// would have no help.
+func readSummary(path string) (map[string]*markers.Section, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sections := markers.ParseSummary(data)
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("%s: no anti-pattern sections", path)
+	}
+	return sections, nil
+}
+
+// jsonMarker is the JSON form of a marker.
+type jsonMarker struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	Package   string `json:"package"`
+	Pattern   string `json:"pattern"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+	Text      string `json:"text"`
+	Decl      string `json:"decl,omitempty"`
+	DeclKind  string `json:"decl_kind,omitempty"`
+}
+
+// writeJSON writes one JSON object per line.
+func writeJSON(w io.Writer, found []marker) error {
+	enc := json.NewEncoder(w)
+	for _, m := range found {
+		err := enc.Encode(jsonMarker{
+			File:      m.Pos.Filename,
+			Line:      m.Pos.Line,
+			Column:    m.Pos.Column,
+			EndLine:   m.End.Line,
+			EndColumn: m.End.Column,
+			Package:   m.Package,
+			Pattern:   m.Pattern.ID,
+			Name:      m.Pattern.Name,
+			Tag:       m.Tag,
+			Text:      m.Text,
+			Decl:      m.Decl,
+			DeclKind:  m.DeclKind,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logicalKinds maps declaration kinds to SARIF logical location kinds.
+var logicalKinds = map[string]string{
+	"func":   "function",
+	"method": "member",
+	"type":   "type",
+	"var":    "variable",
+	"const":  "variable",
+}
+
+func writeSARIF(w io.Writer, found []marker, sections map[string]*markers.Section) error {
+	doc := sarif.New(sarif.Driver{Name: "markers", InformationURI: toolURL})
+	run := doc.Run()
+	for _, p := range markers.Patterns {
+		rule := sarif.Rule{
+			ID:                   p.ID,
+			Name:                 ruleName(p.Name),
+			ShortDescription:     &sarif.Message{Text: p.Name},
+			HelpURI:              summaryURL,
+			DefaultConfiguration: &sarif.Configuration{Level: sarif.LevelNote},
+		}
+		if s := sections[p.ID]; s != nil {
+			rule.HelpURI += "#" + s.Anchor
+			if s.Why != "" {
+				rule.Help = &sarif.Message{Text: s.Why, Markdown: s.Why}
+			}
+		}
+		run.AddRule(rule)
+	}
+	for _, m := range found {
+		loc := sarif.FileLocation("", m.Pos.Filename, m.Pos.Line, m.Pos.Column, m.End.Line, m.End.Column)
+		if m.Decl != "" {
+			loc.LogicalLocations = []sarif.LogicalLocation{{
+				Name:               m.Decl,
+				FullyQualifiedName: m.Package + "." + m.Decl,
+				Kind:               logicalKinds[m.DeclKind],
+			}}
+		}
+		run.Results = append(run.Results, sarif.Result{
+			RuleID:     m.Pattern.ID,
+			Level:      sarif.LevelNote,
+			Message:    sarif.Message{Text: m.Tag + ": " + m.Text},
+			Locations:  []sarif.Location{loc},
+			Properties: map[string]any{"tag": m.Tag},
+		})
+	}
+	return doc.Write(w)
+}
+
+// Checkstyle XML, as read by most code-review tools.
+type (
+	checkstyle struct {
+		XMLName xml.Name          `xml:"checkstyle"`
+		Version string            `xml:"version,attr"`
+		Files   []*checkstyleFile `xml:"file"`
+	}
+	checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+	checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+)
+
+func writeCheckstyle(w io.Writer, found []marker) error {
+	doc := checkstyle{Version: "4.3"}
+	var file *checkstyleFile
+	for _, m := range found {
+		if file == nil || file.Name != m.Pos.Filename {
+			file = &checkstyleFile{Name: m.Pos.Filename}
+			doc.Files = append(doc.Files, file)
+		}
+		msg := m.Pattern.Name + ": " + m.Text
+		if m.Decl != "" {
+			msg += " (in " + m.Decl + ")"
+		}
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     m.Pos.Line,
+			Column:   m.Pos.Column,
+			Severity: "info",
+			Message:  msg,
+			Source:   "antipatterns.markers." + m.Pattern.ID,
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ruleName turns an anti-pattern's name into a rule name, e.g.
+// "Reinventing the Wheel" into "ReinventingTheWheel".
+func ruleName(name string) string {
+	var b strings.Builder
+	for _, w := range strings.Fields(name) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+func relative(root, name string) string {
+	if rel, err := filepath.Rel(root, name); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+	return name
+}
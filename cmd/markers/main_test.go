@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bclements/antipatterns/internal/driver"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// TestGolden exports the markers of the examples in each format and
+// compares them with the golden files in testdata.
+func TestGolden(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := driver.Load(root, false, "./golangexamples/...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := collect(pkgs, root)
+	sections, err := readSummary(filepath.Join(root, "ANTI_PATTERNS_SUMMARY.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		format, golden string
+		write          func(io.Writer) error
+	}{
+		{"sarif", "golangexamples.sarif", func(w io.Writer) error { return writeSARIF(w, found, sections) }},
+		{"checkstyle", "golangexamples.xml", func(w io.Writer) error { return writeCheckstyle(w, found) }},
+		{"json", "golangexamples.jsonl", func(w io.Writer) error { return writeJSON(w, found) }},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := test.write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", test.golden)
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("%s output differs from %s; run go test -update to accept it:\n%s", test.format, golden, got)
+			}
+		})
+	}
+}
+
+func TestReadSummary(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := readSummary(filepath.Join(dir, "ANTI_PATTERNS_SUMMARY.md")); err == nil {
+		t.Error("readSummary of a missing file succeeded")
+	}
+	empty := filepath.Join(dir, "empty.md")
+	if err := os.WriteFile(empty, []byte("# Notes\n\nNothing here.\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSummary(empty); err == nil {
+		t.Error("readSummary of a file without sections succeeded")
+	}
+}
@@ -0,0 +1,125 @@
+// Command pricematrix checks a set of pricing rules against the original
+// Spaghetti Code ProcessOrder on every combination of its inputs.
+//
+// Usage:
+//
+//	pricematrix [-rules=file] [-v]
+//
+// The matrix is the cartesian product of the customer types, payment
+// methods, discount codes and shipping methods the original tests for,
+// plus one value each that it does not know, and baskets of 0 to 7 items
+// whose totals fall on both sides of every threshold it compares against.
+// Each combination is ordered through golangexamples/spaghetticode and
+// through the rule engine of goodexamples/spaghetticode, with the rules in
+// -rules (a .yaml, .yml or .json file) or, by default, the embedded
+// pricing.yaml. pricematrix prints each combination whose orders differ and
+// exits 3 if there are any; -v also prints the rules each one matched.
+//
+// Run it before changing any pricing behavior, so that the only
+// differences are the intended ones. go test ./goodexamples/spaghetticode
+// checks the same matrix against the embedded rules.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/bclements/antipatterns/golangexamples/spaghetticode"
+	good "github.com/bclements/antipatterns/goodexamples/spaghetticode"
+)
+
+var (
+	rulesPath = flag.String("rules", "", "the rules file (default: the embedded pricing.yaml)")
+	verbose   = flag.Bool("v", false, "print the rules each differing combination matched")
+)
+
+var (
+	userTypes = []string{"premium", "regular", "guest", ""}
+	payments  = []string{"credit", "paypal", "cash", ""}
+	codes     = []string{"", "SAVE20", "SAVE10", "save20", "BOGUS"}
+	shipping  = []string{"express", "standard", "pickup", ""}
+	// counts straddle the 3 and 5 item thresholds; prices make subtotals,
+	// and discounted totals, that straddle 0, 50 and 100.
+	counts = []int{0, 1, 2, 3, 4, 5, 6, 7}
+	prices = []float64{-10, 0, 5, 10, 12.5, 17.5, 20, 25, 27.5, 50, 55, 62.5, 100, 101, 125}
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("pricematrix: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: pricematrix [flags]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	rules := good.DefaultRules
+	if *rulesPath != "" {
+		var err error
+		if rules, err = good.LoadRules(*rulesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	n, diffs := 0, 0
+	for _, u := range userTypes {
+		for _, p := range payments {
+			for _, c := range codes {
+				for _, s := range shipping {
+					for _, count := range counts {
+						for _, price := range prices {
+							n++
+							items := make([]spaghetticode.Item, count)
+							goodItems := make([]good.Item, count)
+							for i := range count {
+								name := fmt.Sprintf("Item%d", i+1)
+								items[i] = spaghetticode.Item{Product: name, Price: price}
+								goodItems[i] = good.Item{Product: name, Price: price}
+							}
+							want := outcome(spaghetticode.ProcessOrder(n, u, p, c, s, items))
+							got := rules.ProcessOrder(n, u, p, c, s, goodItems)
+							if want == goodOutcome(got) {
+								continue
+							}
+							diffs++
+							fmt.Printf("user=%q payment=%q code=%q shipping=%q items=%d×%g: want %s, got %s\n",
+								u, p, c, s, count, price, want, goodOutcome(got))
+							if *verbose {
+								q := rules.Quote(u, p, c, s, goodItems)
+								fmt.Printf("\trules: %s\n", strings.Join(q.Applied, " → "))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if diffs > 0 {
+		fmt.Printf("%d of %d combinations differ\n", diffs, n)
+		os.Exit(3)
+	}
+	fmt.Printf("%d combinations match\n", n)
+}
+
+// outcome and goodOutcome describe an order by everything ProcessOrder
+// decides: whether there is one and its status. The other fields are
+// copies of the arguments.
+func outcome(o *spaghetticode.Order) string {
+	if o == nil {
+		return "no order"
+	}
+	return o.Status
+}
+
+func goodOutcome(o *good.Order) string {
+	if o == nil {
+		return "no order"
+	}
+	return o.Status
+}
@@ -0,0 +1,124 @@
+// Command profile shows and compares the environment profiles of the Hard
+// Coding rewrite in goodexamples/hardcoding/profile.
+//
+// Usage:
+//
+//	profile [-profiles=file] list
+//	profile [-profiles=file] show name
+//	profile [-profiles=file] diff name1 name2
+//
+// list prints the profiles and what each extends. show prints a profile's
+// endpoints and where each URL came from. diff prints the endpoints whose
+// URLs differ between two profiles, or nothing if none do.
+//
+// The profiles are the embedded profiles.yaml unless -profiles names a
+// .yaml, .yml or .json file, which is checked first: every problem in it is
+// reported and profile exits 1. Environment overrides such as AUTH_URL
+// apply to show as they do in a running program; diff compares the
+// profiles as written. A local profile's endpoints are servers its tests
+// start, so each URL of one shows as "(local server)".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/profile"
+)
+
+var profilesPath = flag.String("profiles", "", "the profiles file (default: the embedded profiles.yaml)")
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("profile: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: profile [flags] list | show name | diff name1 name2\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	want := map[string]int{"list": 1, "show": 2, "diff": 3}
+	if len(args) == 0 || want[args[0]] != len(args) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	profiles := profile.Default
+	if *profilesPath != "" {
+		var err error
+		if profiles, err = profile.Load(*profilesPath); err != nil {
+			fatal(err)
+		}
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	defer tw.Flush()
+	switch args[0] {
+	case "list":
+		for _, name := range profiles.Names() {
+			fmt.Fprintf(tw, "%s\t%s\n", name, profiles.Describe(name))
+		}
+	case "show":
+		urls, origins, err := resolve(profiles, args[1], profile.Options{Getenv: os.Getenv})
+		if err != nil {
+			fatal(err)
+		}
+		for _, name := range profile.Names() {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", name, urls[name], origins[name])
+		}
+	case "diff":
+		a, _, err := resolve(profiles, args[1], profile.Options{})
+		if err != nil {
+			fatal(err)
+		}
+		b, _, err := resolve(profiles, args[2], profile.Options{})
+		if err != nil {
+			fatal(err)
+		}
+		header := fmt.Sprintf("ENDPOINT\t%s\t%s\n", strings.ToUpper(args[1]), strings.ToUpper(args[2]))
+		for _, name := range profile.Names() {
+			if a[name] == b[name] {
+				continue
+			}
+			fmt.Fprint(tw, header)
+			header = ""
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", name, a[name], b[name])
+		}
+	}
+}
+
+// localURL stands for each URL of a local profile, which are servers a
+// test starts.
+const localURL = "(local server)"
+
+// resolve returns the URL of each endpoint of the named profile and where
+// it came from.
+func resolve(profiles *profile.Profiles, name string, opts profile.Options) (map[string]string, profile.Origins, error) {
+	urls := make(map[string]string)
+	if profiles.IsLocal(name) {
+		origins := make(profile.Origins)
+		for _, ep := range profile.Names() {
+			urls[ep], origins[ep] = localURL, "local"
+		}
+		return urls, origins, nil
+	}
+	e, origins, err := profiles.Resolve(name, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ep := range profile.Names() {
+		urls[ep] = e.URL(ep)
+	}
+	return urls, origins, nil
+}
+
+// fatal prints each line of err and exits 1.
+func fatal(err error) {
+	for line := range strings.SplitSeq(err.Error(), "\n") {
+		log.Print(line)
+	}
+	os.Exit(1)
+}
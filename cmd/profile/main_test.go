@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestProfile runs profile on the embedded profiles and checks what each
+// command prints and its exit status.
+func TestProfile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs profile")
+	}
+	bin := filepath.Join(t.TempDir(), "profile")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	for _, test := range []struct {
+		args string
+		want string
+		exit int
+	}{
+		{
+			args: "diff staging prod",
+			want: `ENDPOINT  STAGING                              PROD
+auth      https://auth.staging.company.com     https://auth.company.com
+api       https://api.staging.company.com      https://api.company.com
+web       https://www.staging.company.com      https://www.company.com
+socket    wss://socket.staging.company.com     wss://socket.company.com
+paypal    https://api-m.sandbox.paypal.com/v1  https://api.paypal.com/v1
+`,
+		},
+		{args: "diff prod prod"},
+		{
+			args: "diff dev test",
+			want: `ENDPOINT  DEV                                  TEST
+auth      http://localhost:8081                (local server)
+api       http://localhost:8080                (local server)
+web       http://localhost:3000                (local server)
+socket    ws://localhost:8082                  (local server)
+stripe    https://api.stripe.com/v1            (local server)
+paypal    https://api-m.sandbox.paypal.com/v1  (local server)
+sendgrid  https://api.sendgrid.com/v3          (local server)
+twilio    https://api.twilio.com/2010-04-01    (local server)
+`,
+		},
+		{args: "diff test test"},
+		{
+			args: "list",
+			want: `dev      extends staging, insecure
+prod     secure
+staging  extends prod
+test     local
+`,
+		},
+		{
+			args: "diff prod qa",
+			want: "profile: unknown profile \"qa\": have dev, prod, staging, test\n",
+			exit: 1,
+		},
+		{
+			args: "diff prod",
+			want: "usage: profile [flags] list | show name | diff name1 name2\n",
+			exit: 2,
+		},
+	} {
+		out, err := exec.Command(bin, strings.Fields(test.args)...).CombinedOutput()
+		exit := 0
+		if e, ok := err.(*exec.ExitError); ok {
+			exit = e.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if exit != test.exit {
+			t.Errorf("profile %s: exit status %d, want %d\n%s", test.args, exit, test.exit, out)
+		}
+		got := string(out)
+		if test.exit == 2 {
+			got, _, _ = strings.Cut(got, "\n")
+			got += "\n"
+		}
+		if got != test.want {
+			t.Errorf("profile %s printed:\n%s\nwant:\n%s", test.args, got, test.want)
+		}
+	}
+}
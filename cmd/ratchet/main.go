@@ -0,0 +1,182 @@
+// Command ratchet runs the repository's analyzers and fails only on
+// findings that are not in a baseline, so that a code base with known
+// anti-patterns can stop adding new ones and pay the old ones down.
+//
+// Usage:
+//
+//	ratchet [-baseline=file] [-update] [-analyzers=name,...] packages...
+//
+// With -update, ratchet records every current finding in the baseline
+// file and exits. Otherwise it compares the findings with the baseline,
+// prints the new ones like go vet and exits 3 if there are any. Findings in
+// the baseline that no longer occur are counted, so that the baseline can
+// be tightened with -update.
+//
+// Findings are matched by fingerprint: the analyzer, the category, the
+// enclosing declaration and the whitespace-normalized source text the
+// diagnostic points at. Line numbers play no part, so editing unrelated
+// code leaves the baseline valid, while a new unreachable statement, even
+// one identical to a baselined statement elsewhere in the same function,
+// is new.
+//
+// -analyzers selects analyzers by name; by default all of them run, with
+// their default settings.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+	"github.com/bclements/antipatterns/internal/baseline"
+	"github.com/bclements/antipatterns/internal/driver"
+	"github.com/bclements/antipatterns/internal/suite"
+)
+
+var (
+	baselinePath = flag.String("baseline", ".antipatterns-baseline.json", "the baseline file")
+	update       = flag.Bool("update", false, "record the current findings as the baseline")
+	names        = flag.String("analyzers", "", "comma-separated analyzers to run (default: all)")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("ratchet: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: ratchet [flags] packages...\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	analyzers, err := suite.Lookup(*names)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	root, findings, current, err := collect("", analyzers, flag.Args()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *update {
+		b := baseline.New(current)
+		if err := b.Write(*baselinePath); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "ratchet: recorded %d findings in %s\n", len(current), *baselinePath)
+		return
+	}
+
+	b, err := baseline.Read(*baselinePath)
+	if os.IsNotExist(err) {
+		log.Fatalf("no baseline at %s; create it with -update", *baselinePath)
+	} else if err != nil {
+		log.Fatal(err)
+	}
+	added, gone := b.Compare(current)
+	for _, i := range added {
+		f := findings[i]
+		fmt.Printf("%s:%d:%d: %s: %s\n", relative(root, f.Pos.Filename), f.Pos.Line, f.Pos.Column, f.Analyzer, f.Message)
+	}
+	fixed := 0
+	for _, e := range gone {
+		fixed += e.Count
+	}
+	fmt.Fprintf(os.Stderr, "ratchet: %d new, %d baselined, %d fixed\n", len(added), len(current)-len(added), fixed)
+	if fixed > 0 && len(added) == 0 {
+		fmt.Fprintf(os.Stderr, "ratchet: run with -update to ratchet the baseline down\n")
+	}
+	if len(added) > 0 {
+		os.Exit(3)
+	}
+}
+
+// collect loads the packages matching patterns, resolved relative to dir,
+// and runs the analyzers over them. It returns the module's root directory,
+// the findings and their baseline entries, whose files are relative to
+// root.
+func collect(dir string, analyzers []*analysis.Analyzer, patterns ...string) (root string, findings []driver.Finding, current []baseline.Entry, err error) {
+	pkgs, err := driver.Load(dir, false, patterns...)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	root, _ = filepath.Abs(dir)
+	if pkgs[0].Module != nil && pkgs[0].Module.Dir != "" {
+		root = pkgs[0].Module.Dir
+	}
+	files := make(map[string]*ast.File)
+	for _, pkg := range pkgs {
+		for i, f := range pkg.Syntax {
+			files[pkg.CompiledGoFiles[i]] = f
+		}
+	}
+
+	graph, err := driver.Analyze(pkgs, analyzers...)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	findings, err = driver.Findings(graph)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	src := &sources{files: make(map[string][]byte)}
+	current = make([]baseline.Entry, len(findings))
+	for i, f := range findings {
+		file := relative(root, f.Pos.Filename)
+		symbol := f.Package
+		if af := files[f.Pos.Filename]; af != nil {
+			if d := astutil.EnclosingDecl(af, f.Diagnostic.Pos); d != nil {
+				symbol += "." + astutil.DeclName(d)
+			} else {
+				symbol += ":" + filepath.Base(file)
+			}
+		}
+		current[i] = baseline.NewEntry(f.Analyzer, f.Category, symbol, src.snippet(f.Pos, f.End), file, f.Message)
+	}
+	return root, findings, current, nil
+}
+
+// sources caches the contents of the files findings point into.
+type sources struct {
+	files map[string][]byte
+}
+
+// snippet returns the source text from pos to end, however many lines it
+// spans, so that a change anywhere in the reported code changes the
+// fingerprint; or, if end is unknown, the rest of pos's line.
+func (s *sources) snippet(pos, end token.Position) string {
+	data, ok := s.files[pos.Filename]
+	if !ok {
+		data, _ = os.ReadFile(pos.Filename)
+		s.files[pos.Filename] = data
+	}
+	if pos.Offset < 0 || pos.Offset > len(data) {
+		return ""
+	}
+	if end.IsValid() && end.Filename == pos.Filename && pos.Offset <= end.Offset && end.Offset <= len(data) {
+		return string(data[pos.Offset:end.Offset])
+	}
+	text := string(data[pos.Offset:])
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	return text
+}
+
+func relative(root, name string) string {
+	if rel, err := filepath.Rel(root, name); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+	return name
+}
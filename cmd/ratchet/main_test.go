@@ -0,0 +1,144 @@
+package main
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bclements/antipatterns/internal/baseline"
+	"github.com/bclements/antipatterns/internal/suite"
+)
+
+// TestBaseline edits a copy of the examples that testdata/baseline.json
+// was recorded from and checks which findings ratchet calls new.
+func TestBaseline(t *testing.T) {
+	const (
+		deadcode = "golangexamples/deadcode/dead_code.go"
+		lavaflow = "golangexamples/lavaflow/lava_flow.go"
+	)
+	tests := []struct {
+		name  string
+		file  string // edited, deadcode if empty
+		edit  func(t *testing.T, src string) string
+		added []string
+	}{
+		{
+			name: "no edit",
+		},
+		{
+			name:  "unrelated edit",
+			edit:  replace("// ComplexFunction has multiple dead code issues\n", "// Half is unrelated to the dead code.\nfunc Half(x int) int {\n\treturn x / 2\n}\n\n// ComplexFunction has multiple dead code issues\n"),
+			added: nil,
+		},
+		{
+			name:  "more dead code after a baselined block",
+			edit:  replace("\ttemp := result / 2\n\treturn temp\n", "\ttemp := result / 2\n\treturn temp\n\treturn temp\n"),
+			added: []string{"deadcode ComplexFunction"},
+		},
+		{
+			name:  "new dead code in a clean function",
+			edit:  replace("\tum.users = make([]string, 0)\n}", "\tum.users = make([]string, 0)\n\treturn\n\tum.adminCount = 0\n}"),
+			added: []string{"deadcode (*UserManager).ClearUsers"},
+		},
+		{
+			// Every line below the edits moves, and emergencyFixForBug123,
+			// with its TODO and DO NOT REMOVE, moves to the end of the file.
+			name: "unrelated edits and a moved declaration",
+			file: lavaflow,
+			edit: func(t *testing.T, src string) string {
+				src = replace("// LAVA FLOW: Nobody knows", "// Version is unrelated to the lava flow.\nconst Version = \"2.0\"\n\n// LAVA FLOW: Nobody knows")(t, src)
+				src = replace("\t// Modern usage\n", "\t// Modern usage, as of version 2.\n\tfmt.Fprintln(w, \"Version\", Version)\n")(t, src)
+				start := strings.Index(src, "// LAVA FLOW: Emergency fix from 2016")
+				if start < 0 {
+					t.Fatalf("%s has no emergencyFixForBug123", lavaflow)
+				}
+				end := start + strings.Index(src[start:], "\n}\n") + len("\n}\n")
+				decl := src[start:end]
+				return src[:start] + src[end:] + "\n" + decl
+			},
+			added: nil,
+		},
+	}
+
+	b, err := baseline.Read(filepath.Join("testdata", "baseline.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzers, err := suite.Lookup("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := copyExamples(t, "../..", "golangexamples/deadcode", "golangexamples/lavaflow")
+			if test.edit != nil {
+				file := cmp.Or(test.file, deadcode)
+				path := filepath.Join(dir, file)
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(test.edit(t, string(data))), 0o666); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, findings, current, err := collect(dir, analyzers, "./golangexamples/lavaflow", "./golangexamples/deadcode")
+			if err != nil {
+				t.Fatal(err)
+			}
+			added, _ := b.Compare(current)
+			var got []string
+			for _, i := range added {
+				symbol := strings.TrimPrefix(current[i].Symbol, "github.com/bclements/antipatterns/golangexamples/deadcode.")
+				got = append(got, findings[i].Analyzer+" "+symbol)
+			}
+			if strings.Join(got, "\n") != strings.Join(test.added, "\n") {
+				t.Errorf("new findings %q, want %q", got, test.added)
+			}
+		})
+	}
+}
+
+// replace returns an edit replacing the first old in a file with new.
+func replace(old, new string) func(t *testing.T, src string) string {
+	return func(t *testing.T, src string) string {
+		t.Helper()
+		if !strings.Contains(src, old) {
+			t.Fatalf("file does not contain %q", old)
+		}
+		return strings.Replace(src, old, new, 1)
+	}
+}
+
+// copyExamples copies the Go files of the packages dirs under root into a
+// temporary module with the same path as the repository's, so that the
+// baseline's symbols and files match.
+func copyExamples(t *testing.T, root string, dirs ...string) string {
+	tmp := t.TempDir()
+	gomod := "module github.com/bclements/antipatterns\n\ngo 1.25.0\n"
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte(gomod), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(root, dir, "*.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(tmp, dir), 0o777); err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(tmp, dir, filepath.Base(f)), data, 0o666); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return tmp
+}
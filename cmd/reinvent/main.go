@@ -0,0 +1,16 @@
+// Command reinvent runs the reinvent analyzer, which reports hand-rolled
+// reimplementations of standard library functionality and names the
+// replacement.
+//
+// Usage:
+//
+//	reinvent packages...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/reinvent"
+)
+
+func main() { singlechecker.Main(reinvent.Analyzer) }
@@ -0,0 +1,15 @@
+// Command secretscan runs the secretscan analyzer, which reports credentials
+// hard-coded in Go source.
+//
+// Usage:
+//
+//	secretscan [-entropy=3.0] [-min-length=8] [-fields=true] [-allowlist=file] packages...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/bclements/antipatterns/analyzers/secretscan"
+)
+
+func main() { singlechecker.Main(secretscan.Analyzer) }
@@ -0,0 +1,144 @@
+// Command unused reports the boat anchors of a whole program: functions,
+// methods, types, interfaces, constants, variables and struct fields that
+// nothing reachable from main and init uses.
+//
+// Usage:
+//
+//	unused [-filter=REGEXP] [-test] [-json] packages...
+//
+// The packages must include at least one main package; reachability is
+// computed from its main and init functions with rapid type analysis. Only
+// declarations in packages whose import path matches -filter are reported;
+// it defaults to the main module. For example,
+//
+//	unused ./cmd/antipatterns
+//
+// reports everything the example runner never touches. Findings fall into
+// three categories: functions that are unreachable, declarations that are
+// unreferenced, and fields or variables that are write-only, such as a field
+// set by a constructor but never read afterwards. The command exits 3 when
+// anything was reported.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/bclements/antipatterns/internal/driver"
+	"github.com/bclements/antipatterns/internal/unused"
+)
+
+var (
+	filter  = flag.String("filter", "", "report only packages whose import path matches this regular expression (default: the main module)")
+	tests   = flag.Bool("test", false, "include test packages and their main functions")
+	jsonOut = flag.Bool("json", false, "print the findings as a JSON array")
+)
+
+// finding is the JSON form of an unused.Finding.
+type finding struct {
+	Category string `json:"category"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Package  string `json:"package"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// headings introduce each category in the text output.
+var headings = []struct{ category, heading string }{
+	{unused.CategoryUnreachable, "Unreachable functions and methods"},
+	{unused.CategoryUnreferenced, "Declarations never referenced by reachable code"},
+	{unused.CategoryWriteOnly, "Assigned but never read"},
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("unused: ")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: unused [flags] packages...\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	pkgs, err := driver.Load("", *tests, flag.Args()...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pattern := *filter
+	if pattern == "" {
+		if pkgs[0].Module == nil {
+			log.Fatal("not in a module; use -filter")
+		}
+		pattern = "^" + regexp.QuoteMeta(pkgs[0].Module.Path) + "(/|$)"
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		log.Fatalf("bad -filter: %v", err)
+	}
+	findings, err := unused.Find(pkgs, re)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *jsonOut {
+		out := []finding{}
+		for _, f := range findings {
+			out = append(out, finding{
+				Category: f.Category,
+				Kind:     f.Kind,
+				Name:     f.Name,
+				Package:  f.Package,
+				File:     relative(f.Pos.Filename),
+				Line:     f.Pos.Line,
+				Column:   f.Pos.Column,
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		first := true
+		for _, h := range headings {
+			printed := false
+			for _, f := range findings {
+				if f.Category != h.category {
+					continue
+				}
+				if !printed {
+					if !first {
+						fmt.Println()
+					}
+					fmt.Printf("%s:\n", h.heading)
+					printed, first = true, false
+				}
+				fmt.Printf("  %s:%d:%d: %s %s\n", relative(f.Pos.Filename), f.Pos.Line, f.Pos.Column, f.Kind, f.Name)
+			}
+		}
+	}
+	if len(findings) > 0 {
+		os.Exit(3)
+	}
+}
+
+func relative(name string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return name
+	}
+	if rel, err := filepath.Rel(wd, name); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return name
+}
@@ -0,0 +1,14 @@
+module github.com/bclements/antipatterns
+
+go 1.25.0
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	golang.org/x/tools v0.47.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
@@ -1,4 +1,4 @@
-package main
+package boatanchor
 
 /*
 ANTI-PATTERN: Boat Anchor
@@ -9,11 +9,13 @@ This weighs down the codebase like a boat anchor.
 
 import (
 	"fmt"
+	"io"
 )
 
 // UserManager manages users with lots of boat anchor code
 type UserManager struct {
 	users []User
+	out   io.Writer // where notifications are printed
 
 	// BOAT ANCHOR: This was for the legacy XML export feature we removed 2 years ago
 	// But keeping it just in case we need it again
@@ -30,10 +32,11 @@ type User struct {
 	Email string
 }
 
-// NewUserManager creates a new user manager
-func NewUserManager() *UserManager {
+// NewUserManager creates a new user manager that prints to w
+func NewUserManager(w io.Writer) *UserManager {
 	return &UserManager{
 		users:       make([]User, 0),
+		out:         w,
 		xmlExporter: NewXMLExporter(), // Instantiated but never used
 	}
 }
@@ -51,7 +54,7 @@ func (um *UserManager) AddUser(user User) {
 }
 
 func (um *UserManager) sendWebhookNotification(user User) {
-	fmt.Printf("Sending webhook for %s\n", user.Name)
+	fmt.Fprintf(um.out, "Sending webhook for %s\n", user.Name)
 }
 
 // BOAT ANCHOR: This hasn't been called in years but "we might need it"
@@ -179,9 +182,10 @@ func NewLegacyValidationError(msg string) *LegacyError {
 	return &LegacyError{Code: 1002, Message: msg}
 }
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	// Only the AddUser functionality is actually used
-	um := NewUserManager()
+	um := NewUserManager(w)
 	um.AddUser(User{ID: 1, Name: "John", Email: "john@example.com"})
 
 	// Everything else in this file is boat anchor code that's never called
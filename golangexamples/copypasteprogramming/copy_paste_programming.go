@@ -1,4 +1,4 @@
-package main
+package copypasteprogramming
 
 /*
 ANTI-PATTERN: Copy and Paste Programming
@@ -9,76 +9,79 @@ This leads to maintenance nightmares when bugs need to be fixed in multiple plac
 
 import (
 	"fmt"
+	"io"
 	"time"
 )
 
 // ReportGenerator - Code duplication everywhere instead of creating reusable methods
-type ReportGenerator struct{}
+type ReportGenerator struct {
+	out io.Writer // where reports are printed
+}
 
 func (rg *ReportGenerator) GenerateSalesReport(salesData []map[string]interface{}) {
 	// COPY-PASTE: Almost identical to expense report!
-	fmt.Println("==================================================")
-	fmt.Println("SALES REPORT")
-	fmt.Println("==================================================")
-	fmt.Printf("Generated: %s\n", time.Now().Format("2006-01-02"))
-	fmt.Printf("Total Records: %d\n", len(salesData))
-	fmt.Println("--------------------------------------------------")
+	fmt.Fprintln(rg.out, "==================================================")
+	fmt.Fprintln(rg.out, "SALES REPORT")
+	fmt.Fprintln(rg.out, "==================================================")
+	fmt.Fprintf(rg.out, "Generated: %s\n", time.Now().Format("2006-01-02"))
+	fmt.Fprintf(rg.out, "Total Records: %d\n", len(salesData))
+	fmt.Fprintln(rg.out, "--------------------------------------------------")
 
 	total := 0.0
 	for _, item := range salesData {
 		product := item["product"].(string)
 		amount := item["amount"].(float64)
-		fmt.Printf("%s: $%.2f\n", product, amount)
+		fmt.Fprintf(rg.out, "%s: $%.2f\n", product, amount)
 		total += amount
 	}
 
-	fmt.Println("--------------------------------------------------")
-	fmt.Printf("Total: $%.2f\n", total)
-	fmt.Println("==================================================")
+	fmt.Fprintln(rg.out, "--------------------------------------------------")
+	fmt.Fprintf(rg.out, "Total: $%.2f\n", total)
+	fmt.Fprintln(rg.out, "==================================================")
 }
 
 func (rg *ReportGenerator) GenerateExpenseReport(expenseData []map[string]interface{}) {
 	// COPY-PASTE: Almost identical to sales report!
-	fmt.Println("==================================================")
-	fmt.Println("EXPENSE REPORT")
-	fmt.Println("==================================================")
-	fmt.Printf("Generated: %s\n", time.Now().Format("2006-01-02"))
-	fmt.Printf("Total Records: %d\n", len(expenseData))
-	fmt.Println("--------------------------------------------------")
+	fmt.Fprintln(rg.out, "==================================================")
+	fmt.Fprintln(rg.out, "EXPENSE REPORT")
+	fmt.Fprintln(rg.out, "==================================================")
+	fmt.Fprintf(rg.out, "Generated: %s\n", time.Now().Format("2006-01-02"))
+	fmt.Fprintf(rg.out, "Total Records: %d\n", len(expenseData))
+	fmt.Fprintln(rg.out, "--------------------------------------------------")
 
 	total := 0.0
 	for _, item := range expenseData {
 		product := item["product"].(string)
 		amount := item["amount"].(float64)
-		fmt.Printf("%s: $%.2f\n", product, amount)
+		fmt.Fprintf(rg.out, "%s: $%.2f\n", product, amount)
 		total += amount
 	}
 
-	fmt.Println("--------------------------------------------------")
-	fmt.Printf("Total: $%.2f\n", total)
-	fmt.Println("==================================================")
+	fmt.Fprintln(rg.out, "--------------------------------------------------")
+	fmt.Fprintf(rg.out, "Total: $%.2f\n", total)
+	fmt.Fprintln(rg.out, "==================================================")
 }
 
 func (rg *ReportGenerator) GenerateInventoryReport(inventoryData []map[string]interface{}) {
 	// COPY-PASTE: Again, almost the same code!
-	fmt.Println("==================================================")
-	fmt.Println("INVENTORY REPORT")
-	fmt.Println("==================================================")
-	fmt.Printf("Generated: %s\n", time.Now().Format("2006-01-02"))
-	fmt.Printf("Total Records: %d\n", len(inventoryData))
-	fmt.Println("--------------------------------------------------")
+	fmt.Fprintln(rg.out, "==================================================")
+	fmt.Fprintln(rg.out, "INVENTORY REPORT")
+	fmt.Fprintln(rg.out, "==================================================")
+	fmt.Fprintf(rg.out, "Generated: %s\n", time.Now().Format("2006-01-02"))
+	fmt.Fprintf(rg.out, "Total Records: %d\n", len(inventoryData))
+	fmt.Fprintln(rg.out, "--------------------------------------------------")
 
 	total := 0.0
 	for _, item := range inventoryData {
 		product := item["product"].(string)
 		amount := item["amount"].(float64)
-		fmt.Printf("%s: $%.2f\n", product, amount)
+		fmt.Fprintf(rg.out, "%s: $%.2f\n", product, amount)
 		total += amount
 	}
 
-	fmt.Println("--------------------------------------------------")
-	fmt.Printf("Total: $%.2f\n", total)
-	fmt.Println("==================================================")
+	fmt.Fprintln(rg.out, "--------------------------------------------------")
+	fmt.Fprintf(rg.out, "Total: $%.2f\n", total)
+	fmt.Fprintln(rg.out, "==================================================")
 }
 
 // UserValidator - More copy-paste nightmares
@@ -303,33 +306,34 @@ func (ah *APIHandler) HandleGetCustomer(request *Request) *Response {
 }
 
 // COPY-PASTE: Duplicate logging functions
-func LogInfo(message string) {
-	fmt.Printf("[INFO] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+func LogInfo(w io.Writer, message string) {
+	fmt.Fprintf(w, "[INFO] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
 }
 
-func LogWarning(message string) {
+func LogWarning(w io.Writer, message string) {
 	// COPY-PASTE: Same as LogInfo with different prefix
-	fmt.Printf("[WARNING] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+	fmt.Fprintf(w, "[WARNING] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
 }
 
-func LogError(message string) {
+func LogError(w io.Writer, message string) {
 	// COPY-PASTE: Same as LogInfo with different prefix
-	fmt.Printf("[ERROR] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+	fmt.Fprintf(w, "[ERROR] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
 }
 
-func LogDebug(message string) {
+func LogDebug(w io.Writer, message string) {
 	// COPY-PASTE: Same as LogInfo with different prefix
-	fmt.Printf("[DEBUG] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+	fmt.Fprintf(w, "[DEBUG] %s - %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
 }
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	// All this code duplication means:
 	// - Bugs need to be fixed in multiple places
 	// - Changes require updating multiple locations
 	// - Code is harder to maintain
 	// - More opportunities for inconsistencies
 
-	rg := &ReportGenerator{}
+	rg := &ReportGenerator{out: w}
 	salesData := []map[string]interface{}{
 		{"product": "Widget", "amount": 100.0},
 	}
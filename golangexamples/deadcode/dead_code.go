@@ -1,4 +1,4 @@
-package main
+package deadcode
 
 /*
 ANTI-PATTERN: Dead Code
@@ -10,6 +10,7 @@ this is code that provably never runs.
 
 import (
 	"fmt"
+	"io"
 )
 
 // DEAD CODE: Function that's never called anywhere
@@ -36,7 +37,7 @@ type Order struct {
 }
 
 // ProcessOrder processes an order with lots of dead code inside
-func ProcessOrder(order *Order) *Order {
+func ProcessOrder(w io.Writer, order *Order) *Order {
 	// DEAD CODE: Variable assigned but never used
 	totalWeight := 0.0
 	_ = totalWeight // Added to avoid compiler error in this example
@@ -44,7 +45,7 @@ func ProcessOrder(order *Order) *Order {
 	// DEAD CODE: Unreachable code after return
 	if order.Status == "cancelled" {
 		return nil
-		fmt.Println("This will never print")   // DEAD CODE
+		fmt.Fprintln(w, "This will never print")   // DEAD CODE
 		order.Status = "processed"             // DEAD CODE
 	}
 
@@ -129,7 +130,7 @@ func (dp *DataProcessor) Reset() {
 }
 
 // CalculateDiscount calculates discount with dead branches
-func CalculateDiscount(amount float64, userType string) float64 {
+func CalculateDiscount(w io.Writer, amount float64, userType string) float64 {
 	var discount float64
 
 	// DEAD CODE: These conditions are mutually exclusive
@@ -149,7 +150,7 @@ func CalculateDiscount(amount float64, userType string) float64 {
 	}
 
 	// DEAD CODE: After all paths return
-	fmt.Println("Processing complete")
+	fmt.Fprintln(w, "Processing complete")
 	return 0
 }
 
@@ -224,7 +225,7 @@ func ComplexFunction(x, y int) int {
 }
 
 // SafeDivide divides numbers with dead exception handler
-func SafeDivide(a, b float64) float64 {
+func SafeDivide(w io.Writer, a, b float64) float64 {
 	// DEAD CODE: We handle zero before it can panic
 	if b == 0 {
 		return 0
@@ -232,7 +233,7 @@ func SafeDivide(a, b float64) float64 {
 	return a / b
 
 	// DEAD CODE: Unreachable due to return above
-	fmt.Println("Division complete")
+	fmt.Fprintln(w, "Division complete")
 	return 0
 }
 
@@ -262,12 +263,12 @@ func ProcessItems(items []int) []int {
 }
 
 // DEAD CODE: Decorator that's never used
-func unusedDecorator(fn func(int) int) func(int) int {
+func unusedDecorator(w io.Writer, fn func(int) int) func(int) int {
 	// This decorator is never applied to any function
 	return func(x int) int {
-		fmt.Println("Before")
+		fmt.Fprintln(w, "Before")
 		result := fn(x)
-		fmt.Println("After")
+		fmt.Fprintln(w, "After")
 		return result
 	}
 }
@@ -299,7 +300,7 @@ func (ui *UnusedImplementation) DoSomethingElse(x int) string {
 type UnusedAlias = map[string]interface{}
 
 // DEAD CODE: Function with all dead branches
-func DeadBranches(x int) int {
+func DeadBranches(w io.Writer, x int) int {
 	if x > 0 {
 		return 1
 	} else if x < 0 {
@@ -309,7 +310,7 @@ func DeadBranches(x int) int {
 	}
 
 	// DEAD CODE: All paths return above
-	fmt.Println("This is unreachable")
+	fmt.Fprintln(w, "This is unreachable")
 	return 999
 }
 
@@ -321,16 +322,16 @@ func init() {
 }
 
 // DEAD CODE: Method on pointer receiver that's never called
-func (um *UserManager) unusedMethod() {
+func (um *UserManager) unusedMethod(w io.Writer) {
 	// Never called anywhere
-	fmt.Println("This is never called")
+	fmt.Fprintln(w, "This is never called")
 }
 
 // DEAD CODE: Variadic function that's never called
-func unusedVariadic(args ...interface{}) {
+func unusedVariadic(w io.Writer, args ...interface{}) {
 	// Never called anywhere
 	for _, arg := range args {
-		fmt.Println(arg)
+		fmt.Fprintln(w, arg)
 	}
 }
 
@@ -340,11 +341,12 @@ func unusedErrorReturner() error {
 	return fmt.Errorf("this error is never seen")
 }
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	// Only a tiny portion of the code above is actually used
 	order := &Order{ID: 1, Status: "pending"}
-	result := ProcessOrder(order)
-	fmt.Printf("Order: %+v\n", result)
+	result := ProcessOrder(w, order)
+	fmt.Fprintf(w, "Order: %+v\n", result)
 
 	// Everything else in this file is dead code that's never executed
 }
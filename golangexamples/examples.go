@@ -0,0 +1,52 @@
+// Package golangexamples indexes the Go anti-pattern examples so they can be
+// listed and run by name.
+package golangexamples
+
+import (
+	"io"
+
+	"github.com/bclements/antipatterns/golangexamples/boatanchor"
+	"github.com/bclements/antipatterns/golangexamples/copypasteprogramming"
+	"github.com/bclements/antipatterns/golangexamples/deadcode"
+	"github.com/bclements/antipatterns/golangexamples/godobject"
+	"github.com/bclements/antipatterns/golangexamples/goldenhammer"
+	"github.com/bclements/antipatterns/golangexamples/hardcoding"
+	"github.com/bclements/antipatterns/golangexamples/lavaflow"
+	"github.com/bclements/antipatterns/golangexamples/leakyabstractions"
+	"github.com/bclements/antipatterns/golangexamples/reinventingthewheel"
+	"github.com/bclements/antipatterns/golangexamples/spaghetticode"
+)
+
+// Example is a single anti-pattern demo.
+type Example struct {
+	// Name is the example's file name without the .go suffix, e.g. "god_object".
+	Name string
+	// Title is the anti-pattern's name as used in ANTI_PATTERNS_SUMMARY.md.
+	Title string
+	// Run executes the demo, writing its output to w.
+	Run func(w io.Writer)
+}
+
+// All lists every example in the order ANTI_PATTERNS_SUMMARY.md presents them.
+var All = []Example{
+	{Name: "boat_anchor", Title: "Boat Anchor", Run: boatanchor.Run},
+	{Name: "spaghetti_code", Title: "Spaghetti Code", Run: spaghetticode.Run},
+	{Name: "golden_hammer", Title: "Golden Hammer", Run: goldenhammer.Run},
+	{Name: "copy_paste_programming", Title: "Copy and Paste Programming", Run: copypasteprogramming.Run},
+	{Name: "god_object", Title: "God Object", Run: godobject.Run},
+	{Name: "lava_flow", Title: "Lava Flow", Run: lavaflow.Run},
+	{Name: "dead_code", Title: "Dead Code", Run: deadcode.Run},
+	{Name: "hard_coding", Title: "Hard Coding", Run: hardcoding.Run},
+	{Name: "reinventing_the_wheel", Title: "Reinventing the Wheel", Run: reinventingthewheel.Run},
+	{Name: "leaky_abstractions", Title: "Leaky Abstractions", Run: leakyabstractions.Run},
+}
+
+// Lookup returns the example with the given name.
+func Lookup(name string) (Example, bool) {
+	for _, ex := range All {
+		if ex.Name == name {
+			return ex, true
+		}
+	}
+	return Example{}, false
+}
@@ -1,4 +1,4 @@
-package main
+package godobject
 
 /*
 ANTI-PATTERN: God Object
@@ -10,6 +10,7 @@ Violates the Single Responsibility Principle.
 
 import (
 	"fmt"
+	"io"
 	"time"
 )
 
@@ -462,7 +463,8 @@ func (am *ApplicationManager) IsFeatureEnabled(featureName string) bool {
 	return am.featureFlags[featureName]
 }
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	/*
 		This God Object violates:
 		- Single Responsibility Principle (it has dozens of responsibilities)
@@ -487,6 +489,6 @@ func main() {
 	*/
 
 	am := NewApplicationManager()
-	fmt.Printf("God object created with %d responsibilities\n", 15)
+	fmt.Fprintf(w, "God object created with %d responsibilities\n", 15)
 	_ = am
 }
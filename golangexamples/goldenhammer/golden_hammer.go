@@ -1,4 +1,4 @@
-package main
+package goldenhammer
 
 /*
 ANTI-PATTERN: Golden Hammer
@@ -9,6 +9,7 @@ Using the same solution/tool for every problem regardless of whether it's approp
 
 import (
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -135,10 +136,10 @@ func (sfe *StructForEverything) GetConfig() StringPair {
 type GoRoutineForEverything struct{}
 
 // GOLDEN HAMMER: Using goroutine for a simple function call
-func (gfe *GoRoutineForEverything) PrintMessage(msg string) {
+func (gfe *GoRoutineForEverything) PrintMessage(w io.Writer, msg string) {
 	// No need for a goroutine here, adds unnecessary complexity
 	go func() {
-		fmt.Println(msg)
+		fmt.Fprintln(w, msg)
 	}()
 }
 
@@ -266,15 +267,16 @@ Each service has its own repository, database, API, and deployment pipeline
 for functionality that should just be simple functions!
 */
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	// Examples of golden hammer in action
 	rf := &RegexFanatic{}
-	fmt.Println("Is 4 even?", rf.IsEven(4)) // Using regex instead of modulo!
+	fmt.Fprintln(w, "Is 4 even?", rf.IsEven(4)) // Using regex instead of modulo!
 
 	pfe := &PointerForEverything{}
 	a, b := 5, 10
 	result := pfe.AddNumbers(&a, &b) // Unnecessary pointer complexity
-	fmt.Println("Sum:", *result)
+	fmt.Fprintln(w, "Sum:", *result)
 
 	// The golden hammer principle: when all you have is a hammer,
 	// everything looks like a nail. Use the right tool for the job!
@@ -1,4 +1,4 @@
-package main
+package hardcoding
 
 /*
 ANTI-PATTERN: Hard Coding
@@ -10,6 +10,7 @@ Makes the code inflexible and difficult to deploy to different environments.
 
 import (
 	"fmt"
+	"io"
 )
 
 // DatabaseConnection - HARD CODING: Database credentials embedded in code
@@ -42,7 +43,7 @@ func (dbc *DatabaseConnection) Connect() string {
 // EmailService - HARD CODING: Email configuration embedded
 type EmailService struct{}
 
-func (es *EmailService) SendEmail(to, subject, body string) {
+func (es *EmailService) SendEmail(w io.Writer, to, subject, body string) {
 	// HARD CODING: SMTP settings should be configurable
 	smtpServer := "smtp.gmail.com"
 	smtpPort := 587
@@ -59,7 +60,7 @@ func (es *EmailService) SendEmail(to, subject, body string) {
 	}
 
 	// Simulate sending email
-	fmt.Printf("Sending email via %s:%d\n", smtpServer, smtpPort)
+	fmt.Fprintf(w, "Sending email via %s:%d\n", smtpServer, smtpPort)
 	_ = smtpUsername
 	_ = smtpPassword
 	_ = body
@@ -96,7 +97,7 @@ func (ac *APIClient) MakeRequest(endpoint string) (string, map[string]string) {
 // FileManager - HARD CODING: File paths embedded in code
 type FileManager struct{}
 
-func (fm *FileManager) SaveFile(filename string, content []byte) {
+func (fm *FileManager) SaveFile(w io.Writer, filename string, content []byte) {
 	// HARD CODING: Absolute paths that won't work on other systems
 	basePath := "/home/john/projects/myapp/uploads"
 	filePath := fmt.Sprintf("%s/%s", basePath, filename)
@@ -104,8 +105,8 @@ func (fm *FileManager) SaveFile(filename string, content []byte) {
 	// HARD CODING: Log file path
 	logPath := "/var/log/myapp/file_operations.log"
 
-	fmt.Printf("Saving to %s\n", filePath)
-	fmt.Printf("Logging to %s\n", logPath)
+	fmt.Fprintf(w, "Saving to %s\n", filePath)
+	fmt.Fprintf(w, "Logging to %s\n", logPath)
 }
 
 func (fm *FileManager) GetConfig() string {
@@ -182,7 +183,7 @@ const (
 )
 
 // SendNotification - HARD CODING: Notification service configuration
-func SendNotification(userID int, message string) {
+func SendNotification(w io.Writer, userID int, message string) {
 	// HARD CODING: Slack webhook
 	slackWebhook := "https://hooks.slack.com/services/T00/B00/XXXXXXXXXXXXXXXX"
 
@@ -195,7 +196,7 @@ func SendNotification(userID int, message string) {
 	channel := "#notifications"
 	username := "Bot"
 
-	fmt.Printf("Sending to %s\n", slackWebhook)
+	fmt.Fprintf(w, "Sending to %s\n", slackWebhook)
 	_ = channel
 	_ = username
 	_ = userID
@@ -357,7 +358,8 @@ const (
 	TwilioEndpoint    = "https://api.twilio.com/2010-04-01"
 )
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	/*
 		All of these hardcoded values should be:
 		1. Moved to environment variables (.env file)
@@ -369,11 +371,11 @@ func main() {
 	*/
 
 	db := NewDatabaseConnection()
-	fmt.Println("Database connection:", db.Connect())
+	fmt.Fprintln(w, "Database connection:", db.Connect())
 
 	api := NewAPIClient()
 	url, _ := api.MakeRequest("users")
-	fmt.Println("API URL:", url)
+	fmt.Fprintln(w, "API URL:", url)
 
 	// All the hardcoded values make this code:
 	// - Insecure (credentials in code)
@@ -1,4 +1,4 @@
-package main
+package lavaflow
 
 /*
 ANTI-PATTERN: Lava Flow
@@ -10,6 +10,7 @@ this code is now part of the landscape but serves no purpose.
 
 import (
 	"fmt"
+	"io"
 )
 
 // LAVA FLOW: Nobody knows what this does or if it's still needed
@@ -357,11 +358,12 @@ var (
 	LegacyModeEnabled      = false // Legacy mode never actually implemented
 )
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	// Modern usage
 	dp := NewDataProcessor()
 	result := dp.ProcessData([]int{1, 2, 3, 4, 5})
-	fmt.Printf("Processed data: %v\n", result)
+	fmt.Fprintf(w, "Processed data: %v\n", result)
 
 	// Everything else in this file is lava flow - code that nobody dares to remove
 	// because nobody knows if it's safe to do so
@@ -1,4 +1,4 @@
-package main
+package leakyabstractions
 
 /*
 ANTI-PATTERN: Leaky Abstractions
@@ -61,7 +61,7 @@ func (r *RedisCache) Delete(key string) error {
 }
 
 // LEAKY ABSTRACTION: Code that uses Cache is coupled to Redis implementation
-func ProcessDataBAD(cache Cache) error {
+func ProcessDataBAD(w io.Writer, cache Cache) error {
 	/*
 		PROBLEM: The interface claims to hide the implementation, but we're
 		forced to check for Redis-specific errors!
@@ -78,14 +78,14 @@ func ProcessDataBAD(cache Cache) error {
 	// LEAK: Checking for implementation-specific error!
 	if err == ErrRedisPoolExhausted {
 		// Now we're coupled to Redis - the abstraction has leaked
-		fmt.Println("Redis pool exhausted - retry logic")
+		fmt.Fprintln(w, "Redis pool exhausted - retry logic")
 		time.Sleep(100 * time.Millisecond)
 		return cache.Set("key", "value")
 	}
 
 	// LEAK: Another Redis-specific error check
 	if err == ErrRedisTimeout {
-		fmt.Println("Redis timeout - adjust timeout settings")
+		fmt.Fprintln(w, "Redis timeout - adjust timeout settings")
 	}
 
 	return err
@@ -139,7 +139,7 @@ func (dr *DataReader) ReadNext() (string, error) {
 	return string(dr.buffer[:n]), nil
 }
 
-func ProcessFileBAD() {
+func ProcessFileBAD(w io.Writer) {
 	/*
 		PROBLEM: This looks simple, but performance completely depends on
 		understanding buffer management which should be hidden!
@@ -149,10 +149,10 @@ func ProcessFileBAD() {
 	// LEAK: Tiny buffer size causes many system calls
 	// You need to understand this to use the abstraction efficiently!
 	data, _ := reader.ReadNext()
-	fmt.Println(data)
+	fmt.Fprintln(w, data)
 }
 
-func ProcessFileGOOD() {
+func ProcessFileGOOD(w io.Writer) {
 	/*
 		SOLUTION: Use appropriate buffer size, but this means understanding
 		the underlying I/O model. The abstraction leaked!
@@ -160,7 +160,7 @@ func ProcessFileGOOD() {
 	reader := NewDataReader(strings.NewReader("hello world"), 4096)
 
 	data, _ := reader.ReadNext()
-	fmt.Println(data)
+	fmt.Fprintln(w, data)
 }
 
 // ==============================================================================
@@ -203,7 +203,7 @@ func (op *ObjectPool) Put(obj *ExpensiveObject) {
 	op.pool.Put(obj)
 }
 
-func UsePoolBAD() {
+func UsePoolBAD(w io.Writer) {
 	/*
 		PROBLEM: This looks like guaranteed object reuse, but sync.Pool
 		doesn't guarantee anything! GC can clear it at any time.
@@ -218,7 +218,7 @@ func UsePoolBAD() {
 	// You need to understand sync.Pool behavior to use it correctly.
 	obj2 := pool.Get()
 	if obj2 != obj {
-		fmt.Println("Different object - sync.Pool was cleared!")
+		fmt.Fprintln(w, "Different object - sync.Pool was cleared!")
 	}
 }
 
@@ -232,10 +232,13 @@ type HTTPClient struct {
 	MaxIdleConns        int
 	MaxIdleConnsPerHost int
 	IdleConnTimeout     time.Duration
+
+	out io.Writer // where requests are logged
 }
 
-func NewHTTPClient() *HTTPClient {
+func NewHTTPClient(w io.Writer) *HTTPClient {
 	return &HTTPClient{
+		out:                 w,
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 2,
 		IdleConnTimeout:     90 * time.Second,
@@ -255,16 +258,16 @@ func (hc *HTTPClient) Get(url string) (string, error) {
 
 		The abstraction leaks TCP connection management.
 	*/
-	fmt.Printf("[HTTP] GET %s (using connection pool)\n", url)
+	fmt.Fprintf(hc.out, "[HTTP] GET %s (using connection pool)\n", url)
 	return "response", nil
 }
 
-func MakeRequestsBAD() {
+func MakeRequestsBAD(w io.Writer) {
 	/*
 		PROBLEM: Looks simple, but connection behavior is completely opaque.
 		Performance depends on understanding connection pooling!
 	*/
-	client := NewHTTPClient()
+	client := NewHTTPClient(w)
 
 	// LEAK: Are these using the same connection? New connections?
 	// You can't tell without understanding the implementation!
@@ -322,7 +325,7 @@ func (wq *WorkQueue) Start() {
 	}()
 }
 
-func UseWorkQueueBAD() {
+func UseWorkQueueBAD(w io.Writer) {
 	/*
 		PROBLEM: Behavior completely changes based on buffer size,
 		but this is supposed to be hidden by the abstraction!
@@ -331,17 +334,17 @@ func UseWorkQueueBAD() {
 	queue.Start()
 
 	// LEAK: This works fine
-	_ = queue.Submit(func() { fmt.Println("Task 1") })
+	_ = queue.Submit(func() { fmt.Fprintln(w, "Task 1") })
 
 	// LEAK: This might fail because buffer is full!
 	// You need to understand channel buffering to use this correctly.
-	err := queue.Submit(func() { fmt.Println("Task 2") })
+	err := queue.Submit(func() { fmt.Fprintln(w, "Task 2") })
 	if err != nil {
-		fmt.Println("Queue full - needed to understand buffering!")
+		fmt.Fprintln(w, "Queue full - needed to understand buffering!")
 	}
 }
 
-func UseWorkQueueGOOD() {
+func UseWorkQueueGOOD(w io.Writer) {
 	/*
 		SOLUTION: Use larger buffer, but this means understanding
 		the channel implementation. The abstraction leaked!
@@ -350,7 +353,7 @@ func UseWorkQueueGOOD() {
 	queue.Start()
 
 	for i := 0; i < 100; i++ {
-		_ = queue.Submit(func() { fmt.Println("Task") })
+		_ = queue.Submit(func() { fmt.Fprintln(w, "Task") })
 	}
 }
 
@@ -361,6 +364,7 @@ func UseWorkQueueGOOD() {
 // Service claims to abstract business logic, but leaks context semantics
 type Service struct {
 	name string
+	out  io.Writer
 }
 
 func (s *Service) ProcessRequest(ctx context.Context, request string) error {
@@ -379,12 +383,12 @@ func (s *Service) ProcessRequest(ctx context.Context, request string) error {
 		// The abstraction doesn't tell you!
 		return ctx.Err()
 	case <-time.After(100 * time.Millisecond):
-		fmt.Printf("[%s] Processed: %s\n", s.name, request)
+		fmt.Fprintf(s.out, "[%s] Processed: %s\n", s.name, request)
 		return nil
 	}
 }
 
-func CallServiceBAD() {
+func CallServiceBAD(w io.Writer) {
 	/*
 		PROBLEM: Context cancellation behavior is opaque.
 		You need to understand context semantics to use this correctly!
@@ -392,7 +396,7 @@ func CallServiceBAD() {
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
 
-	service := &Service{name: "UserService"}
+	service := &Service{name: "UserService", out: w}
 
 	// LEAK: This will timeout, but the error tells you nothing about
 	// whether it was context timeout, cancellation, or deadline!
@@ -400,7 +404,7 @@ func CallServiceBAD() {
 	if err != nil {
 		// LEAK: Is this context.DeadlineExceeded or context.Canceled?
 		// You need to understand context error types!
-		fmt.Printf("Error: %v\n", err)
+		fmt.Fprintf(w, "Error: %v\n", err)
 	}
 }
 
@@ -411,6 +415,7 @@ func CallServiceBAD() {
 // DB claims to abstract database, but leaks transaction behavior
 type DB struct {
 	inTransaction bool
+	out           io.Writer
 }
 
 func (db *DB) Query(query string) ([]map[string]interface{}, error) {
@@ -423,7 +428,7 @@ func (db *DB) Query(query string) ([]map[string]interface{}, error) {
 
 		The abstraction leaks SQL transaction semantics.
 	*/
-	fmt.Printf("[DB] Query: %s (in_transaction: %v)\n", query, db.inTransaction)
+	fmt.Fprintf(db.out, "[DB] Query: %s (in_transaction: %v)\n", query, db.inTransaction)
 	return nil, nil
 }
 
@@ -446,12 +451,12 @@ func (db *DB) Rollback() error {
 	return nil
 }
 
-func UseDBBAD() {
+func UseDBBAD(w io.Writer) {
 	/*
 		PROBLEM: Transaction behavior is opaque. What happens if you
 		forget to commit? What isolation level? The abstraction leaks!
 	*/
-	db := &DB{}
+	db := &DB{out: w}
 
 	_ = db.Begin()
 	_, _ = db.Query("UPDATE users SET balance = balance - 100 WHERE id = 1")
@@ -466,48 +471,49 @@ func UseDBBAD() {
 // MAIN - Demonstrate Leaky Abstractions
 // ==============================================================================
 
-func main() {
-	fmt.Println("=" + strings.Repeat("=", 79))
-	fmt.Println("LEAKY ABSTRACTIONS ANTI-PATTERN")
-	fmt.Println("=" + strings.Repeat("=", 79))
-	fmt.Println()
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
+	fmt.Fprintln(w, "=" + strings.Repeat("=", 79))
+	fmt.Fprintln(w, "LEAKY ABSTRACTIONS ANTI-PATTERN")
+	fmt.Fprintln(w, "=" + strings.Repeat("=", 79))
+	fmt.Fprintln(w)
 
-	fmt.Println("Example 1: Interface Leaking Through Error Types")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Fprintln(w, "Example 1: Interface Leaking Through Error Types")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
 	cache := &RedisCache{connected: false}
-	_ = ProcessDataBAD(cache)
-	fmt.Println()
-
-	fmt.Println("Example 2: io.Reader Leaking Buffer Management")
-	fmt.Println(strings.Repeat("-", 80))
-	ProcessFileBAD()
-	fmt.Println()
-
-	fmt.Println("Example 3: sync.Pool Leaking Memory Semantics")
-	fmt.Println(strings.Repeat("-", 80))
-	UsePoolBAD()
-	fmt.Println()
-
-	fmt.Println("Example 5: Channel Buffering Leaking Goroutine Behavior")
-	fmt.Println(strings.Repeat("-", 80))
-	UseWorkQueueBAD()
+	_ = ProcessDataBAD(w, cache)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Example 2: io.Reader Leaking Buffer Management")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+	ProcessFileBAD(w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Example 3: sync.Pool Leaking Memory Semantics")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+	UsePoolBAD(w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Example 5: Channel Buffering Leaking Goroutine Behavior")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+	UseWorkQueueBAD(w)
 	time.Sleep(200 * time.Millisecond) // Wait for goroutines
-	fmt.Println()
-
-	fmt.Println("Example 6: context.Context Leaking Cancellation")
-	fmt.Println(strings.Repeat("-", 80))
-	CallServiceBAD()
-	fmt.Println()
-
-	fmt.Println("Example 7: Database Leaking Transaction Semantics")
-	fmt.Println(strings.Repeat("-", 80))
-	UseDBBAD()
-	fmt.Println()
-
-	fmt.Println("=" + strings.Repeat("=", 79))
-	fmt.Println("KEY INSIGHT: All abstractions leak to some degree.")
-	fmt.Println("Good abstractions leak gracefully and document what leaks.")
-	fmt.Println("Bad ones force you to understand implementation details to use")
-	fmt.Println("them correctly, defeating the purpose of abstraction.")
-	fmt.Println("=" + strings.Repeat("=", 79))
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Example 6: context.Context Leaking Cancellation")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+	CallServiceBAD(w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Example 7: Database Leaking Transaction Semantics")
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+	UseDBBAD(w)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "=" + strings.Repeat("=", 79))
+	fmt.Fprintln(w, "KEY INSIGHT: All abstractions leak to some degree.")
+	fmt.Fprintln(w, "Good abstractions leak gracefully and document what leaks.")
+	fmt.Fprintln(w, "Bad ones force you to understand implementation details to use")
+	fmt.Fprintln(w, "them correctly, defeating the purpose of abstraction.")
+	fmt.Fprintln(w, "=" + strings.Repeat("=", 79))
 }
@@ -1,4 +1,4 @@
-package main
+package reinventingthewheel
 
 /*
 ANTI-PATTERN: Reinventing the Wheel
@@ -10,6 +10,7 @@ in buggier, less efficient code.
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
 	"strings"
 )
@@ -132,11 +133,12 @@ type CustomLogger struct {
 		Use: import "log"; log.Println() or logrus/zap for more features
 	*/
 	filename string
+	out      io.Writer
 }
 
 func (cl *CustomLogger) Log(message string) {
 	// Missing: log levels, formatting, rotation, structured logging
-	fmt.Println(message)
+	fmt.Fprintln(cl.out, message)
 }
 
 // REINVENTING THE WHEEL: Custom configuration parser
@@ -390,7 +392,8 @@ func encodeBase64(data []byte) string {
 	return ""
 }
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	/*
 		Why reinventing the wheel is bad:
 		1. Wastes development time
@@ -425,5 +428,5 @@ func main() {
 		- regexp - regular expressions
 	*/
 
-	fmt.Println("Don't reinvent the wheel - use existing libraries!")
+	fmt.Fprintln(w, "Don't reinvent the wheel - use existing libraries!")
 }
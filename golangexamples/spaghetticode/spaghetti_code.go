@@ -1,4 +1,4 @@
-package main
+package spaghetticode
 
 /*
 ANTI-PATTERN: Spaghetti Code
@@ -9,6 +9,7 @@ The logic jumps around making it hard to follow the execution path.
 
 import (
 	"fmt"
+	"io"
 )
 
 // Order represents an order in the system
@@ -329,7 +330,8 @@ func ValidateUser(username, password, email, userType string, age int, country s
 	return false, "username required"
 }
 
-func main() {
+// Run demonstrates the anti-pattern, writing everything it prints to w.
+func Run(w io.Writer) {
 	// Example usage
 	items := []Item{
 		{Product: "Item1", Price: 10.0},
@@ -338,7 +340,7 @@ func main() {
 
 	order := ProcessOrder(1, "premium", "credit", "SAVE20", "express", items)
 	if order != nil {
-		fmt.Printf("Order processed: %+v\n", order)
+		fmt.Fprintf(w, "Order processed: %+v\n", order)
 	}
 
 	// Trying to understand what this code does is like untangling spaghetti
@@ -0,0 +1,60 @@
+// Package boatanchor is the Boat Anchor example with the anchors cut loose:
+// the XML exporter, the e-mail notifier, the old payment provider and the
+// rollback constants are deleted rather than kept "just in case". Version
+// control remembers them if they are ever needed again.
+package boatanchor
+
+import (
+	"fmt"
+	"io"
+)
+
+// User is a registered user.
+type User struct {
+	ID    int
+	Name  string
+	Email string
+}
+
+// A Notifier tells the outside world about new users.
+type Notifier interface {
+	UserAdded(User)
+}
+
+// WebhookNotifier notifies by webhook, the only channel in use.
+type WebhookNotifier struct {
+	W io.Writer
+}
+
+// UserAdded sends the webhook for u.
+func (n WebhookNotifier) UserAdded(u User) {
+	fmt.Fprintf(n.W, "Sending webhook for %s\n", u.Name)
+}
+
+// UserManager stores users and announces each one added.
+type UserManager struct {
+	users    []User
+	notifier Notifier
+}
+
+// NewUserManager returns an empty manager that announces users through n.
+func NewUserManager(n Notifier) *UserManager {
+	return &UserManager{notifier: n}
+}
+
+// AddUser stores u and notifies.
+func (um *UserManager) AddUser(u User) {
+	um.users = append(um.users, u)
+	um.notifier.UserAdded(u)
+}
+
+// Users returns the users added so far.
+func (um *UserManager) Users() []User {
+	return um.users
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	um := NewUserManager(WebhookNotifier{W: w})
+	um.AddUser(User{ID: 1, Name: "John", Email: "john@example.com"})
+}
@@ -0,0 +1,156 @@
+// Package copypasteprogramming is the Copy and Paste Programming example
+// with every pasted block written once: one report writer for sales,
+// expenses and inventory, one credential check shared by every kind of
+// user, one lookup for every table, one guard for every handler and one
+// logging function for every level.
+package copypasteprogramming
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// A Line is one named amount in a report.
+type Line struct {
+	Name   string
+	Amount float64
+}
+
+const (
+	heavyRule = "=================================================="
+	lightRule = "--------------------------------------------------"
+)
+
+// WriteReport writes a titled report of lines and their total, dated now.
+// Sales, expense and inventory reports differ only in their title.
+func WriteReport(w io.Writer, title string, lines []Line, now time.Time) {
+	fmt.Fprintln(w, heavyRule)
+	fmt.Fprintln(w, strings.ToUpper(title)+" REPORT")
+	fmt.Fprintln(w, heavyRule)
+	fmt.Fprintf(w, "Generated: %s\n", now.Format(time.DateOnly))
+	fmt.Fprintf(w, "Total Records: %d\n", len(lines))
+	fmt.Fprintln(w, lightRule)
+	total := 0.0
+	for _, l := range lines {
+		fmt.Fprintf(w, "%s: $%.2f\n", l.Name, l.Amount)
+		total += l.Amount
+	}
+	fmt.Fprintln(w, lightRule)
+	fmt.Fprintf(w, "Total: $%.2f\n", total)
+	fmt.Fprintln(w, heavyRule)
+}
+
+// A Role is a kind of user, with the rule its usernames follow.
+type Role struct {
+	// Prefix is the username prefix the role requires, or, if Forbid is
+	// set, forbids.
+	Prefix string
+	Forbid bool
+	// Message explains a username that breaks the rule.
+	Message string
+}
+
+// The roles of the original's three copies of the validator.
+var (
+	Admin   = Role{Prefix: "admin_", Message: "Admin username must start with admin_"}
+	Regular = Role{Prefix: "admin_", Forbid: true, Message: "Regular users cannot have admin_ prefix"}
+	Guest   = Role{Prefix: "guest_", Message: "Guest username must start with guest_"}
+)
+
+// ValidateUser checks credentials common to every user, then the role's
+// username rule. It returns false and the first problem, or true and
+// "Valid".
+func ValidateUser(role Role, username, password, email string) (bool, string) {
+	switch {
+	case username == "":
+		return false, "Username is required"
+	case len(username) < 3:
+		return false, "Username too short"
+	case password == "":
+		return false, "Password is required"
+	case len(password) < 8:
+		return false, "Password too short"
+	case email == "":
+		return false, "Email is required"
+	case !strings.Contains(email, "@"):
+		return false, "Invalid email"
+	case strings.HasPrefix(username, role.Prefix) == role.Forbid:
+		return false, role.Message
+	}
+	return true, "Valid"
+}
+
+// A Record is a row read from the database.
+type Record map[string]any
+
+// DB stands in for a database; tables holds each table's sample row.
+type DB struct {
+	tables map[string]Record
+}
+
+// NewDB returns a database with a sample row in each table.
+func NewDB() *DB {
+	return &DB{tables: map[string]Record{
+		"users":     {"name": "User"},
+		"products":  {"name": "Product"},
+		"orders":    {"status": "pending"},
+		"customers": {"name": "Customer"},
+	}}
+}
+
+// ByID returns the row of table with the given id. Connecting, querying
+// and closing happen here once instead of in a method per table.
+func (db *DB) ByID(table string, id int) (Record, error) {
+	row, ok := db.tables[table]
+	if !ok {
+		return nil, fmt.Errorf("no table %q", table)
+	}
+	r := Record{"id": id}
+	for k, v := range row {
+		r[k] = v
+	}
+	return r, nil
+}
+
+// A Request carries a handler's input.
+type Request struct {
+	Data map[string]any
+}
+
+// A Response is a handler's result.
+type Response struct {
+	Body   map[string]any
+	Status int
+}
+
+// A Handler processes a request that has data.
+type Handler func(data map[string]any) *Response
+
+// RequireData wraps h so that requests without data are rejected with 400
+// before h sees them.
+func RequireData(h Handler) func(*Request) *Response {
+	return func(r *Request) *Response {
+		if r.Data == nil {
+			return &Response{Body: map[string]any{"error": "No data provided"}, Status: 400}
+		}
+		return h(r.Data)
+	}
+}
+
+// OK is a handler that succeeds, standing in for the user, product, order
+// and customer handlers.
+func OK(map[string]any) *Response {
+	return &Response{Body: map[string]any{"success": true}, Status: 200}
+}
+
+// Log writes message at level, e.g. "INFO" or "ERROR", with a timestamp.
+func Log(w io.Writer, level, message string) {
+	fmt.Fprintf(w, "[%s] %s - %s\n", level, time.Now().Format(time.DateTime), message)
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	WriteReport(w, "Sales", []Line{{Name: "Widget", Amount: 100.0}}, time.Now())
+}
@@ -0,0 +1,74 @@
+// Package deadcode is the Dead Code example with the dead code deleted:
+// the statements after returns, the branches whose conditions could never
+// hold, the assignments nobody read and the declarations nobody called.
+// What is left behaves exactly as before.
+package deadcode
+
+import (
+	"fmt"
+	"io"
+)
+
+// Order represents an order.
+type Order struct {
+	ID     int
+	Status string
+	Items  []string
+}
+
+// ProcessOrder returns order, or nil if it has been cancelled.
+func ProcessOrder(order *Order) *Order {
+	if order.Status == "cancelled" {
+		return nil
+	}
+	return order
+}
+
+// Double returns each value of data times two.
+func Double(data []int) []int {
+	out := make([]int, len(data))
+	for i, v := range data {
+		out[i] = v * 2
+	}
+	return out
+}
+
+// discounts maps a user type to its discount rate.
+var discounts = map[string]float64{
+	"premium": 0.2,
+	"regular": 0.1,
+}
+
+// CalculateDiscount returns amount less the user type's discount, or 0 if
+// amount is not positive.
+func CalculateDiscount(amount float64, userType string) float64 {
+	if amount <= 0 {
+		return 0
+	}
+	return amount * (1 - discounts[userType])
+}
+
+// SafeDivide returns a / b, or 0 if b is 0.
+func SafeDivide(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// UserManager keeps a list of users.
+type UserManager struct {
+	users []string
+}
+
+// AddUser adds user and returns it.
+func (um *UserManager) AddUser(user string) string {
+	um.users = append(um.users, user)
+	return user
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	order := &Order{ID: 1, Status: "pending"}
+	fmt.Fprintf(w, "Order: %+v\n", ProcessOrder(order))
+}
@@ -0,0 +1,44 @@
+// Package goodexamples indexes the idiomatic rewrites of the Go anti-pattern
+// examples. Each package here has the same name as the example it rewrites
+// and its Run prints the same output, except where the output was itself
+// a symptom of the anti-pattern, so that a demo can run both and diff them.
+package goodexamples
+
+import (
+	"github.com/bclements/antipatterns/golangexamples"
+	"github.com/bclements/antipatterns/goodexamples/boatanchor"
+	"github.com/bclements/antipatterns/goodexamples/copypasteprogramming"
+	"github.com/bclements/antipatterns/goodexamples/deadcode"
+	"github.com/bclements/antipatterns/goodexamples/godobject"
+	"github.com/bclements/antipatterns/goodexamples/goldenhammer"
+	"github.com/bclements/antipatterns/goodexamples/hardcoding"
+	"github.com/bclements/antipatterns/goodexamples/lavaflow"
+	"github.com/bclements/antipatterns/goodexamples/leakyabstractions"
+	"github.com/bclements/antipatterns/goodexamples/reinventingthewheel"
+	"github.com/bclements/antipatterns/goodexamples/spaghetticode"
+)
+
+// All lists the rewrites under the names and titles of the examples they
+// rewrite, in the same order as golangexamples.All.
+var All = []golangexamples.Example{
+	{Name: "boat_anchor", Title: "Boat Anchor", Run: boatanchor.Run},
+	{Name: "spaghetti_code", Title: "Spaghetti Code", Run: spaghetticode.Run},
+	{Name: "golden_hammer", Title: "Golden Hammer", Run: goldenhammer.Run},
+	{Name: "copy_paste_programming", Title: "Copy and Paste Programming", Run: copypasteprogramming.Run},
+	{Name: "god_object", Title: "God Object", Run: godobject.Run},
+	{Name: "lava_flow", Title: "Lava Flow", Run: lavaflow.Run},
+	{Name: "dead_code", Title: "Dead Code", Run: deadcode.Run},
+	{Name: "hard_coding", Title: "Hard Coding", Run: hardcoding.Run},
+	{Name: "reinventing_the_wheel", Title: "Reinventing the Wheel", Run: reinventingthewheel.Run},
+	{Name: "leaky_abstractions", Title: "Leaky Abstractions", Run: leakyabstractions.Run},
+}
+
+// Lookup returns the rewrite of the example with the given name.
+func Lookup(name string) (golangexamples.Example, bool) {
+	for _, ex := range All {
+		if ex.Name == name {
+			return ex, true
+		}
+	}
+	return golangexamples.Example{}, false
+}
@@ -0,0 +1,61 @@
+package godobject
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is something a user did, such as viewing a page.
+type Event struct {
+	UserID int
+	Name   string
+	Data   map[string]any
+	Time   time.Time
+}
+
+// PageViewEvent is the name of the event PageView records.
+const PageViewEvent = "page_view"
+
+// AnalyticsService records what users do.
+type AnalyticsService interface {
+	Track(userID int, name string, data map[string]any)
+	PageView(userID int, page string)
+	// Report counts the events of each name in [start, end).
+	Report(start, end time.Time) map[string]int
+}
+
+// MemoryAnalytics is an AnalyticsService that keeps events in memory.
+type MemoryAnalytics struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewMemoryAnalytics returns an empty recorder.
+func NewMemoryAnalytics() *MemoryAnalytics {
+	return &MemoryAnalytics{now: time.Now}
+}
+
+func (a *MemoryAnalytics) Track(userID int, name string, data map[string]any) {
+	e := Event{UserID: userID, Name: name, Data: data, Time: a.now()}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, e)
+}
+
+func (a *MemoryAnalytics) PageView(userID int, page string) {
+	a.Track(userID, PageViewEvent, map[string]any{"page": page})
+}
+
+func (a *MemoryAnalytics) Report(start, end time.Time) map[string]int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	counts := make(map[string]int)
+	for _, e := range a.events {
+		if !e.Time.Before(start) && e.Time.Before(end) {
+			counts[e.Name]++
+		}
+	}
+	return counts
+}
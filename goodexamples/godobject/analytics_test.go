@@ -0,0 +1,41 @@
+package godobject
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestMemoryAnalytics(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	a := NewMemoryAnalytics()
+	a.now = func() time.Time { return now }
+	a.PageView(1, "/")
+	a.Track(1, "signup", nil)
+	now = start.Add(time.Hour)
+	a.PageView(2, "/")
+	a.PageView(1, "/checkout")
+	now = start.Add(2 * time.Hour)
+	a.Track(1, "purchase", map[string]any{"total": 9.99})
+
+	for _, test := range []struct {
+		name       string
+		start, end time.Time
+		want       map[string]int
+	}{
+		{"all", start, start.Add(3 * time.Hour), map[string]int{PageViewEvent: 3, "signup": 1, "purchase": 1}},
+		{"first hour", start, start.Add(time.Hour), map[string]int{PageViewEvent: 1, "signup": 1}},
+		{"end excluded", start.Add(time.Hour), start.Add(2 * time.Hour), map[string]int{PageViewEvent: 2}},
+		{"start included", start.Add(2 * time.Hour), start.Add(2*time.Hour + 1), map[string]int{"purchase": 1}},
+		{"before", start.Add(-time.Hour), start, map[string]int{}},
+		{"empty range", start.Add(time.Hour), start, map[string]int{}},
+	} {
+		if got := a.Report(test.start, test.end); !maps.Equal(got, test.want) {
+			t.Errorf("%s: Report = %v, want %v", test.name, got, test.want)
+		}
+	}
+	if e := a.events[3]; e.UserID != 1 || e.Name != PageViewEvent || e.Data["page"] != "/checkout" || !e.Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("PageView recorded %+v", e)
+	}
+}
@@ -0,0 +1,136 @@
+package godobject
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// Session is a logged-in user's session. Its access token is valid until
+// ExpiresAt and its refresh token until RefreshExpiresAt.
+type Session struct {
+	ID               string
+	UserID           int
+	ExpiresAt        time.Time
+	RefreshExpiresAt time.Time
+}
+
+// Tokens are the credentials a login hands out: a short-lived access
+// token and a refresh token to get the next one.
+type Tokens struct {
+	Access  string
+	Refresh string
+}
+
+// AuthService logs users in and checks their tokens.
+type AuthService interface {
+	Login(username, password string) (Tokens, error)
+	// Validate returns the ID of the user an access token belongs to.
+	Validate(accessToken string) (int, error)
+	// Refresh ends the refresh token's access token and issues new tokens
+	// for the same session. An expired refresh token ends the session.
+	Refresh(refreshToken string) (Tokens, error)
+	// Logout ends every session of the user.
+	Logout(userID int) error
+}
+
+// MemoryAuth is an AuthService that keeps sessions in memory.
+type MemoryAuth struct {
+	users      UserService
+	ttl        time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+
+	mu            sync.Mutex
+	sessions      map[string]*Session
+	authTokens    map[string]string // access token -> session ID
+	refreshTokens map[string]string // refresh token -> session ID
+}
+
+// NewMemoryAuth returns an AuthService checking passwords with users and
+// issuing access tokens valid for ttl and refresh tokens valid for
+// refreshTTL.
+func NewMemoryAuth(users UserService, ttl, refreshTTL time.Duration) *MemoryAuth {
+	return &MemoryAuth{
+		users:         users,
+		ttl:           ttl,
+		refreshTTL:    refreshTTL,
+		now:           time.Now,
+		sessions:      make(map[string]*Session),
+		authTokens:    make(map[string]string),
+		refreshTokens: make(map[string]string),
+	}
+}
+
+func (a *MemoryAuth) Login(username, password string) (Tokens, error) {
+	u, err := a.users.Verify(username, password)
+	if err != nil {
+		return Tokens{}, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := &Session{ID: rand.Text(), UserID: u.ID}
+	a.sessions[s.ID] = s
+	return a.issue(s), nil
+}
+
+func (a *MemoryAuth) Validate(accessToken string) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := a.sessions[a.authTokens[accessToken]]
+	if s == nil || !a.now().Before(s.ExpiresAt) {
+		return 0, ErrBadCredentials
+	}
+	return s.UserID, nil
+}
+
+func (a *MemoryAuth) Refresh(refreshToken string) (Tokens, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := a.sessions[a.refreshTokens[refreshToken]]
+	if s == nil {
+		return Tokens{}, ErrBadCredentials
+	}
+	a.revoke(s.ID)
+	if !a.now().Before(s.RefreshExpiresAt) {
+		delete(a.sessions, s.ID)
+		return Tokens{}, ErrBadCredentials
+	}
+	return a.issue(s), nil
+}
+
+func (a *MemoryAuth) Logout(userID int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id, s := range a.sessions {
+		if s.UserID != userID {
+			continue
+		}
+		delete(a.sessions, id)
+		a.revoke(id)
+	}
+	return nil
+}
+
+// revoke deletes the tokens of the session with the given ID. a.mu must be
+// held.
+func (a *MemoryAuth) revoke(sessionID string) {
+	for _, tokens := range []map[string]string{a.authTokens, a.refreshTokens} {
+		for t, id := range tokens {
+			if id == sessionID {
+				delete(tokens, t)
+			}
+		}
+	}
+}
+
+// issue extends s and gives it a new pair of tokens. a.mu must be held.
+func (a *MemoryAuth) issue(s *Session) Tokens {
+	now := a.now()
+	s.ExpiresAt = now.Add(a.ttl)
+	s.RefreshExpiresAt = now.Add(a.refreshTTL)
+	t := Tokens{Access: rand.Text(), Refresh: rand.Text()}
+	a.authTokens[t.Access] = s.ID
+	a.refreshTokens[t.Refresh] = s.ID
+	return t
+}
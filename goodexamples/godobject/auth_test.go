@@ -0,0 +1,156 @@
+package godobject
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestAuth returns a MemoryAuth for users ann and bob whose clock is
+// *now, with access tokens valid for a minute and refresh tokens for an
+// hour.
+func newTestAuth(t *testing.T, now *time.Time) *MemoryAuth {
+	t.Helper()
+	users := NewMemoryUsers()
+	for _, name := range []string{"ann", "bob"} {
+		if _, err := users.Create(name, name+"@example.com", name+" long enough"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := NewMemoryAuth(users, time.Minute, time.Hour)
+	a.now = func() time.Time { return *now }
+	return a
+}
+
+func TestMemoryAuthLogin(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAuth(t, &now)
+	for _, test := range []struct {
+		username, phrase string
+		wantID           int
+		err              error
+	}{
+		{"ann", "ann long enough", 1, nil},
+		{"bob", "bob long enough", 2, nil},
+		{"ann", "bob long enough", 0, ErrBadCredentials},
+		{"carol", "carol long enough", 0, ErrBadCredentials},
+	} {
+		tokens, err := a.Login(test.username, test.phrase)
+		if !errors.Is(err, test.err) {
+			t.Errorf("Login(%q, %q) = %v, want %v", test.username, test.phrase, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if id, err := a.Validate(tokens.Access); err != nil || id != test.wantID {
+			t.Errorf("Validate of %s's access token = %d, %v; want %d", test.username, id, err, test.wantID)
+		}
+		if _, err := a.Validate(tokens.Refresh); !errors.Is(err, ErrBadCredentials) {
+			t.Errorf("Validate of %s's refresh token = %v, want %v", test.username, err, ErrBadCredentials)
+		}
+	}
+}
+
+// TestMemoryAuthExpiry moves the clock past each token's lifetime.
+func TestMemoryAuthExpiry(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name     string
+		after    time.Duration
+		validate error // of the access token
+		refresh  error
+	}{
+		{"fresh", 0, nil, nil},
+		{"access nearly expired", time.Minute - time.Nanosecond, nil, nil},
+		{"access expired", time.Minute, ErrBadCredentials, nil},
+		{"refresh nearly expired", time.Hour - time.Nanosecond, ErrBadCredentials, nil},
+		{"refresh expired", time.Hour, ErrBadCredentials, ErrBadCredentials},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			now := start
+			a := newTestAuth(t, &now)
+			tokens, err := a.Login("ann", "ann long enough")
+			if err != nil {
+				t.Fatal(err)
+			}
+			now = start.Add(test.after)
+			if _, err := a.Validate(tokens.Access); !errors.Is(err, test.validate) {
+				t.Errorf("Validate = %v, want %v", err, test.validate)
+			}
+			next, err := a.Refresh(tokens.Refresh)
+			if !errors.Is(err, test.refresh) {
+				t.Fatalf("Refresh = %v, want %v", err, test.refresh)
+			}
+			if err != nil {
+				if len(a.sessions) != 0 {
+					t.Errorf("an expired refresh token left %d sessions", len(a.sessions))
+				}
+				return
+			}
+			// A refresh starts both lifetimes again.
+			now = now.Add(time.Minute - time.Nanosecond)
+			if id, err := a.Validate(next.Access); err != nil || id != 1 {
+				t.Errorf("Validate of the refreshed access token = %d, %v; want 1", id, err)
+			}
+		})
+	}
+}
+
+// TestMemoryAuthRefreshOnce checks that a refresh revokes the tokens it
+// replaces, so a stolen refresh token cannot be replayed.
+func TestMemoryAuthRefreshOnce(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAuth(t, &now)
+	first, err := a.Login("ann", "ann long enough")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := a.Refresh(first.Refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Refresh(first.Refresh); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("reusing a refresh token = %v, want %v", err, ErrBadCredentials)
+	}
+	if _, err := a.Validate(first.Access); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("Validate of the replaced access token = %v, want %v", err, ErrBadCredentials)
+	}
+	if id, err := a.Validate(second.Access); err != nil || id != 1 {
+		t.Errorf("Validate of the new access token = %d, %v; want 1", id, err)
+	}
+	if _, err := a.Refresh(second.Refresh); err != nil {
+		t.Errorf("Refresh with the new refresh token: %v", err)
+	}
+}
+
+func TestMemoryAuthLogout(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAuth(t, &now)
+	var ann []Tokens
+	for range 2 {
+		tokens, err := a.Login("ann", "ann long enough")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ann = append(ann, tokens)
+	}
+	bob, err := a.Login("bob", "bob long enough")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Logout(1); err != nil {
+		t.Fatal(err)
+	}
+	for i, tokens := range ann {
+		if _, err := a.Validate(tokens.Access); !errors.Is(err, ErrBadCredentials) {
+			t.Errorf("Validate of ann's session %d after Logout = %v, want %v", i, err, ErrBadCredentials)
+		}
+		if _, err := a.Refresh(tokens.Refresh); !errors.Is(err, ErrBadCredentials) {
+			t.Errorf("Refresh of ann's session %d after Logout = %v, want %v", i, err, ErrBadCredentials)
+		}
+	}
+	if id, err := a.Validate(bob.Access); err != nil || id != 2 {
+		t.Errorf("Validate of bob's token after ann's Logout = %d, %v; want 2", id, err)
+	}
+}
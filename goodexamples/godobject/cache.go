@@ -0,0 +1,53 @@
+package godobject
+
+import (
+	"sync"
+	"time"
+)
+
+// CacheService holds values for a while.
+type CacheService interface {
+	Set(key string, value any, ttl time.Duration)
+	// Get returns the value stored under key, unless it has expired.
+	Get(key string) (any, bool)
+	Delete(key string)
+	Clear()
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// MemoryCache is a CacheService in a sync.Map. Expired entries are
+// dropped when they are next read.
+type MemoryCache struct {
+	now     func() time.Time
+	entries sync.Map // string -> cacheEntry
+}
+
+// NewMemoryCache returns an empty cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{now: time.Now}
+}
+
+func (c *MemoryCache) Set(key string, value any, ttl time.Duration) {
+	c.entries.Store(key, cacheEntry{value, c.now().Add(ttl)})
+}
+
+func (c *MemoryCache) Get(key string) (any, bool) {
+	v, ok := c.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	e := v.(cacheEntry)
+	if !c.now().Before(e.expires) {
+		c.entries.CompareAndDelete(key, v)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *MemoryCache) Delete(key string) { c.entries.Delete(key) }
+
+func (c *MemoryCache) Clear() { c.entries.Clear() }
@@ -0,0 +1,56 @@
+package godobject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+	c.Set("a", 1, time.Minute)
+	c.Set("b", "two", time.Hour)
+	c.Set("c", 3.0, time.Hour)
+	for _, test := range []struct {
+		name  string
+		do    func()
+		key   string
+		want  any
+		found bool
+	}{
+		{"set", nil, "a", 1, true},
+		{"never set", nil, "z", nil, false},
+		{"nearly expired", func() { now = now.Add(time.Minute - time.Nanosecond) }, "a", 1, true},
+		{"expired", func() { now = now.Add(time.Nanosecond) }, "a", nil, false},
+		{"longer ttl", nil, "b", "two", true},
+		{"reset", func() { c.Set("a", 4, time.Minute) }, "a", 4, true},
+		{"deleted", func() { c.Delete("b") }, "b", nil, false},
+		{"kept", nil, "c", 3.0, true},
+		{"cleared", c.Clear, "c", nil, false},
+	} {
+		if test.do != nil {
+			test.do()
+		}
+		if got, found := c.Get(test.key); got != test.want || found != test.found {
+			t.Errorf("%s: Get(%q) = %v, %t; want %v, %t", test.name, test.key, got, found, test.want, test.found)
+		}
+	}
+	if _, ok := c.entries.Load("a"); ok {
+		t.Error("Clear left entries behind")
+	}
+}
+
+// TestMemoryCacheDropsExpired checks that reading an expired entry removes
+// it.
+func TestMemoryCacheDropsExpired(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+	c.Set("a", 1, time.Second)
+	now = now.Add(time.Second)
+	c.Get("a")
+	if _, ok := c.entries.Load("a"); ok {
+		t.Error("expired entry still stored after Get")
+	}
+}
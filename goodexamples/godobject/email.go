@@ -0,0 +1,83 @@
+package godobject
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Email is a message to send.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailService sends e-mail.
+type EmailService interface {
+	Send(e Email) error
+	// SendTemplate sends the named template, executed with data.
+	SendTemplate(to, name string, data any) error
+}
+
+// An EmailTemplate is the subject and body of a kind of e-mail, in
+// text/template syntax.
+type EmailTemplate struct {
+	Subject, Body string
+}
+
+// Outbox is an EmailService that keeps messages in memory instead of
+// sending them, for development and demos.
+type Outbox struct {
+	templates *template.Template
+
+	mu   sync.Mutex
+	sent []Email
+}
+
+// NewOutbox returns an empty outbox that knows the given templates.
+func NewOutbox(templates map[string]EmailTemplate) (*Outbox, error) {
+	t := template.New("")
+	for name, et := range templates {
+		if _, err := t.New(name + ".subject").Parse(et.Subject); err != nil {
+			return nil, err
+		}
+		if _, err := t.New(name + ".body").Parse(et.Body); err != nil {
+			return nil, err
+		}
+	}
+	return &Outbox{templates: t}, nil
+}
+
+func (o *Outbox) Send(e Email) error {
+	if !strings.Contains(e.To, "@") {
+		return fmt.Errorf("recipient %q: %w", e.To, ErrInvalid)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sent = append(o.sent, e)
+	return nil
+}
+
+func (o *Outbox) SendTemplate(to, name string, data any) error {
+	var subject, body bytes.Buffer
+	if o.templates.Lookup(name+".subject") == nil {
+		return notFound("template", name)
+	}
+	if err := o.templates.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return err
+	}
+	if err := o.templates.ExecuteTemplate(&body, name+".body", data); err != nil {
+		return err
+	}
+	return o.Send(Email{To: to, Subject: subject.String(), Body: body.String()})
+}
+
+// Sent returns the messages sent so far.
+func (o *Outbox) Sent() []Email {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]Email(nil), o.sent...)
+}
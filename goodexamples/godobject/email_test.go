@@ -0,0 +1,65 @@
+package godobject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOutbox(t *testing.T) {
+	if _, err := NewOutbox(map[string]EmailTemplate{"bad": {Subject: "{{.Name"}}); err == nil {
+		t.Error("NewOutbox accepted a template that does not parse")
+	}
+	o, err := NewOutbox(map[string]EmailTemplate{
+		"welcome": {Subject: "Welcome, {{.Username}}", Body: "Hello {{.Username}}."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name string
+		send func() error
+		want *Email // sent, if not nil
+		err  error
+	}{
+		{
+			name: "send",
+			send: func() error { return o.Send(Email{To: "ann@example.com", Subject: "Hi", Body: "Hello."}) },
+			want: &Email{To: "ann@example.com", Subject: "Hi", Body: "Hello."},
+		},
+		{
+			name: "send to no address",
+			send: func() error { return o.Send(Email{To: "ann", Subject: "Hi"}) },
+			err:  ErrInvalid,
+		},
+		{
+			name: "template",
+			send: func() error { return o.SendTemplate("bob@example.com", "welcome", User{Username: "bob"}) },
+			want: &Email{To: "bob@example.com", Subject: "Welcome, bob", Body: "Hello bob."},
+		},
+		{
+			name: "unknown template",
+			send: func() error { return o.SendTemplate("bob@example.com", "goodbye", nil) },
+			err:  ErrNotFound,
+		},
+		{
+			name: "template to no address",
+			send: func() error { return o.SendTemplate("bob", "welcome", User{Username: "bob"}) },
+			err:  ErrInvalid,
+		},
+	} {
+		before := len(o.Sent())
+		if err := test.send(); !errors.Is(err, test.err) {
+			t.Errorf("%s: %v, want %v", test.name, err, test.err)
+		}
+		sent := o.Sent()[before:]
+		switch {
+		case test.want == nil && len(sent) != 0:
+			t.Errorf("%s sent %+v", test.name, sent)
+		case test.want != nil && (len(sent) != 1 || sent[0] != *test.want):
+			t.Errorf("%s sent %+v, want %+v", test.name, sent, *test.want)
+		}
+	}
+	if err := o.SendTemplate("bob@example.com", "welcome", 42); err == nil {
+		t.Error("SendTemplate with data lacking Username succeeded")
+	}
+}
@@ -0,0 +1,65 @@
+package godobject
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net/url"
+)
+
+// FileStorage stores uploaded files.
+type FileStorage interface {
+	// Upload stores data for its owner and returns the file's ID.
+	Upload(ownerID int, data []byte) (string, error)
+	Download(id string) ([]byte, error)
+	Delete(id string) error
+	// URL returns the address the file is served from.
+	URL(id string) (string, error)
+}
+
+type storedFile struct {
+	owner int
+	data  []byte
+}
+
+// MemoryFiles is a FileStorage in memory, served under a base URL.
+type MemoryFiles struct {
+	base    *url.URL
+	maxSize int
+	files   *table[string, storedFile]
+}
+
+// NewMemoryFiles returns an empty store accepting files of up to maxSize
+// bytes and serving them under base.
+func NewMemoryFiles(base string, maxSize int) (*MemoryFiles, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryFiles{base: u, maxSize: maxSize, files: newTable[string, storedFile]("file")}, nil
+}
+
+func (s *MemoryFiles) Upload(ownerID int, data []byte) (string, error) {
+	if len(data) > s.maxSize {
+		return "", fmt.Errorf("file of %d bytes exceeds %d: %w", len(data), s.maxSize, ErrInvalid)
+	}
+	id := rand.Text()
+	s.files.put(id, storedFile{owner: ownerID, data: bytes.Clone(data)})
+	return id, nil
+}
+
+func (s *MemoryFiles) Download(id string) ([]byte, error) {
+	f, err := s.files.get(id)
+	return bytes.Clone(f.data), err
+}
+
+func (s *MemoryFiles) Delete(id string) error {
+	return s.files.remove(id)
+}
+
+func (s *MemoryFiles) URL(id string) (string, error) {
+	if _, err := s.files.get(id); err != nil {
+		return "", err
+	}
+	return s.base.JoinPath(id).String(), nil
+}
@@ -0,0 +1,60 @@
+package godobject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryFiles(t *testing.T) {
+	if _, err := NewMemoryFiles("://files", 10); err == nil {
+		t.Error("NewMemoryFiles accepted a base that is not a URL")
+	}
+	s, err := NewMemoryFiles("https://files.example.com/uploads/", 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("contents")
+	id, err := s.Upload(1, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[0] = 'C'
+	if _, err := s.Upload(1, []byte("too large")); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Upload of 9 bytes = %v, want %v", err, ErrInvalid)
+	}
+
+	for _, test := range []struct {
+		name    string
+		id      string
+		want    string // downloaded
+		wantURL string
+		err     error
+	}{
+		{"uploaded", id, "contents", "https://files.example.com/uploads/" + id, nil},
+		{"unknown", "missing", "", "", ErrNotFound},
+	} {
+		got, err := s.Download(test.id)
+		if !errors.Is(err, test.err) || string(got) != test.want {
+			t.Errorf("%s: Download = %q, %v; want %q, %v", test.name, got, err, test.want, test.err)
+		}
+		u, err := s.URL(test.id)
+		if !errors.Is(err, test.err) || u != test.wantURL {
+			t.Errorf("%s: URL = %q, %v; want %q, %v", test.name, u, err, test.wantURL, test.err)
+		}
+	}
+
+	got, _ := s.Download(id)
+	got[0] = 'C'
+	if again, _ := s.Download(id); string(again) != "contents" {
+		t.Errorf("changing a download changed the stored file to %q", again)
+	}
+	if err := s.Delete(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := s.Download(id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Download after Delete = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,8 @@
+package godobject
+
+// FeatureFlags says which features are switched on for a user; "" is an
+// anonymous user. A *flags.Evaluator from goodexamples/hardcoding/flags is
+// one.
+type FeatureFlags interface {
+	Enabled(name, user string) bool
+}
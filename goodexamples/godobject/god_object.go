@@ -0,0 +1,199 @@
+// Package godobject is the God Object example split up. Each
+// responsibility ApplicationManager carried is a service of its own behind
+// a small interface - UserService, AuthService, ProductService,
+// InventoryService, OrderService, PaymentService, EmailService,
+// CacheService, FileStorage, NotificationService, AnalyticsService and
+// FeatureFlags - with a working in-memory implementation holding only the
+// state it needs, or, for FeatureFlags, the evaluator of
+// goodexamples/hardcoding/flags. Services that need one another take the
+// interface, so OrderService can be given any ProductService, and an App
+// wires one implementation of each together.
+package godobject
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+)
+
+// responsibilities is the number of jobs the original ApplicationManager
+// held in one struct.
+const responsibilities = 15
+
+// Errors the services return, wrapped with the detail of what failed.
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrExists         = errors.New("already exists")
+	ErrInvalid        = errors.New("invalid")
+	ErrBadCredentials = errors.New("bad credentials")
+	ErrOutOfStock     = errors.New("out of stock")
+)
+
+func notFound(kind string, id any) error {
+	return fmt.Errorf("%s %v: %w", kind, id, ErrNotFound)
+}
+
+// A Level is the severity of a log message.
+type Level string
+
+// Log levels.
+const (
+	Info  Level = "INFO"
+	Error Level = "ERROR"
+)
+
+// Logger keeps messages by level. It is safe for concurrent use.
+type Logger struct {
+	mu       sync.Mutex
+	messages map[Level][]string
+}
+
+// Log records a message at level.
+func (l *Logger) Log(level Level, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.messages == nil {
+		l.messages = make(map[Level][]string)
+	}
+	l.messages[level] = append(l.messages[level], fmt.Sprintf("[%s] %s", level, message))
+}
+
+// Messages returns the messages logged at level.
+func (l *Logger) Messages(level Level) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.messages[level])
+}
+
+// Clear discards the messages logged at level.
+func (l *Logger) Clear(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.messages, level)
+}
+
+// Config is the application's settings.
+type Config map[string]any
+
+// App holds one implementation of each service.
+type App struct {
+	Log           *Logger
+	Config        Config
+	Users         UserService
+	Auth          AuthService
+	Products      ProductService
+	Inventory     InventoryService
+	Orders        OrderService
+	Payments      PaymentService
+	Email         EmailService
+	Cache         CacheService
+	Files         FileStorage
+	Notifications NotificationService
+	Analytics     AnalyticsService
+	Features      FeatureFlags
+}
+
+// NewApp returns an App of in-memory services.
+func NewApp() (*App, error) {
+	outbox, err := NewOutbox(map[string]EmailTemplate{
+		"welcome": {Subject: "Welcome, {{.Username}}", Body: "Thanks for signing up, {{.Username}}."},
+		"order":   {Subject: "Order {{.ID}} confirmed", Body: "Your order of {{len .Items}} items comes to ${{printf \"%.2f\" .Total}}."},
+	})
+	if err != nil {
+		return nil, err
+	}
+	files, err := NewMemoryFiles("https://files.example.com/", 10<<20)
+	if err != nil {
+		return nil, err
+	}
+	users := NewMemoryUsers()
+	products := NewMemoryProducts()
+	inventory := NewMemoryInventory()
+	features, err := flags.New([]flags.Flag{{Name: "new_checkout", Enabled: true}}, flags.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &App{
+		Log:           new(Logger),
+		Config:        make(Config),
+		Users:         users,
+		Auth:          NewMemoryAuth(users, 15*time.Minute, 24*time.Hour),
+		Products:      products,
+		Inventory:     inventory,
+		Orders:        NewMemoryOrders(products, inventory),
+		Payments:      NewMemoryPayments(),
+		Email:         outbox,
+		Cache:         NewMemoryCache(),
+		Files:         files,
+		Notifications: NewMemoryNotifications(),
+		Analytics:     NewMemoryAnalytics(),
+		Features:      features,
+	}, nil
+}
+
+// Checkout runs the flow that used to go through ApplicationManager: sign
+// a user up, log them in, stock and order a product, pay, and tell them.
+// Each step is one service's job.
+func (app *App) Checkout() error {
+	u, err := app.Users.Create("john", "john@example.com", "correct horse battery")
+	if err != nil {
+		return err
+	}
+	if err = app.Email.SendTemplate(u.Email, "welcome", u); err != nil {
+		return err
+	}
+	tokens, err := app.Auth.Login("john", "correct horse battery")
+	if err != nil {
+		return err
+	}
+	userID, err := app.Auth.Validate(tokens.Access)
+	if err != nil {
+		return err
+	}
+	app.Analytics.PageView(userID, "/checkout")
+
+	p, err := app.Products.Add(Product{Name: "Widget", Price: 9.99})
+	if err != nil {
+		return err
+	}
+	if err = app.Inventory.Adjust(p.ID, 10); err != nil {
+		return err
+	}
+	o, err := app.Orders.Create(userID, []int{p.ID, p.ID})
+	if err != nil {
+		return err
+	}
+	if _, err = app.Payments.Charge(o, "4242 4242 4242 4242"); err != nil {
+		return err
+	}
+	if err = app.Orders.MarkPaid(o.ID); err != nil {
+		return err
+	}
+	if err = app.Email.SendTemplate(u.Email, "order", o); err != nil {
+		return err
+	}
+	if _, err = app.Notifications.Notify(userID, "Your order is on its way"); err != nil {
+		return err
+	}
+	app.Log.Log(Info, fmt.Sprintf("order %d paid", o.ID))
+	return app.Auth.Logout(userID)
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w. It
+// runs Checkout, reporting only if it fails, and prints the original's
+// line, so that the two can be diffed.
+func Run(w io.Writer) {
+	app, err := NewApp()
+	if err == nil {
+		err = app.Checkout()
+	}
+	if err != nil {
+		fmt.Fprintln(w, "checkout:", err)
+	}
+	fmt.Fprintf(w, "God object created with %d responsibilities\n", responsibilities)
+}
@@ -0,0 +1,49 @@
+package godobject
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var l Logger
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			for j := range 100 {
+				l.Log(Info, fmt.Sprint(i, j))
+				l.Messages(Info)
+			}
+		})
+	}
+	wg.Go(func() { l.Log(Error, "failed") })
+	wg.Wait()
+
+	if n := len(l.Messages(Info)); n != 1000 {
+		t.Errorf("%d info messages, want 1000", n)
+	}
+	if got := l.Messages(Error); len(got) != 1 || got[0] != "[ERROR] failed" {
+		t.Errorf("error messages = %q, want [ERROR] failed", got)
+	}
+	l.Clear(Info)
+	if got := l.Messages(Info); len(got) != 0 {
+		t.Errorf("info messages after Clear = %q", got)
+	}
+	if got := l.Messages(Error); len(got) != 1 {
+		t.Errorf("Clear(Info) cleared errors: %q", got)
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Checkout(); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.Log.Messages(Info); len(got) != 1 || got[0] != "[INFO] order 1 paid" {
+		t.Errorf("log = %q, want [INFO] order 1 paid", got)
+	}
+}
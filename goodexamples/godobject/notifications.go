@@ -0,0 +1,74 @@
+package godobject
+
+import "slices"
+
+// Notification is a message for a user.
+type Notification struct {
+	ID      int
+	UserID  int
+	Message string
+	Read    bool
+}
+
+// NotificationPrefs are the channels a user wants notifications on.
+type NotificationPrefs struct {
+	Email bool
+	SMS   bool
+	Push  bool
+}
+
+// NotificationService notifies users.
+type NotificationService interface {
+	// Notify records a notification for the user, unless they have turned
+	// every channel off, and returns its ID, or 0 if it was not sent.
+	Notify(userID int, message string) (int, error)
+	MarkRead(id int) error
+	Unread(userID int) []Notification
+	SetPrefs(userID int, prefs NotificationPrefs)
+}
+
+// MemoryNotifications is a NotificationService in memory. Users who have
+// not set preferences get every channel.
+type MemoryNotifications struct {
+	notifications *table[int, Notification]
+	nextID        int // guarded by notifications.mu
+	prefs         *table[int, NotificationPrefs]
+}
+
+// NewMemoryNotifications returns a service with no notifications.
+func NewMemoryNotifications() *MemoryNotifications {
+	return &MemoryNotifications{
+		notifications: newTable[int, Notification]("notification"),
+		nextID:        1,
+		prefs:         newTable[int, NotificationPrefs]("preferences"),
+	}
+}
+
+func (s *MemoryNotifications) Notify(userID int, message string) (int, error) {
+	if p, err := s.prefs.get(userID); err == nil && p == (NotificationPrefs{}) {
+		return 0, nil
+	}
+	n, err := s.notifications.insert(func() (int, Notification) {
+		n := Notification{ID: s.nextID, UserID: userID, Message: message}
+		s.nextID++
+		return n.ID, n
+	}, nil)
+	return n.ID, err
+}
+
+func (s *MemoryNotifications) MarkRead(id int) error {
+	return s.notifications.update(id, func(n *Notification) error {
+		n.Read = true
+		return nil
+	})
+}
+
+func (s *MemoryNotifications) Unread(userID int) []Notification {
+	out := s.notifications.find(func(n Notification) bool { return n.UserID == userID && !n.Read })
+	slices.SortFunc(out, func(a, b Notification) int { return a.ID - b.ID })
+	return out
+}
+
+func (s *MemoryNotifications) SetPrefs(userID int, prefs NotificationPrefs) {
+	s.prefs.put(userID, prefs)
+}
@@ -0,0 +1,58 @@
+package godobject
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMemoryNotifications(t *testing.T) {
+	s := NewMemoryNotifications()
+	s.SetPrefs(2, NotificationPrefs{})
+	s.SetPrefs(3, NotificationPrefs{SMS: true})
+	for _, test := range []struct {
+		name   string
+		userID int
+		wantID int
+	}{
+		{"no preferences", 1, 1},
+		{"every channel off", 2, 0},
+		{"one channel on", 3, 2},
+		{"no preferences again", 1, 3},
+	} {
+		if id, err := s.Notify(test.userID, test.name); err != nil || id != test.wantID {
+			t.Errorf("%s: Notify = %d, %v; want %d", test.name, id, err, test.wantID)
+		}
+	}
+
+	for _, test := range []struct {
+		name   string
+		do     func() error
+		err    error
+		unread [3][]int // IDs unread by users 1 to 3 afterwards
+	}{
+		{"nothing", func() error { return nil }, nil, [3][]int{{1, 3}, nil, {2}}},
+		{"read 3", func() error { return s.MarkRead(3) }, nil, [3][]int{{1}, nil, {2}}},
+		{"read 3 again", func() error { return s.MarkRead(3) }, nil, [3][]int{{1}, nil, {2}}},
+		{"read 4", func() error { return s.MarkRead(4) }, ErrNotFound, [3][]int{{1}, nil, {2}}},
+		{"read 2", func() error { return s.MarkRead(2) }, nil, [3][]int{{1}, nil, nil}},
+	} {
+		if err := test.do(); !errors.Is(err, test.err) {
+			t.Errorf("%s: %v, want %v", test.name, err, test.err)
+		}
+		for i, want := range test.unread {
+			var got []int
+			for _, n := range s.Unread(i + 1) {
+				got = append(got, n.ID)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("after %s, user %d has %v unread, want %v", test.name, i+1, got, want)
+			}
+		}
+	}
+
+	s.SetPrefs(2, NotificationPrefs{Push: true})
+	if id, err := s.Notify(2, "turned on"); err != nil || id != 4 {
+		t.Errorf("Notify after turning a channel on = %d, %v; want 4", id, err)
+	}
+}
@@ -0,0 +1,101 @@
+package godobject
+
+import "fmt"
+
+// Order statuses.
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderCancelled = "cancelled"
+)
+
+// Order is a user's order for one of each of Items, by product ID.
+type Order struct {
+	ID     int
+	UserID int
+	Items  []int
+	Total  float64
+	Status string
+}
+
+// OrderService takes and tracks orders.
+type OrderService interface {
+	// Create prices the items and takes them from stock.
+	Create(userID int, items []int) (Order, error)
+	Get(id int) (Order, error)
+	MarkPaid(id int) error
+	// Cancel returns a pending order's items to stock.
+	Cancel(id int) error
+}
+
+// MemoryOrders is an OrderService that keeps orders in memory.
+type MemoryOrders struct {
+	products  ProductService
+	inventory InventoryService
+	orders    *table[int, Order]
+	nextID    int // guarded by orders.mu
+}
+
+// NewMemoryOrders returns an OrderService pricing from products and taking
+// stock from inventory.
+func NewMemoryOrders(products ProductService, inventory InventoryService) *MemoryOrders {
+	return &MemoryOrders{products: products, inventory: inventory, orders: newTable[int, Order]("order"), nextID: 1}
+}
+
+func (s *MemoryOrders) Create(userID int, items []int) (Order, error) {
+	if len(items) == 0 {
+		return Order{}, fmt.Errorf("order with no items: %w", ErrInvalid)
+	}
+	total := 0.0
+	for _, id := range items {
+		p, err := s.products.Get(id)
+		if err != nil {
+			return Order{}, err
+		}
+		total += p.Price
+	}
+	if err := s.inventory.Reserve(items); err != nil {
+		return Order{}, err
+	}
+	return s.orders.insert(func() (int, Order) {
+		o := Order{ID: s.nextID, UserID: userID, Items: items, Total: total, Status: OrderPending}
+		s.nextID++
+		return o.ID, o
+	}, nil)
+}
+
+func (s *MemoryOrders) Get(id int) (Order, error) {
+	return s.orders.get(id)
+}
+
+func (s *MemoryOrders) MarkPaid(id int) error {
+	return s.orders.update(id, transition(OrderPaid))
+}
+
+func (s *MemoryOrders) Cancel(id int) error {
+	var items []int
+	err := s.orders.update(id, func(o *Order) error {
+		items = o.Items
+		return transition(OrderCancelled)(o)
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := s.inventory.Adjust(item, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transition returns an update moving a pending order to status.
+func transition(status string) func(*Order) error {
+	return func(o *Order) error {
+		if o.Status != OrderPending {
+			return fmt.Errorf("order %d is %s: %w", o.ID, o.Status, ErrInvalid)
+		}
+		o.Status = status
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package godobject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryOrders(t *testing.T) {
+	products := NewMemoryProducts()
+	inventory := NewMemoryInventory()
+	for _, p := range []Product{{Name: "Widget", Price: 2.5}, {Name: "Gadget", Price: 10}} {
+		p, err := products.Add(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := inventory.Adjust(p.ID, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewMemoryOrders(products, inventory)
+
+	for _, test := range []struct {
+		items     []int
+		want      Order
+		err       error
+		remaining [2]int // stock of the widget and the gadget afterwards
+	}{
+		{[]int{1, 2}, Order{ID: 1, UserID: 7, Items: []int{1, 2}, Total: 12.5, Status: OrderPending}, nil, [2]int{1, 1}},
+		{[]int{1, 1}, Order{}, ErrOutOfStock, [2]int{1, 1}},
+		{[]int{1, 3}, Order{}, ErrNotFound, [2]int{1, 1}},
+		{nil, Order{}, ErrInvalid, [2]int{1, 1}},
+		{[]int{1}, Order{ID: 2, UserID: 7, Items: []int{1}, Total: 2.5, Status: OrderPending}, nil, [2]int{0, 1}},
+	} {
+		o, err := s.Create(7, test.items)
+		if !errors.Is(err, test.err) || !reflect.DeepEqual(o, test.want) {
+			t.Errorf("Create(%v) = %+v, %v; want %+v, %v", test.items, o, err, test.want, test.err)
+		}
+		if got := [2]int{inventory.Stock(1), inventory.Stock(2)}; got != test.remaining {
+			t.Errorf("after Create(%v), stock is %v, want %v", test.items, got, test.remaining)
+		}
+	}
+
+	for _, test := range []struct {
+		name      string
+		do        func() error
+		err       error
+		status    [2]string // of orders 1 and 2 afterwards
+		remaining [2]int
+	}{
+		{"pay 1", func() error { return s.MarkPaid(1) }, nil, [2]string{OrderPaid, OrderPending}, [2]int{0, 1}},
+		{"cancel 1", func() error { return s.Cancel(1) }, ErrInvalid, [2]string{OrderPaid, OrderPending}, [2]int{0, 1}},
+		{"cancel 2", func() error { return s.Cancel(2) }, nil, [2]string{OrderPaid, OrderCancelled}, [2]int{1, 1}},
+		{"pay 2", func() error { return s.MarkPaid(2) }, ErrInvalid, [2]string{OrderPaid, OrderCancelled}, [2]int{1, 1}},
+		{"cancel 2 again", func() error { return s.Cancel(2) }, ErrInvalid, [2]string{OrderPaid, OrderCancelled}, [2]int{1, 1}},
+		{"pay 3", func() error { return s.MarkPaid(3) }, ErrNotFound, [2]string{OrderPaid, OrderCancelled}, [2]int{1, 1}},
+	} {
+		if err := test.do(); !errors.Is(err, test.err) {
+			t.Errorf("%s: %v, want %v", test.name, err, test.err)
+		}
+		for i, want := range test.status {
+			if o, err := s.Get(i + 1); err != nil || o.Status != want {
+				t.Errorf("after %s, order %d is %s (%v), want %s", test.name, i+1, o.Status, err, want)
+			}
+		}
+		if got := [2]int{inventory.Stock(1), inventory.Stock(2)}; got != test.remaining {
+			t.Errorf("after %s, stock is %v, want %v", test.name, got, test.remaining)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package godobject
+
+import (
+	"crypto/rand"
+	"fmt"
+	"slices"
+)
+
+// Transaction statuses.
+const (
+	TxCaptured = "captured"
+	TxRefunded = "refunded"
+)
+
+// Transaction is a payment taken for an order.
+type Transaction struct {
+	ID      string
+	OrderID int
+	UserID  int
+	Amount  float64
+	Status  string
+}
+
+// Refund is money returned for a transaction.
+type Refund struct {
+	TransactionID string
+	Amount        float64
+}
+
+// PaymentService takes and refunds payments.
+type PaymentService interface {
+	// Charge takes payment for an order by card.
+	Charge(order Order, cardNumber string) (Transaction, error)
+	Refund(transactionID string) (Refund, error)
+	History(userID int) []Transaction
+}
+
+// MemoryPayments is a PaymentService that records payments in memory
+// instead of calling a payment provider.
+type MemoryPayments struct {
+	transactions *table[string, Transaction]
+}
+
+// NewMemoryPayments returns a PaymentService with no payments.
+func NewMemoryPayments() *MemoryPayments {
+	return &MemoryPayments{transactions: newTable[string, Transaction]("transaction")}
+}
+
+func (s *MemoryPayments) Charge(order Order, cardNumber string) (Transaction, error) {
+	if !ValidCardNumber(cardNumber) {
+		return Transaction{}, fmt.Errorf("card number: %w", ErrInvalid)
+	}
+	t := Transaction{ID: rand.Text(), OrderID: order.ID, UserID: order.UserID, Amount: order.Total, Status: TxCaptured}
+	s.transactions.put(t.ID, t)
+	return t, nil
+}
+
+func (s *MemoryPayments) Refund(transactionID string) (Refund, error) {
+	var r Refund
+	err := s.transactions.update(transactionID, func(t *Transaction) error {
+		if t.Status != TxCaptured {
+			return fmt.Errorf("transaction %s is %s: %w", t.ID, t.Status, ErrInvalid)
+		}
+		t.Status = TxRefunded
+		r = Refund{TransactionID: t.ID, Amount: t.Amount}
+		return nil
+	})
+	return r, err
+}
+
+// History returns the user's transactions in order ID order.
+func (s *MemoryPayments) History(userID int) []Transaction {
+	out := s.transactions.find(func(t Transaction) bool { return t.UserID == userID })
+	slices.SortFunc(out, func(a, b Transaction) int { return a.OrderID - b.OrderID })
+	return out
+}
+
+// ValidCardNumber reports whether s is 12 to 19 digits passing the Luhn
+// check, ignoring spaces.
+func ValidCardNumber(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 12 && n <= 19 && sum%10 == 0
+}
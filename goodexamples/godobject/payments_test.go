@@ -0,0 +1,91 @@
+package godobject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidCardNumber(t *testing.T) {
+	for _, test := range []struct {
+		number string
+		want   bool
+	}{
+		{"4242 4242 4242 4242", true},
+		{"4111111111111111", true},
+		{"5555 5555 5555 4444", true},
+		{"378282246310005", true}, // 15 digits
+		{"4242 4242 4242 4241", false},
+		{"4242-4242-4242-4242", false},
+		{"0000 0000 000", false}, // 11 digits
+		{"", false},
+	} {
+		if got := ValidCardNumber(test.number); got != test.want {
+			t.Errorf("ValidCardNumber(%q) = %t, want %t", test.number, got, test.want)
+		}
+	}
+}
+
+func TestMemoryPayments(t *testing.T) {
+	s := NewMemoryPayments()
+	var ids []string
+	for _, test := range []struct {
+		order Order
+		card  string
+		err   error
+	}{
+		{Order{ID: 2, UserID: 1, Total: 20}, "4242 4242 4242 4242", nil},
+		{Order{ID: 1, UserID: 1, Total: 10}, "4111111111111111", nil},
+		{Order{ID: 3, UserID: 2, Total: 30}, "4242 4242 4242 4242", nil},
+		{Order{ID: 4, UserID: 1, Total: 40}, "4242 4242 4242 4241", ErrInvalid},
+	} {
+		tx, err := s.Charge(test.order, test.card)
+		if !errors.Is(err, test.err) {
+			t.Errorf("Charge of order %d = %v, want %v", test.order.ID, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		if tx.ID == "" || tx.OrderID != test.order.ID || tx.UserID != test.order.UserID || tx.Amount != test.order.Total || tx.Status != TxCaptured {
+			t.Errorf("Charge of order %d = %+v", test.order.ID, tx)
+		}
+		ids = append(ids, tx.ID)
+	}
+
+	for _, test := range []struct {
+		id   string
+		want Refund
+		err  error
+	}{
+		{ids[0], Refund{TransactionID: ids[0], Amount: 20}, nil},
+		{ids[0], Refund{}, ErrInvalid},
+		{"unknown", Refund{}, ErrNotFound},
+	} {
+		r, err := s.Refund(test.id)
+		if !errors.Is(err, test.err) || r != test.want {
+			t.Errorf("Refund(%s) = %+v, %v; want %+v, %v", test.id, r, err, test.want, test.err)
+		}
+	}
+
+	for _, test := range []struct {
+		userID int
+		want   []Transaction
+	}{
+		{1, []Transaction{
+			{ID: ids[1], OrderID: 1, UserID: 1, Amount: 10, Status: TxCaptured},
+			{ID: ids[0], OrderID: 2, UserID: 1, Amount: 20, Status: TxRefunded},
+		}},
+		{2, []Transaction{{ID: ids[2], OrderID: 3, UserID: 2, Amount: 30, Status: TxCaptured}}},
+		{3, nil},
+	} {
+		got := s.History(test.userID)
+		if len(got) != len(test.want) {
+			t.Errorf("History(%d) = %+v, want %+v", test.userID, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("History(%d)[%d] = %+v, want %+v", test.userID, i, got[i], test.want[i])
+			}
+		}
+	}
+}
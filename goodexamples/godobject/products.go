@@ -0,0 +1,129 @@
+package godobject
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Product is something for sale.
+type Product struct {
+	ID    int
+	Name  string
+	Price float64
+}
+
+// ProductService manages the catalog.
+type ProductService interface {
+	// Add adds p to the catalog and returns it with its new ID.
+	Add(p Product) (Product, error)
+	Get(id int) (Product, error)
+	SetPrice(id int, price float64) error
+	Remove(id int) error
+	// Search returns the products whose names contain query, ignoring
+	// case, in ID order.
+	Search(query string) []Product
+}
+
+// MemoryProducts is a ProductService that keeps the catalog in memory.
+type MemoryProducts struct {
+	products *table[int, Product]
+	nextID   int // guarded by products.mu
+}
+
+// NewMemoryProducts returns an empty catalog.
+func NewMemoryProducts() *MemoryProducts {
+	return &MemoryProducts{products: newTable[int, Product]("product"), nextID: 1}
+}
+
+func (s *MemoryProducts) Add(p Product) (Product, error) {
+	if p.Name == "" || p.Price < 0 {
+		return Product{}, fmt.Errorf("product %q: %w", p.Name, ErrInvalid)
+	}
+	return s.products.insert(func() (int, Product) {
+		p.ID = s.nextID
+		s.nextID++
+		return p.ID, p
+	}, nil)
+}
+
+func (s *MemoryProducts) Get(id int) (Product, error) {
+	return s.products.get(id)
+}
+
+func (s *MemoryProducts) SetPrice(id int, price float64) error {
+	if price < 0 {
+		return fmt.Errorf("price %v: %w", price, ErrInvalid)
+	}
+	return s.products.update(id, func(p *Product) error {
+		p.Price = price
+		return nil
+	})
+}
+
+func (s *MemoryProducts) Remove(id int) error {
+	return s.products.remove(id)
+}
+
+func (s *MemoryProducts) Search(query string) []Product {
+	query = strings.ToLower(query)
+	out := s.products.find(func(p Product) bool {
+		return strings.Contains(strings.ToLower(p.Name), query)
+	})
+	slices.SortFunc(out, func(a, b Product) int { return a.ID - b.ID })
+	return out
+}
+
+// InventoryService tracks stock.
+type InventoryService interface {
+	Stock(productID int) int
+	// Adjust adds delta, which may be negative, to a product's stock. It
+	// fails with ErrOutOfStock rather than go below zero.
+	Adjust(productID, delta int) error
+	// Reserve takes one of each of the given products, or none of them if
+	// any is out of stock.
+	Reserve(productIDs []int) error
+}
+
+// MemoryInventory is an InventoryService that keeps counts in memory.
+type MemoryInventory struct {
+	stock *table[int, int]
+}
+
+// NewMemoryInventory returns an inventory with nothing in stock.
+func NewMemoryInventory() *MemoryInventory {
+	return &MemoryInventory{stock: newTable[int, int]("product")}
+}
+
+func (s *MemoryInventory) Stock(productID int) int {
+	n, _ := s.stock.get(productID)
+	return n
+}
+
+func (s *MemoryInventory) Adjust(productID, delta int) error {
+	return s.stock.tx(func(stock map[int]int) error {
+		if stock[productID]+delta < 0 {
+			return fmt.Errorf("product %d: %w", productID, ErrOutOfStock)
+		}
+		stock[productID] += delta
+		return nil
+	})
+}
+
+func (s *MemoryInventory) Reserve(productIDs []int) error {
+	want := make(map[int]int)
+	for _, id := range productIDs {
+		want[id]++
+	}
+	return s.stock.tx(func(stock map[int]int) error {
+		for id, n := range want {
+			if stock[id] < n {
+				return fmt.Errorf("product %d: %w", id, ErrOutOfStock)
+			}
+		}
+		for id, n := range want {
+			stock[id] -= n
+		}
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package godobject
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMemoryProducts(t *testing.T) {
+	s := NewMemoryProducts()
+	for _, test := range []struct {
+		p      Product
+		wantID int
+		err    error
+	}{
+		{Product{Name: "Widget", Price: 9.99}, 1, nil},
+		{Product{Name: "Blue widget", Price: 12}, 2, nil},
+		{Product{Name: "Gadget"}, 3, nil},
+		{Product{Price: 1}, 0, ErrInvalid},
+		{Product{Name: "Refund", Price: -1}, 0, ErrInvalid},
+	} {
+		p, err := s.Add(test.p)
+		if !errors.Is(err, test.err) || p.ID != test.wantID {
+			t.Errorf("Add(%+v) = %d, %v; want %d, %v", test.p, p.ID, err, test.wantID, test.err)
+		}
+	}
+
+	for _, test := range []struct {
+		id    int
+		price float64
+		err   error
+	}{
+		{1, 8.99, nil},
+		{1, -8.99, ErrInvalid},
+		{4, 1, ErrNotFound},
+	} {
+		if err := s.SetPrice(test.id, test.price); !errors.Is(err, test.err) {
+			t.Errorf("SetPrice(%d, %v) = %v, want %v", test.id, test.price, err, test.err)
+		}
+	}
+	if p, err := s.Get(1); err != nil || p.Price != 8.99 {
+		t.Errorf("Get(1) = %+v, %v; want the price 8.99", p, err)
+	}
+
+	if err := s.Remove(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(3); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(3) after Remove = %v, want %v", err, ErrNotFound)
+	}
+	for _, test := range []struct {
+		query string
+		want  []int
+	}{
+		{"widget", []int{1, 2}},
+		{"BLUE", []int{2}},
+		{"", []int{1, 2}},
+		{"gadget", nil},
+	} {
+		var got []int
+		for _, p := range s.Search(test.query) {
+			got = append(got, p.ID)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Search(%q) = %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestMemoryInventory(t *testing.T) {
+	s := NewMemoryInventory()
+	for _, test := range []struct {
+		name  string
+		do    func() error
+		err   error
+		stock [3]int // of products 1 to 3 afterwards
+	}{
+		{"stock 1", func() error { return s.Adjust(1, 2) }, nil, [3]int{2, 0, 0}},
+		{"stock 2", func() error { return s.Adjust(2, 1) }, nil, [3]int{2, 1, 0}},
+		{"overdraw 2", func() error { return s.Adjust(2, -2) }, ErrOutOfStock, [3]int{2, 1, 0}},
+		{"reserve 1 twice and 2", func() error { return s.Reserve([]int{1, 2, 1}) }, nil, [3]int{0, 0, 0}},
+		{"restock", func() error { return s.Adjust(1, 1) }, nil, [3]int{1, 0, 0}},
+		{"reserve 1 and 3", func() error { return s.Reserve([]int{1, 3}) }, ErrOutOfStock, [3]int{1, 0, 0}},
+		{"reserve 1 twice", func() error { return s.Reserve([]int{1, 1}) }, ErrOutOfStock, [3]int{1, 0, 0}},
+		{"reserve nothing", func() error { return s.Reserve(nil) }, nil, [3]int{1, 0, 0}},
+	} {
+		if err := test.do(); !errors.Is(err, test.err) {
+			t.Errorf("%s: %v, want %v", test.name, err, test.err)
+		}
+		if got := [3]int{s.Stock(1), s.Stock(2), s.Stock(3)}; got != test.stock {
+			t.Errorf("after %s, stock is %v, want %v", test.name, got, test.stock)
+		}
+	}
+}
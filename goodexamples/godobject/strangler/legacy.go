@@ -0,0 +1,171 @@
+package strangler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bclements/antipatterns/golangexamples/godobject"
+)
+
+// The legacy components are the god object's method bodies moved, unchanged
+// in behavior, into one type per group. Most of them were stubs and still
+// are; the rest keep only the state their group used.
+
+type legacyUsers struct{}
+
+func (legacyUsers) CreateUser(username, email, password string) (*godobject.User, error) {
+	return nil, nil
+}
+func (legacyUsers) DeleteUser(userID int) error                                     { return nil }
+func (legacyUsers) UpdateUserProfile(userID int, data map[string]interface{}) error { return nil }
+func (legacyUsers) GetUserByID(userID int) (*godobject.User, error)                 { return nil, nil }
+
+type legacyAuth struct{}
+
+func (legacyAuth) AuthenticateUser(username, password string) (string, error) { return "", nil }
+func (legacyAuth) LogoutUser(userID int) error                                { return nil }
+func (legacyAuth) ResetPassword(email string) error                           { return nil }
+func (legacyAuth) ValidateToken(token string) (bool, error)                   { return false, nil }
+func (legacyAuth) RefreshAuthToken(refreshToken string) (string, error)       { return "", nil }
+
+type legacyDatabase struct{}
+
+func (legacyDatabase) ConnectToDatabase() error { return nil }
+func (legacyDatabase) ExecuteQuery(query string, args ...interface{}) (interface{}, error) {
+	return nil, nil
+}
+func (legacyDatabase) MigrateDatabase() error     { return nil }
+func (legacyDatabase) BackupDatabase() error      { return nil }
+func (legacyDatabase) RollbackTransaction() error { return nil }
+
+type legacyCatalog struct{}
+
+func (legacyCatalog) AddProduct(product godobject.Product) error               { return nil }
+func (legacyCatalog) RemoveProduct(productID int) error                        { return nil }
+func (legacyCatalog) UpdateProductPrice(productID int, newPrice float64) error { return nil }
+func (legacyCatalog) SearchProducts(query string) ([]godobject.Product, error) { return nil, nil }
+func (legacyCatalog) GetProductRecommendations(userID int) ([]godobject.Product, error) {
+	return nil, nil
+}
+func (legacyCatalog) UpdateInventory(productID, quantity int) error { return nil }
+
+type legacyOrders struct{}
+
+func (legacyOrders) CreateOrder(userID int, items []int) (*godobject.Order, error) { return nil, nil }
+func (legacyOrders) CancelOrder(orderID int) error                                 { return nil }
+func (legacyOrders) GetOrderStatus(orderID int) (string, error)                    { return "", nil }
+func (legacyOrders) CalculateShipping(orderID int) (float64, error)                { return 0, nil }
+func (legacyOrders) TrackOrder(orderID int) (string, error)                        { return "", nil }
+
+type legacyPayments struct{}
+
+func (legacyPayments) ProcessPayment(orderID int, paymentMethod string) error { return nil }
+func (legacyPayments) RefundPayment(transactionID string) error               { return nil }
+func (legacyPayments) ValidateCreditCard(cardNumber string) (bool, error)     { return false, nil }
+func (legacyPayments) GetPaymentHistory(userID int) ([]godobject.Transaction, error) {
+	return nil, nil
+}
+
+type legacyMailer struct{}
+
+func (legacyMailer) SendEmail(to, subject, body string) error { return nil }
+func (legacyMailer) SendWelcomeEmail(userID int) error        { return nil }
+func (legacyMailer) SendOrderConfirmation(orderID int) error  { return nil }
+func (legacyMailer) SendPasswordResetEmail(email string) error {
+	return nil
+}
+func (legacyMailer) QueueEmail(email godobject.Email) error { return nil }
+
+type legacyFiles struct{}
+
+func (legacyFiles) UploadFile(file []byte, userID int) (string, error) { return "", nil }
+func (legacyFiles) DeleteFile(fileID string) error                     { return nil }
+func (legacyFiles) GetFileURL(fileID string) (string, error)           { return "", nil }
+
+type legacyNotifications struct{}
+
+func (legacyNotifications) SendNotification(userID int, message string) error { return nil }
+func (legacyNotifications) MarkNotificationRead(notificationID int) error     { return nil }
+func (legacyNotifications) GetUnreadNotifications(userID int) ([]godobject.Notification, error) {
+	return nil, nil
+}
+
+// legacyLog keeps info and error lines apart; ClearLogs only ever cleared
+// the info lines.
+type legacyLog struct {
+	info, errors []string
+}
+
+func newLog() *legacyLog { return &legacyLog{} }
+
+func (l *legacyLog) LogInfo(message string) {
+	l.info = append(l.info, fmt.Sprintf("[INFO] %s", message))
+}
+
+func (l *legacyLog) LogError(message string) {
+	l.errors = append(l.errors, fmt.Sprintf("[ERROR] %s", message))
+}
+
+func (l *legacyLog) ExportLogs(format string) (string, error) { return "", nil }
+func (l *legacyLog) ClearLogs()                               { l.info = nil }
+
+// legacyCache records each entry's expiry but never consults it, as the
+// god object did.
+type legacyCache struct {
+	values map[string]interface{}
+	expiry map[string]time.Time
+}
+
+func newCache() *legacyCache {
+	return &legacyCache{values: make(map[string]interface{}), expiry: make(map[string]time.Time)}
+}
+
+func (c *legacyCache) CacheSet(key string, value interface{}, ttl time.Duration) {
+	c.values[key] = value
+	c.expiry[key] = time.Now().Add(ttl)
+}
+
+func (c *legacyCache) CacheGet(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *legacyCache) CacheInvalidate(key string) { delete(c.values, key) }
+func (c *legacyCache) CacheClearAll()             { c.values = make(map[string]interface{}) }
+
+type legacyAnalytics struct {
+	pageViews []godobject.PageView
+	events    []godobject.Event
+}
+
+func newAnalytics() *legacyAnalytics { return &legacyAnalytics{} }
+
+func (a *legacyAnalytics) TrackPageView(userID int, page string) {
+	a.pageViews = append(a.pageViews, godobject.PageView{UserID: userID, Page: page, Time: time.Now()})
+}
+
+func (a *legacyAnalytics) TrackEvent(userID int, eventName string, properties map[string]interface{}) {
+	a.events = append(a.events, godobject.Event{UserID: userID, Name: eventName, Data: properties})
+}
+
+func (a *legacyAnalytics) GenerateAnalyticsReport(startDate, endDate time.Time) (string, error) {
+	return "", nil
+}
+
+type legacyConfig map[string]interface{}
+
+func newConfig() legacyConfig { return make(legacyConfig) }
+
+func (c legacyConfig) GetConfig(key string) (interface{}, bool) {
+	value, ok := c[key]
+	return value, ok
+}
+
+func (c legacyConfig) SetConfig(key string, value interface{}) { c[key] = value }
+
+// legacyFeatures is the god object's flag map, which nothing ever set.
+type legacyFeatures map[string]bool
+
+func newFeatures() legacyFeatures { return make(legacyFeatures) }
+
+func (f legacyFeatures) IsFeatureEnabled(featureName string) bool { return f[featureName] }
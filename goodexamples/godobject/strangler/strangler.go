@@ -0,0 +1,180 @@
+// Package strangler is the first step out of the god object: a facade with
+// exactly the method set of godobject.ApplicationManager, whose methods are
+// no longer its own.
+//
+// The facade embeds one interface per responsibility group, so each of the
+// old methods is promoted from the component that now owns it. Callers keep
+// compiling and behaving as before while the components are replaced, one
+// group at a time, by real services such as those in goodexamples/godobject;
+// when no caller needs the facade any more it is deleted. The components New
+// wires in reproduce the legacy behavior exactly, which cmd/characterize
+// checks by replaying a transcript recorded from the original.
+package strangler
+
+import (
+	"time"
+
+	"github.com/bclements/antipatterns/golangexamples/godobject"
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+)
+
+// Users creates and looks up user accounts.
+type Users interface {
+	CreateUser(username, email, password string) (*godobject.User, error)
+	DeleteUser(userID int) error
+	UpdateUserProfile(userID int, data map[string]interface{}) error
+	GetUserByID(userID int) (*godobject.User, error)
+}
+
+// Auth issues and checks session tokens.
+type Auth interface {
+	AuthenticateUser(username, password string) (string, error)
+	LogoutUser(userID int) error
+	ResetPassword(email string) error
+	ValidateToken(token string) (bool, error)
+	RefreshAuthToken(refreshToken string) (string, error)
+}
+
+// Database manages the connection and schema.
+type Database interface {
+	ConnectToDatabase() error
+	ExecuteQuery(query string, args ...interface{}) (interface{}, error)
+	MigrateDatabase() error
+	BackupDatabase() error
+	RollbackTransaction() error
+}
+
+// Catalog holds products, their stock and recommendations.
+type Catalog interface {
+	AddProduct(product godobject.Product) error
+	RemoveProduct(productID int) error
+	UpdateProductPrice(productID int, newPrice float64) error
+	SearchProducts(query string) ([]godobject.Product, error)
+	GetProductRecommendations(userID int) ([]godobject.Product, error)
+	UpdateInventory(productID, quantity int) error
+}
+
+// Orders places orders and ships them.
+type Orders interface {
+	CreateOrder(userID int, items []int) (*godobject.Order, error)
+	CancelOrder(orderID int) error
+	GetOrderStatus(orderID int) (string, error)
+	CalculateShipping(orderID int) (float64, error)
+	TrackOrder(orderID int) (string, error)
+}
+
+// Payments charges and refunds.
+type Payments interface {
+	ProcessPayment(orderID int, paymentMethod string) error
+	RefundPayment(transactionID string) error
+	ValidateCreditCard(cardNumber string) (bool, error)
+	GetPaymentHistory(userID int) ([]godobject.Transaction, error)
+}
+
+// Mailer sends email.
+type Mailer interface {
+	SendEmail(to, subject, body string) error
+	SendWelcomeEmail(userID int) error
+	SendOrderConfirmation(orderID int) error
+	SendPasswordResetEmail(email string) error
+	QueueEmail(email godobject.Email) error
+}
+
+// Logging records and exports log lines.
+type Logging interface {
+	LogInfo(message string)
+	LogError(message string)
+	ExportLogs(format string) (string, error)
+	ClearLogs()
+}
+
+// Cache is a key-value cache.
+type Cache interface {
+	CacheSet(key string, value interface{}, ttl time.Duration)
+	CacheGet(key string) (interface{}, bool)
+	CacheInvalidate(key string)
+	CacheClearAll()
+}
+
+// Files stores uploads.
+type Files interface {
+	UploadFile(file []byte, userID int) (string, error)
+	DeleteFile(fileID string) error
+	GetFileURL(fileID string) (string, error)
+}
+
+// Notifications delivers in-app notifications.
+type Notifications interface {
+	SendNotification(userID int, message string) error
+	MarkNotificationRead(notificationID int) error
+	GetUnreadNotifications(userID int) ([]godobject.Notification, error)
+}
+
+// Analytics records page views and events.
+type Analytics interface {
+	TrackPageView(userID int, page string)
+	TrackEvent(userID int, eventName string, properties map[string]interface{})
+	GenerateAnalyticsReport(startDate, endDate time.Time) (string, error)
+}
+
+// Config holds runtime settings.
+type Config interface {
+	GetConfig(key string) (interface{}, bool)
+	SetConfig(key string, value interface{})
+}
+
+// Features reports which features are switched on.
+type Features interface {
+	IsFeatureEnabled(featureName string) bool
+}
+
+// FlagFeatures is a Features backed by a flag evaluator. The original's
+// callers never say who is asking, so flags are evaluated for an anonymous
+// user: targeted users and partial rollouts do not see them.
+type FlagFeatures struct {
+	Flags *flags.Evaluator
+}
+
+func (f FlagFeatures) IsFeatureEnabled(featureName string) bool {
+	return f.Flags.Enabled(featureName, "")
+}
+
+// ApplicationManager has the method set of godobject.ApplicationManager,
+// each method routed to the component for its group. Any component can be
+// replaced before the facade is used.
+type ApplicationManager struct {
+	Users
+	Auth
+	Database
+	Catalog
+	Orders
+	Payments
+	Mailer
+	Logging
+	Cache
+	Files
+	Notifications
+	Analytics
+	Config
+	Features
+}
+
+// New returns a facade whose components behave as the god object did.
+func New() *ApplicationManager {
+	return &ApplicationManager{
+		Users:         legacyUsers{},
+		Auth:          legacyAuth{},
+		Database:      legacyDatabase{},
+		Catalog:       legacyCatalog{},
+		Orders:        legacyOrders{},
+		Payments:      legacyPayments{},
+		Mailer:        legacyMailer{},
+		Logging:       newLog(),
+		Cache:         newCache(),
+		Files:         legacyFiles{},
+		Notifications: legacyNotifications{},
+		Analytics:     newAnalytics(),
+		Config:        newConfig(),
+		Features:      newFeatures(),
+	}
+}
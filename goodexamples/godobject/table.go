@@ -0,0 +1,104 @@
+package godobject
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A table is the in-memory storage the services share: rows by key,
+// guarded by a mutex, with ErrNotFound for missing keys.
+type table[K comparable, V any] struct {
+	kind string // e.g. "user", for errors
+
+	mu   sync.Mutex
+	rows map[K]V
+}
+
+func newTable[K comparable, V any](kind string) *table[K, V] {
+	return &table[K, V]{kind: kind, rows: make(map[K]V)}
+}
+
+func (t *table[K, V]) get(key K) (V, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v, ok := t.rows[key]
+	if !ok {
+		return v, t.notFound(key)
+	}
+	return v, nil
+}
+
+func (t *table[K, V]) put(key K, v V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rows[key] = v
+}
+
+// insert stores the row new returns, unless conflict, if not nil, returns
+// an error for a stored row. new runs with t.mu held.
+func (t *table[K, V]) insert(new func() (K, V), conflict func(V) error) (V, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if conflict != nil {
+		for _, v := range t.rows {
+			if err := conflict(v); err != nil {
+				var zero V
+				return zero, err
+			}
+		}
+	}
+	k, v := new()
+	t.rows[k] = v
+	return v, nil
+}
+
+// update calls f with the row stored under key and stores what it leaves
+// there, unless it returns an error.
+func (t *table[K, V]) update(key K, f func(*V) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v, ok := t.rows[key]
+	if !ok {
+		return t.notFound(key)
+	}
+	if err := f(&v); err != nil {
+		return err
+	}
+	t.rows[key] = v
+	return nil
+}
+
+// tx calls f with every row, for changes that must see or touch several
+// rows at once. Keys f deletes from rows are deleted from the table.
+func (t *table[K, V]) tx(f func(rows map[K]V) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return f(t.rows)
+}
+
+func (t *table[K, V]) remove(key K) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.rows[key]; !ok {
+		return t.notFound(key)
+	}
+	delete(t.rows, key)
+	return nil
+}
+
+// find returns the rows match accepts, in no particular order.
+func (t *table[K, V]) find(match func(V) bool) []V {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []V
+	for _, v := range t.rows {
+		if match(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (t *table[K, V]) notFound(key K) error {
+	return fmt.Errorf("%s %v: %w", t.kind, key, ErrNotFound)
+}
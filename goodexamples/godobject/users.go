@@ -0,0 +1,110 @@
+package godobject
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// User is a registered user.
+type User struct {
+	ID       int
+	Username string
+	Email    string
+}
+
+// UserService manages user accounts.
+type UserService interface {
+	Create(username, email, password string) (User, error)
+	Get(id int) (User, error)
+	UpdateEmail(id int, email string) error
+	Delete(id int) error
+	// Verify returns the user with the given username and password, or
+	// ErrBadCredentials.
+	Verify(username, password string) (User, error)
+}
+
+// hashIterations is the PBKDF2 work factor for stored passwords.
+const hashIterations = 100_000
+
+type account struct {
+	User
+	salt, hash []byte
+}
+
+// MemoryUsers is a UserService that keeps accounts in memory. Passwords
+// are stored as salted PBKDF2 hashes.
+type MemoryUsers struct {
+	accounts *table[int, account]
+	nextID   int // guarded by accounts.mu
+}
+
+// NewMemoryUsers returns an empty user store.
+func NewMemoryUsers() *MemoryUsers {
+	return &MemoryUsers{accounts: newTable[int, account]("user"), nextID: 1}
+}
+
+func (s *MemoryUsers) Create(username, email, password string) (User, error) {
+	if len(username) < 3 || !strings.Contains(email, "@") || len(password) < 8 {
+		return User{}, fmt.Errorf("user %q: %w", username, ErrInvalid)
+	}
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	hash, err := hashPassword(password, salt)
+	if err != nil {
+		return User{}, err
+	}
+	a, err := s.accounts.insert(func() (int, account) {
+		id := s.nextID
+		s.nextID++
+		return id, account{User: User{ID: id, Username: username, Email: email}, salt: salt, hash: hash}
+	}, func(a account) error {
+		if a.Username == username {
+			return fmt.Errorf("user %q: %w", username, ErrExists)
+		}
+		return nil
+	})
+	return a.User, err
+}
+
+func (s *MemoryUsers) Get(id int) (User, error) {
+	a, err := s.accounts.get(id)
+	return a.User, err
+}
+
+func (s *MemoryUsers) UpdateEmail(id int, email string) error {
+	if !strings.Contains(email, "@") {
+		return fmt.Errorf("email %q: %w", email, ErrInvalid)
+	}
+	return s.accounts.update(id, func(a *account) error {
+		a.Email = email
+		return nil
+	})
+}
+
+func (s *MemoryUsers) Delete(id int) error {
+	return s.accounts.remove(id)
+}
+
+func (s *MemoryUsers) Verify(username, password string) (User, error) {
+	found := s.accounts.find(func(a account) bool { return a.Username == username })
+	if len(found) == 0 {
+		return User{}, ErrBadCredentials
+	}
+	a := found[0]
+	hash, err := hashPassword(password, a.salt)
+	if err != nil {
+		return User{}, err
+	}
+	if subtle.ConstantTimeCompare(hash, a.hash) != 1 {
+		return User{}, ErrBadCredentials
+	}
+	return a.User, nil
+}
+
+func hashPassword(password string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, password, salt, hashIterations, 32)
+}
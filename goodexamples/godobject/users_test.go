@@ -0,0 +1,73 @@
+package godobject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryUsers(t *testing.T) {
+	s := NewMemoryUsers()
+	for _, test := range []struct {
+		username, email, phrase string
+		wantID                  int
+		err                     error
+	}{
+		{"ann", "ann@example.com", "long enough", 1, nil},
+		{"bob", "bob@example.com", "also long enough", 2, nil},
+		{"ann", "other@example.com", "long enough", 0, ErrExists},
+		{"al", "al@example.com", "long enough", 0, ErrInvalid},
+		{"carol", "carol.example.com", "long enough", 0, ErrInvalid},
+		{"carol", "carol@example.com", "short", 0, ErrInvalid},
+	} {
+		u, err := s.Create(test.username, test.email, test.phrase)
+		if !errors.Is(err, test.err) || u.ID != test.wantID {
+			t.Errorf("Create(%q, %q) = %d, %v; want %d, %v", test.username, test.email, u.ID, err, test.wantID, test.err)
+		}
+	}
+
+	for _, test := range []struct {
+		username, phrase string
+		wantID           int
+		err              error
+	}{
+		{"ann", "long enough", 1, nil},
+		{"bob", "also long enough", 2, nil},
+		{"ann", "also long enough", 0, ErrBadCredentials},
+		{"dave", "long enough", 0, ErrBadCredentials},
+	} {
+		u, err := s.Verify(test.username, test.phrase)
+		if !errors.Is(err, test.err) || u.ID != test.wantID {
+			t.Errorf("Verify(%q, %q) = %d, %v; want %d, %v", test.username, test.phrase, u.ID, err, test.wantID, test.err)
+		}
+	}
+
+	for _, test := range []struct {
+		id    int
+		email string
+		err   error
+	}{
+		{1, "ann@example.org", nil},
+		{1, "ann", ErrInvalid},
+		{3, "nobody@example.com", ErrNotFound},
+	} {
+		if err := s.UpdateEmail(test.id, test.email); !errors.Is(err, test.err) {
+			t.Errorf("UpdateEmail(%d, %q) = %v, want %v", test.id, test.email, err, test.err)
+		}
+	}
+	if u, err := s.Get(1); err != nil || u != (User{ID: 1, Username: "ann", Email: "ann@example.org"}) {
+		t.Errorf("Get(1) = %+v, %v", u, err)
+	}
+
+	if err := s.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete(2) = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := s.Get(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(2) after Delete = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := s.Verify("bob", "also long enough"); !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("Verify of a deleted user = %v, want %v", err, ErrBadCredentials)
+	}
+}
@@ -0,0 +1,96 @@
+// Package goldenhammer is the Golden Hammer example with each job done by
+// the tool that fits it: a regular expression only for e-mail addresses,
+// arithmetic for arithmetic, slices, maps and multiple return values for
+// data, and plain function calls where no concurrency is needed.
+package goldenhammer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// emailPattern is compiled once, not on every call.
+var emailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// ValidateEmail reports whether email looks like an e-mail address. This is
+// the one job in the example a regular expression is right for.
+func ValidateEmail(email string) bool {
+	return emailPattern.MatchString(email)
+}
+
+// IsEven reports whether n is even.
+func IsEven(n int) bool {
+	return n%2 == 0
+}
+
+// Add returns a + b.
+func Add(a, b int) int {
+	return a + b
+}
+
+// Reverse returns s with its characters in reverse order.
+func Reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// Double returns each value of data times two. It runs in the caller's
+// goroutine: there is nothing to overlap with.
+func Double(data []int) []int {
+	out := make([]int, len(data))
+	for i, v := range data {
+		out[i] = v * 2
+	}
+	return out
+}
+
+// IsString reports whether v holds a string.
+func IsString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// TwoNumbers returns two values directly rather than in a struct.
+func TwoNumbers() (first, second int) {
+	return 1, 2
+}
+
+// Set is a set of strings.
+type Set map[string]struct{}
+
+// Add adds s to the set.
+func (set Set) Add(s string) { set[s] = struct{}{} }
+
+// Has reports whether s is in the set.
+func (set Set) Has(s string) bool {
+	_, ok := set[s]
+	return ok
+}
+
+// UserInfo is a user's fixed set of fields, so it is a struct, not a map.
+type UserInfo struct {
+	Name  string
+	Email string
+}
+
+// Point is a point on a grid. Create one with a composite literal; it
+// needs no factory.
+type Point struct {
+	X, Y int
+}
+
+// Upper returns data in upper case; the value is simply passed in.
+func Upper(data string) string {
+	return strings.ToUpper(data)
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	fmt.Fprintln(w, "Is 4 even?", IsEven(4))
+	fmt.Fprintln(w, "Sum:", Add(5, 10))
+}
@@ -0,0 +1,180 @@
+package flags
+
+import (
+	"context"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Reasons an Event gives for its value.
+const (
+	ReasonOverride = "override" // an environment variable forced it
+	ReasonUnknown  = "unknown"  // no such flag; off
+	ReasonDisabled = "disabled" // the master switch is off
+	ReasonTargeted = "targeted" // the user is listed
+	ReasonRollout  = "rollout"  // the user's bucket fell inside or outside the rollout
+	ReasonOn       = "on"       // on for everyone
+)
+
+// Options configure an Evaluator.
+type Options struct {
+	// Getenv reads overrides: FLAG_NEW_UI=on forces new_ui on, and off,
+	// true, false, 1 and 0 work too. Other values are ignored. It is
+	// os.Getenv in a real program; nil means no overrides.
+	Getenv func(string) string
+	// OnEvaluate, if set, is called with every evaluation.
+	OnEvaluate func(Event)
+	// Now stamps events; nil means time.Now.
+	Now func() time.Time
+}
+
+// An Evaluator answers whether a flag is on for a user. It is safe for
+// concurrent use, including while its flags are replaced.
+type Evaluator struct {
+	flags atomic.Pointer[map[string]Flag]
+	opts  Options
+}
+
+// New returns an evaluator of the given flags.
+func New(flags []Flag, opts Options) (*Evaluator, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	e := &Evaluator{opts: opts}
+	if err := e.Replace(flags); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Replace swaps in a new set of flags, if they are well formed.
+func (e *Evaluator) Replace(flags []Flag) error {
+	if err := Check(flags); err != nil {
+		return err
+	}
+	m := make(map[string]Flag, len(flags))
+	for _, f := range flags {
+		m[f.Name] = f
+	}
+	e.flags.Store(&m)
+	return nil
+}
+
+// Enabled reports whether the flag is on for the user; "" is an anonymous
+// user.
+func (e *Evaluator) Enabled(name, user string) bool {
+	return e.Evaluate(name, user).Value
+}
+
+// Evaluate decides whether the flag is on for the user, reports the
+// decision to OnEvaluate and returns it.
+func (e *Evaluator) Evaluate(name, user string) Event {
+	ev := Event{Time: e.opts.Now(), Flag: name, User: user}
+	ev.Value, ev.Reason = e.decide(name, user)
+	if e.opts.OnEvaluate != nil {
+		e.opts.OnEvaluate(ev)
+	}
+	return ev
+}
+
+func (e *Evaluator) decide(name, user string) (bool, string) {
+	if on, ok := e.override(name); ok {
+		return on, ReasonOverride
+	}
+	f, ok := (*e.flags.Load())[name]
+	switch {
+	case !ok:
+		return false, ReasonUnknown
+	case !f.Enabled:
+		return false, ReasonDisabled
+	case user != "" && slices.Contains(f.Users, user):
+		return true, ReasonTargeted
+	case f.Rollout == nil || *f.Rollout >= 100:
+		return true, ReasonOn
+	case user == "":
+		return false, ReasonRollout
+	}
+	return Bucket(name, user) < *f.Rollout, ReasonRollout
+}
+
+func (e *Evaluator) override(name string) (on, ok bool) {
+	if e.opts.Getenv == nil {
+		return false, false
+	}
+	switch strings.ToLower(e.opts.Getenv(EnvName(name))) {
+	case "on", "true", "1":
+		return true, true
+	case "off", "false", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// EnvName returns the environment variable that overrides a flag:
+// FLAG_NEW_UI for new_ui.
+func EnvName(flag string) string {
+	return "FLAG_" + strings.ToUpper(flag)
+}
+
+// Bucket places a user in [0, 100) for a flag. The same user and flag
+// always land in the same place, so raising a rollout only ever adds
+// users, and different flags spread users differently.
+func Bucket(flag, user string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(flag))
+	h.Write([]byte{0})
+	h.Write([]byte(user))
+	return float64(h.Sum32()%10000) / 100
+}
+
+// DefaultWatchInterval is how often Watch checks the flags file when it is
+// given no interval.
+const DefaultWatchInterval = 5 * time.Second
+
+// Watch reloads the evaluator from the flags file at its first check and
+// then whenever the file's size or modification time changes, checking
+// every interval, or every DefaultWatchInterval if interval is not
+// positive, until ctx is done. Loading at the first check picks up edits
+// made since the evaluator was created. A file that fails to load, or
+// disappears, leaves the current flags in place and is reported once to
+// onError, if not nil.
+func (e *Evaluator) Watch(ctx context.Context, path string, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	var last os.FileInfo
+	first := true
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		fi, err := os.Stat(path)
+		switch {
+		case err != nil:
+			if last == nil && !first {
+				continue // still missing
+			}
+			last = nil
+		case last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size():
+			continue
+		default:
+			last = fi
+			var flags []Flag
+			if flags, err = Load(path); err == nil {
+				err = e.Replace(flags)
+			}
+		}
+		first = false
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
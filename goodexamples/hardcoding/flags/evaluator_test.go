@@ -0,0 +1,181 @@
+package flags_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+)
+
+func TestEvaluate(t *testing.T) {
+	ten, none := 10.0, 0.0
+	e, err := flags.New([]flags.Flag{
+		{Name: "off"},
+		{Name: "on", Enabled: true},
+		{Name: "partners", Enabled: true, Users: []string{"partner-acme"}, Rollout: &none},
+		{Name: "tenth", Enabled: true, Rollout: &ten},
+		{Name: "forced", Enabled: false},
+	}, flags.Options{
+		Getenv: func(name string) string {
+			if name == "FLAG_FORCED" {
+				return "ON"
+			}
+			return ""
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		flag, user string
+		value      bool
+		reason     string
+	}{
+		{"off", "user1", false, flags.ReasonDisabled},
+		{"on", "", true, flags.ReasonOn},
+		{"partners", "partner-acme", true, flags.ReasonTargeted},
+		{"partners", "user1", false, flags.ReasonRollout},
+		{"tenth", "", false, flags.ReasonRollout},
+		{"forced", "user1", true, flags.ReasonOverride},
+		{"missing", "user1", false, flags.ReasonUnknown},
+	}
+	for _, test := range tests {
+		ev := e.Evaluate(test.flag, test.user)
+		if ev.Value != test.value || ev.Reason != test.reason {
+			t.Errorf("Evaluate(%q, %q) = %v (%s), want %v (%s)", test.flag, test.user, ev.Value, ev.Reason, test.value, test.reason)
+		}
+	}
+
+	// A tenth of users, give or take, are in the rollout, and always the
+	// same ones.
+	in := 0
+	for i := range 1000 {
+		user := fmt.Sprintf("user%d", i)
+		if e.Enabled("tenth", user) {
+			in++
+			if flags.Bucket("tenth", user) >= 10 {
+				t.Errorf("%s is in the rollout from bucket %g", user, flags.Bucket("tenth", user))
+			}
+		}
+	}
+	if in < 70 || in > 130 {
+		t.Errorf("%d of 1000 users in a 10%% rollout", in)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e, err := flags.New([]flags.Flag{{Name: "new_ui", Enabled: true}}, flags.Options{
+		OnEvaluate: flags.JSONRecorder(&buf),
+		Now:        func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Enabled("new_ui", "user1")
+	e.Enabled("old_ui", "")
+	want := `{"time":"2026-01-01T00:00:00Z","flag":"new_ui","user":"user1","value":true,"reason":"on"}
+{"time":"2026-01-01T00:00:00Z","flag":"old_ui","value":false,"reason":"unknown"}
+`
+	if buf.String() != want {
+		t.Errorf("recorded:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	events, err := flags.ReadEvents(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := flags.Stale([]flags.Flag{{Name: "new_ui", Enabled: true}, {Name: "dark_mode"}}, flags.Summarize(events))
+	wantFindings := []flags.Finding{
+		{Flag: "dark_mode", Reason: "never evaluated"},
+		{Flag: "new_ui", Reason: "on in all 1 evaluations"},
+		{Flag: "old_ui", Reason: "not defined, but evaluated 1 times"},
+	}
+	if len(findings) != len(wantFindings) {
+		t.Fatalf("Stale = %v, want %v", findings, wantFindings)
+	}
+	for i := range findings {
+		if findings[i] != wantFindings[i] {
+			t.Errorf("Stale[%d] = %v, want %v", i, findings[i], wantFindings[i])
+		}
+	}
+}
+
+// TestWatch rewrites a flags file under a watching evaluator and waits for
+// it to see the new value, then breaks the file and checks that the last
+// good flags stay in place.
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("flags:\n  - name: new_ui\n    enabled: false\n")
+	defined, err := flags.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := flags.New(defined, flags.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := make(chan error, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Watch(ctx, path, time.Millisecond, func(err error) { errs <- err })
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	write("flags:\n  - name: new_ui\n    enabled: true\n")
+	waitFor("new_ui to turn on", func() bool { return e.Enabled("new_ui", "") })
+
+	write("flags:\n  - name: New UI\n    enabled: false\n")
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("onError called with nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a malformed file was not reported")
+	}
+	if !e.Enabled("new_ui", "") {
+		t.Error("a malformed file replaced the flags")
+	}
+}
+
+// TestWatchDefaultInterval checks that Watch takes a zero interval as the
+// default rather than panicking.
+func TestWatchDefaultInterval(t *testing.T) {
+	e, err := flags.New(nil, flags.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		e.Watch(ctx, filepath.Join(t.TempDir(), "flags.yaml"), interval, nil)
+	}
+}
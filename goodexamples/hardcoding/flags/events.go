@@ -0,0 +1,117 @@
+package flags
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// An Event is one evaluation of a flag.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Flag   string    `json:"flag"`
+	User   string    `json:"user,omitempty"`
+	Value  bool      `json:"value"`
+	Reason string    `json:"reason"`
+}
+
+// JSONRecorder returns an OnEvaluate function that writes each event to w
+// as a line of JSON. It is safe for concurrent use; write errors are
+// dropped, since evaluating a flag must not fail.
+func JSONRecorder(w io.Writer) func(Event) {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(ev)
+	}
+}
+
+// ReadEvents reads events written by a JSONRecorder.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		events = append(events, ev)
+	}
+	return events, sc.Err()
+}
+
+// Usage is what the events say about one flag.
+type Usage struct {
+	Flag        string
+	Evaluations int
+	On          int // evaluations that returned true
+	Last        time.Time
+}
+
+// Summarize tallies events by flag.
+func Summarize(events []Event) map[string]*Usage {
+	m := make(map[string]*Usage)
+	for _, ev := range events {
+		u := m[ev.Flag]
+		if u == nil {
+			u = &Usage{Flag: ev.Flag}
+			m[ev.Flag] = u
+		}
+		u.Evaluations++
+		if ev.Value {
+			u.On++
+		}
+		if ev.Time.After(u.Last) {
+			u.Last = ev.Time
+		}
+	}
+	return m
+}
+
+// A Finding is a flag that is probably ready to be deleted, from the flags
+// file, from the code or from both.
+type Finding struct {
+	Flag   string
+	Reason string
+}
+
+// Stale compares the defined flags with their usage. A defined flag is
+// stale if it was never evaluated, since the code that asked for it is
+// gone, or if every evaluation gave the same answer, since the feature is
+// either fully launched or abandoned and the branch for the other answer is
+// dead. A flag that was evaluated but is not defined is reported too: it is
+// off everywhere, so the code guarded by it is dead, or its name is a typo.
+// Findings are sorted by flag.
+func Stale(defined []Flag, usage map[string]*Usage) []Finding {
+	var out []Finding
+	known := make(map[string]bool)
+	for _, f := range defined {
+		known[f.Name] = true
+		u := usage[f.Name]
+		switch {
+		case u == nil:
+			out = append(out, Finding{f.Name, "never evaluated"})
+		case u.On == 0:
+			out = append(out, Finding{f.Name, fmt.Sprintf("off in all %d evaluations", u.Evaluations)})
+		case u.On == u.Evaluations:
+			out = append(out, Finding{f.Name, fmt.Sprintf("on in all %d evaluations", u.Evaluations)})
+		}
+	}
+	for name, u := range usage {
+		if !known[name] {
+			out = append(out, Finding{name, fmt.Sprintf("not defined, but evaluated %d times", u.Evaluations)})
+		}
+	}
+	slices.SortFunc(out, func(a, b Finding) int { return cmp.Compare(a.Flag, b.Flag) })
+	return out
+}
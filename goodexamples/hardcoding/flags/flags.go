@@ -0,0 +1,92 @@
+// Package flags evaluates feature flags defined in a file rather than
+// compiled in, replacing the Hard Coding example's EnableNewUI constants and
+// features map and the God Object's IsFeatureEnabled.
+//
+// A flag can be off, on for everyone, on for named users, or on for a
+// percentage of users chosen by a stable hash, so a user stays in or out of
+// a rollout as it widens. An environment variable can force any flag on or
+// off for the whole process. The Evaluator reloads the file when it
+// changes, and reports every evaluation as an Event, so that flags nobody
+// asks about any more, or that always give the same answer, can be found
+// and deleted before they become lava flow.
+package flags
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A Flag is a feature's switch.
+type Flag struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Enabled is the master switch: a disabled flag is off for everyone.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Users always see an enabled flag, whatever the rollout.
+	Users []string `yaml:"users,omitempty" json:"users,omitempty"`
+	// Rollout is the percentage of other users who see an enabled flag,
+	// 100 if it is not set. Anonymous users are counted out of any rollout
+	// short of 100.
+	Rollout *float64 `yaml:"rollout,omitempty" json:"rollout,omitempty"`
+}
+
+// A File is the content of a flags file.
+type File struct {
+	Flags []Flag `yaml:"flags" json:"flags"`
+}
+
+var validName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Load reads flags from a .yaml, .yml or .json file.
+func Load(path string) ([]Flag, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&f)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&f)
+	default:
+		return nil, fmt.Errorf("%s: want a .yaml, .yml or .json file", path)
+	}
+	if err == nil {
+		err = Check(f.Flags)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return f.Flags, nil
+}
+
+// Check reports every malformed flag: a name that is not lower_snake_case,
+// a name used twice, or a rollout outside 0-100.
+func Check(flags []Flag) error {
+	var errs []error
+	seen := make(map[string]bool)
+	for i, f := range flags {
+		switch {
+		case !validName.MatchString(f.Name):
+			errs = append(errs, fmt.Errorf("flag %d: name %q is not lower_snake_case", i+1, f.Name))
+		case seen[f.Name]:
+			errs = append(errs, fmt.Errorf("flag %s: defined twice", f.Name))
+		case f.Rollout != nil && (*f.Rollout < 0 || *f.Rollout > 100):
+			errs = append(errs, fmt.Errorf("flag %s: rollout %g is not a percentage", f.Name, *f.Rollout))
+		}
+		seen[f.Name] = true
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,451 @@
+// Package hardcoding is the Hard Coding example with its settings moved out
+// of the code. Everything the original compiled in, from hosts and
+// credentials to timeouts, pool sizes and login rules, is a field of Config,
+// loaded in layers when the program starts: built-in defaults, then a
+// configuration file, then the environment, then command-line flags.
+// Credentials are Secrets, fetched from a SecretProvider rather than from
+// those layers, and print as [redacted]. Only values that are the same
+// everywhere have defaults; a missing host or password is reported rather
+// than replaced by production's. The service URLs are those of a profile
+// of goodexamples/hardcoding/profile, named by the configuration, and the
+// feature flags are a file, also named by it, that the evaluator of
+// goodexamples/hardcoding/flags reloads while the program runs.
+package hardcoding
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/profile"
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/ratelimit"
+)
+
+// DatabaseConfig says where the database is and how to log in.
+type DatabaseConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password Secret
+	Name     string
+}
+
+// DSN returns the connection string for the database. It holds the
+// password, so it is a Secret too.
+func (c DatabaseConfig) DSN() Secret {
+	return NewSecret(c.url(url.UserPassword(c.User, c.Password.Reveal())).String())
+}
+
+// String returns the connection string with the password masked.
+func (c DatabaseConfig) String() string {
+	return c.url(url.UserPassword(c.User, c.Password.Reveal())).Redacted()
+}
+
+func (c DatabaseConfig) url(user *url.Userinfo) *url.URL {
+	return &url.URL{
+		Scheme: "postgresql",
+		User:   user,
+		Host:   c.Host + ":" + strconv.Itoa(c.Port),
+		Path:   "/" + c.Name,
+	}
+}
+
+// SMTPConfig says which mail server sends email, and as whom.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password Secret
+}
+
+// APIConfig says how to reach the API.
+type APIConfig struct {
+	BaseURL    string
+	Key        Secret
+	Timeout    time.Duration
+	MaxRetries int
+}
+
+// URL returns the address of an endpoint of the API.
+func (c APIConfig) URL(endpoint string) string {
+	return c.BaseURL + "/" + endpoint
+}
+
+// Headers returns the headers every request to the API carries.
+func (c APIConfig) Headers() map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + c.Key.Reveal(),
+		"Content-Type":  "application/json",
+	}
+}
+
+// CacheConfig says where Redis is and how long each kind of entry lives.
+type CacheConfig struct {
+	RedisHost     string
+	RedisPort     int
+	RedisPassword Secret
+	RedisDB       int
+	UserTTL       time.Duration
+	ProductTTL    time.Duration
+	SessionTTL    time.Duration
+}
+
+// PoolConfig sizes the database connection pool.
+type PoolConfig struct {
+	Size    int
+	Timeout time.Duration
+}
+
+// AuthConfig holds the login rules.
+type AuthConfig struct {
+	MaxLoginAttempts int
+	SessionTimeout   time.Duration
+}
+
+// RateLimitConfig holds the request limits the original's RateLimitCheck
+// declared, each key's allowance per minute, hour and day, and the client
+// addresses exempt from them.
+type RateLimitConfig struct {
+	PerMinute int
+	PerHour   int
+	PerDay    int
+	Allowlist string // comma-separated addresses and CIDR ranges
+}
+
+// Limiter returns a limiter enforcing all three limits at once.
+func (c RateLimitConfig) Limiter(opts ratelimit.Options) (*ratelimit.Limiter, error) {
+	return ratelimit.New([]ratelimit.Limit{
+		{Requests: c.PerMinute, Per: time.Minute},
+		{Requests: c.PerHour, Per: time.Hour},
+		{Requests: c.PerDay, Per: 24 * time.Hour},
+	}, opts)
+}
+
+// Allow returns the parsed allowlist.
+func (c RateLimitConfig) Allow() (ratelimit.Allowlist, error) {
+	return ratelimit.ParseAllowlist(strings.Split(c.Allowlist, ",")...)
+}
+
+// RateLimitCheck does what the original's RateLimitCheck only declared:
+// it counts each user's requests against the configured limits, except
+// those from an allowlisted address.
+type RateLimitCheck struct {
+	limiter *ratelimit.Limiter
+	allow   ratelimit.Allowlist
+}
+
+// Check returns a RateLimitCheck enforcing c.
+func (c RateLimitConfig) Check(opts ratelimit.Options) (*RateLimitCheck, error) {
+	limiter, err := c.Limiter(opts)
+	if err != nil {
+		return nil, err
+	}
+	allow, err := c.Allow()
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimitCheck{limiter: limiter, allow: allow}, nil
+}
+
+// Allow reports whether the user may make a request from addr now and, if
+// so, counts it.
+func (r *RateLimitCheck) Allow(userID int, addr netip.Addr) bool {
+	if r.allow.Contains(addr) {
+		return true
+	}
+	return r.limiter.Allow(strconv.Itoa(userID)).Allowed
+}
+
+// FlagsConfig says where the feature flags the original compiled in are
+// defined, for the evaluator of goodexamples/hardcoding/flags.
+type FlagsConfig struct {
+	File   string        // the flags file; every flag is off if empty
+	Reload time.Duration // how often to check File for changes
+}
+
+// Evaluator returns an evaluator of the flags in File, which follows
+// changes to the file until ctx is done, reporting a file that fails to
+// load to onError, if not nil.
+func (c FlagsConfig) Evaluator(ctx context.Context, opts flags.Options, onError func(error)) (*flags.Evaluator, error) {
+	var defined []flags.Flag
+	if c.File != "" {
+		var err error
+		if defined, err = flags.Load(c.File); err != nil {
+			return nil, err
+		}
+	}
+	e, err := flags.New(defined, opts)
+	if err != nil {
+		return nil, err
+	}
+	if c.File != "" {
+		go e.Watch(ctx, c.File, c.Reload, onError)
+	}
+	return e, nil
+}
+
+// Features are the switches the original compiled in, its EnableNewUI,
+// EnableBetaFeatures and EnableDebugMode constants and its features map,
+// as one user sees them.
+type Features struct {
+	NewUI           bool
+	BetaFeatures    bool
+	DebugMode       bool
+	NewDashboard    bool
+	BetaUI          bool
+	ExperimentalAPI bool
+	DarkMode        bool
+}
+
+// FeaturesFor evaluates every feature flag for a user, "" being an
+// anonymous one.
+func FeaturesFor(e *flags.Evaluator, user string) Features {
+	return Features{
+		NewUI:           e.Enabled("new_ui", user),
+		BetaFeatures:    e.Enabled("beta_features", user),
+		DebugMode:       e.Enabled("debug_mode", user),
+		NewDashboard:    e.Enabled("new_dashboard", user),
+		BetaUI:          e.Enabled("beta_ui", user),
+		ExperimentalAPI: e.Enabled("experimental_api", user),
+		DarkMode:        e.Enabled("dark_mode", user),
+	}
+}
+
+// EndpointsConfig chooses the service URLs the original compiled in as its
+// AuthURL, APIURL, WebURL and SocketURL constants and third-party
+// endpoints: those of a profile in goodexamples/hardcoding/profile.
+type EndpointsConfig struct {
+	Profile string // the profile, such as prod or staging
+	File    string // the profiles file; the embedded profiles if empty
+	// URLs are the profile's endpoints, with environment overrides such
+	// as AUTH_URL applied. Load resolves them.
+	URLs profile.Endpoints
+}
+
+// resolve sets c.URLs to the endpoints of c.Profile, reading overrides
+// through getenv, and returns where each came from.
+func (c *EndpointsConfig) resolve(getenv func(string) string) (profile.Origins, error) {
+	profiles := profile.Default
+	if c.File != "" {
+		var err error
+		if profiles, err = profile.Load(c.File); err != nil {
+			return nil, err
+		}
+	}
+	urls, origins, err := profiles.Resolve(c.Profile, profile.Options{Getenv: getenv})
+	if err != nil {
+		return nil, err
+	}
+	c.URLs = urls
+	return origins, nil
+}
+
+// StripeConfig holds the payment gateway's keys.
+type StripeConfig struct {
+	SecretKey      Secret
+	PublishableKey Secret
+}
+
+// AWSConfig holds the AWS credentials.
+type AWSConfig struct {
+	AccessKeyID     Secret
+	SecretAccessKey Secret
+}
+
+// Config is everything the example needs from its environment.
+type Config struct {
+	Database  DatabaseConfig
+	SMTP      SMTPConfig
+	API       APIConfig
+	Cache     CacheConfig
+	DBPool    PoolConfig
+	Auth      AuthConfig
+	RateLimit RateLimitConfig
+	Flags     FlagsConfig
+	Endpoints EndpointsConfig
+	Stripe    StripeConfig
+	SendGrid  Secret
+	AWS       AWSConfig
+}
+
+// Defaults returns the values that are the same in every deployment: the
+// standard ports and the original's timeouts, TTLs and limits. Hosts and
+// credentials have none.
+func Defaults() Config {
+	return Config{
+		Database: DatabaseConfig{Port: 5432},
+		SMTP:     SMTPConfig{Port: 587},
+		API:      APIConfig{Timeout: 30 * time.Second, MaxRetries: 3},
+		Cache: CacheConfig{
+			RedisPort:  6379,
+			UserTTL:    time.Hour,
+			ProductTTL: 30 * time.Minute,
+			SessionTTL: 2 * time.Hour,
+		},
+		DBPool:    PoolConfig{Size: 10, Timeout: 30 * time.Second},
+		Auth:      AuthConfig{MaxLoginAttempts: 3, SessionTimeout: 30 * time.Minute},
+		RateLimit: RateLimitConfig{PerMinute: 60, PerHour: 1000, PerDay: 10000},
+		Flags:     FlagsConfig{Reload: flags.DefaultWatchInterval},
+	}
+}
+
+// A setting is one configurable value: its key in files and flags, its
+// environment variable and the field it sets. A Secret's "environment
+// variable" is its name in the SecretProvider.
+type setting struct {
+	key   string
+	env   string
+	ptr   any // *string, *int, *time.Duration or *Secret
+	usage string
+	check func(any) error // nil if any value will do
+}
+
+// settings lists every setting of c, pointing into c.
+func (c *Config) settings() []setting {
+	return []setting{
+		{key: "database.host", env: "DB_HOST", ptr: &c.Database.Host, usage: "database server host", check: required},
+		{key: "database.port", env: "DB_PORT", ptr: &c.Database.Port, usage: "database server port", check: port},
+		{key: "database.user", env: "DB_USER", ptr: &c.Database.User, usage: "database user", check: required},
+		{key: "database.password", env: "DB_PASSWORD", ptr: &c.Database.Password, usage: "database password", check: required},
+		{key: "database.name", env: "DB_NAME", ptr: &c.Database.Name, usage: "database name", check: required},
+
+		{key: "smtp.host", env: "SMTP_HOST", ptr: &c.SMTP.Host, usage: "mail server host", check: required},
+		{key: "smtp.port", env: "SMTP_PORT", ptr: &c.SMTP.Port, usage: "mail server port", check: port},
+		{key: "smtp.username", env: "SMTP_USERNAME", ptr: &c.SMTP.Username, usage: "mail server user"},
+		{key: "smtp.password", env: "SMTP_PASSWORD", ptr: &c.SMTP.Password, usage: "mail server password"},
+
+		{key: "api.base_url", env: "API_BASE_URL", ptr: &c.API.BaseURL, usage: "API base URL", check: httpURL},
+		{key: "api.key", env: "API_KEY", ptr: &c.API.Key, usage: "API key", check: required},
+		{key: "api.timeout", env: "API_TIMEOUT", ptr: &c.API.Timeout, usage: "API request timeout", check: positive},
+		{key: "api.max_retries", env: "API_MAX_RETRIES", ptr: &c.API.MaxRetries, usage: "API request retries", check: nonNegative},
+
+		{key: "cache.redis_host", env: "REDIS_HOST", ptr: &c.Cache.RedisHost, usage: "Redis host", check: required},
+		{key: "cache.redis_port", env: "REDIS_PORT", ptr: &c.Cache.RedisPort, usage: "Redis port", check: port},
+		{key: "cache.redis_password", env: "REDIS_PASSWORD", ptr: &c.Cache.RedisPassword, usage: "Redis password"},
+		{key: "cache.redis_db", env: "REDIS_DB", ptr: &c.Cache.RedisDB, usage: "Redis database number", check: nonNegative},
+		{key: "cache.user_ttl", env: "CACHE_USER_TTL", ptr: &c.Cache.UserTTL, usage: "how long users stay cached", check: positive},
+		{key: "cache.product_ttl", env: "CACHE_PRODUCT_TTL", ptr: &c.Cache.ProductTTL, usage: "how long products stay cached", check: positive},
+		{key: "cache.session_ttl", env: "CACHE_SESSION_TTL", ptr: &c.Cache.SessionTTL, usage: "how long sessions stay cached", check: positive},
+
+		{key: "db_pool.size", env: "DB_POOL_SIZE", ptr: &c.DBPool.Size, usage: "database connections to keep open", check: positive},
+		{key: "db_pool.timeout", env: "DB_POOL_TIMEOUT", ptr: &c.DBPool.Timeout, usage: "how long to wait for a free connection", check: positive},
+
+		{key: "auth.max_login_attempts", env: "MAX_LOGIN_ATTEMPTS", ptr: &c.Auth.MaxLoginAttempts, usage: "failed logins before an account locks", check: positive},
+		{key: "auth.session_timeout", env: "SESSION_TIMEOUT", ptr: &c.Auth.SessionTimeout, usage: "how long an idle session lasts", check: positive},
+
+		{key: "rate_limit.per_minute", env: "RATE_LIMIT_PER_MINUTE", ptr: &c.RateLimit.PerMinute, usage: "requests a user may make a minute", check: positive},
+		{key: "rate_limit.per_hour", env: "RATE_LIMIT_PER_HOUR", ptr: &c.RateLimit.PerHour, usage: "requests a user may make an hour", check: positive},
+		{key: "rate_limit.per_day", env: "RATE_LIMIT_PER_DAY", ptr: &c.RateLimit.PerDay, usage: "requests a user may make a day", check: positive},
+		{key: "rate_limit.allowlist", env: "RATE_LIMIT_ALLOWLIST", ptr: &c.RateLimit.Allowlist, usage: "comma-separated client addresses and CIDR ranges never limited", check: allowlist},
+
+		{key: "flags.file", env: "FLAGS_FILE", ptr: &c.Flags.File, usage: "feature flags file, reloaded when it changes"},
+		{key: "flags.reload", env: "FLAGS_RELOAD", ptr: &c.Flags.Reload, usage: "how often to check the flags file for changes", check: positive},
+
+		{key: "endpoints.profile", env: "PROFILE", ptr: &c.Endpoints.Profile, usage: "the profile of service URLs, such as prod or staging", check: required},
+		{key: "endpoints.file", env: "PROFILES_FILE", ptr: &c.Endpoints.File, usage: "profiles file (default: the embedded profiles)"},
+
+		{key: "stripe.secret_key", env: "STRIPE_SECRET_KEY", ptr: &c.Stripe.SecretKey, usage: "Stripe secret key"},
+		{key: "stripe.publishable_key", env: "STRIPE_PUBLISHABLE_KEY", ptr: &c.Stripe.PublishableKey, usage: "Stripe publishable key"},
+		{key: "sendgrid.api_key", env: "SENDGRID_API_KEY", ptr: &c.SendGrid, usage: "SendGrid API key"},
+		{key: "aws.access_key_id", env: "AWS_ACCESS_KEY_ID", ptr: &c.AWS.AccessKeyID, usage: "AWS access key ID"},
+		{key: "aws.secret_access_key", env: "AWS_SECRET_ACCESS_KEY", ptr: &c.AWS.SecretAccessKey, usage: "AWS secret access key"},
+	}
+}
+
+func required(v any) error {
+	switch v := v.(type) {
+	case *string:
+		if *v == "" {
+			return fmt.Errorf("is required")
+		}
+	case *Secret:
+		if v.IsZero() {
+			return fmt.Errorf("is required")
+		}
+	}
+	return nil
+}
+
+func port(v any) error {
+	if p := *v.(*int); p < 1 || p > 65535 {
+		return fmt.Errorf("port %d is not in 1-65535", p)
+	}
+	return nil
+}
+
+func positive(v any) error {
+	switch v := v.(type) {
+	case *int:
+		if *v <= 0 {
+			return fmt.Errorf("must be positive, not %d", *v)
+		}
+	case *time.Duration:
+		if *v <= 0 {
+			return fmt.Errorf("must be positive, not %v", *v)
+		}
+	}
+	return nil
+}
+
+func nonNegative(v any) error {
+	if n := *v.(*int); n < 0 {
+		return fmt.Errorf("must not be negative, not %d", n)
+	}
+	return nil
+}
+
+func allowlist(v any) error {
+	_, err := ratelimit.ParseAllowlist(strings.Split(*v.(*string), ",")...)
+	return err
+}
+
+func httpURL(v any) error {
+	s := *v.(*string)
+	if s == "" {
+		return fmt.Errorf("is required")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", s)
+	}
+	return nil
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w. It
+// plays the deployment's part, supplying the environment the original
+// compiled in, credentials included, so that the two print the same apart
+// from the password.
+func Run(w io.Writer) {
+	deployment := map[string]string{
+		"DB_HOST":      "prod-db-server-01.company.com",
+		"DB_USER":      "admin",
+		"DB_NAME":      "production_db",
+		"SMTP_HOST":    "smtp.gmail.com",
+		"API_BASE_URL": "https://api.production.company.com/v1",
+		"REDIS_HOST":   "redis.production.company.com",
+		"PROFILE":      "prod",
+		"DB_PASSWORD":  "example-password",
+		"API_KEY":      "example-key",
+	}
+	getenv := func(name string) string { return deployment[name] }
+
+	cfg, _, err := Load(context.Background(), Sources{
+		Getenv:  getenv,
+		Secrets: EnvSecrets(getenv),
+	})
+	if err != nil {
+		fmt.Fprintln(w, "configuration:", err)
+		return
+	}
+	fmt.Fprintln(w, "Database connection:", cfg.Database)
+	fmt.Fprintln(w, "API URL:", cfg.API.URL("users"))
+}
@@ -0,0 +1,162 @@
+package hardcoding
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/flags"
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/ratelimit"
+)
+
+// TestFeatures checks that an anonymous user sees the flags in
+// testdata/flags.yaml as the original's constants and map set them, and
+// that targeting reaches a listed user.
+func TestFeatures(t *testing.T) {
+	ctx := t.Context() // stops the watcher when the test ends
+	cfg := Defaults()
+	cfg.Flags.File = "testdata/flags.yaml"
+	e, err := cfg.Flags.Evaluator(ctx, flags.Options{}, func(err error) { t.Error(err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Features{NewUI: true, NewDashboard: true, DarkMode: true}
+	if got := FeaturesFor(e, ""); got != want {
+		t.Errorf("anonymous user sees %+v, want %+v", got, want)
+	}
+	want.ExperimentalAPI = true
+	if got := FeaturesFor(e, "partner-acme"); got != want {
+		t.Errorf("partner-acme sees %+v, want %+v", got, want)
+	}
+
+	cfg.Flags.File = ""
+	e, err = cfg.Flags.Evaluator(ctx, flags.Options{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FeaturesFor(e, ""); got != (Features{}) {
+		t.Errorf("with no flags file, features are %+v, want all off", got)
+	}
+
+	cfg.Flags.File = "testdata/missing.yaml"
+	if _, err := cfg.Flags.Evaluator(ctx, flags.Options{}, nil); err == nil {
+		t.Error("Evaluator of a missing file succeeded")
+	}
+}
+
+// newRateLimitCheck returns c's check, counting exactly on a clock that
+// moves only when *now is changed.
+func newRateLimitCheck(t *testing.T, c RateLimitConfig, now *time.Time) *RateLimitCheck {
+	t.Helper()
+	check, err := c.Check(ratelimit.Options{Algorithm: ratelimit.SlidingLog, Now: func() time.Time { return *now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return check
+}
+
+// allowN makes n requests for the user from addr and returns how many were
+// allowed.
+func allowN(check *RateLimitCheck, userID int, addr string, n int) int {
+	allowed := 0
+	for range n {
+		if check.Allow(userID, netip.MustParseAddr(addr)) {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+// TestRateLimitCheck checks the original's limits, 60 requests a minute,
+// 1000 an hour and 10000 a day, and its allowlist of three addresses.
+func TestRateLimitCheck(t *testing.T) {
+	cfg := Defaults().RateLimit
+	if cfg.PerMinute != 60 || cfg.PerHour != 1000 || cfg.PerDay != 10000 {
+		t.Fatalf("default limits are %+v, want 60, 1000 and 10000", cfg)
+	}
+	cfg.Allowlist = "192.168.1.100, 10.0.0.50, 172.16.0.25"
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	check := newRateLimitCheck(t, cfg, &now)
+	const client = "203.0.113.7"
+
+	if n := allowN(check, 1, client, 61); n != 60 {
+		t.Errorf("%d of 61 requests in a minute allowed, want 60", n)
+	}
+	if n := allowN(check, 2, client, 60); n != 60 {
+		t.Errorf("user 2 allowed %d of 60 requests after user 1 used theirs, want 60", n)
+	}
+	for _, addr := range []string{"192.168.1.100", "10.0.0.50", "172.16.0.25", "::ffff:10.0.0.50"} {
+		if n := allowN(check, 1, addr, 100); n != 100 {
+			t.Errorf("user 1 at allowlisted %s allowed %d of 100 requests, want all", addr, n)
+		}
+	}
+	if n := allowN(check, 1, "172.16.0.26", 1); n != 0 {
+		t.Error("172.16.0.26, next to an allowlisted address, was not limited")
+	}
+
+	// A minute's requests every minute reach the hourly limit in the 17th
+	// minute; the 60 already made count.
+	total := 60
+	for range 16 {
+		now = now.Add(time.Minute)
+		total += allowN(check, 1, client, 60)
+	}
+	if total != 1000 {
+		t.Errorf("%d requests allowed in 17 minutes, want 1000", total)
+	}
+	now = now.Add(time.Minute)
+	if n := allowN(check, 1, client, 1); n != 0 {
+		t.Error("a request past the hourly limit was allowed")
+	}
+
+	// Ten hours of 1000 reach the daily limit.
+	for range 9 {
+		now = now.Add(time.Hour)
+		for range 17 {
+			total += allowN(check, 1, client, 60)
+			now = now.Add(time.Minute)
+		}
+	}
+	if total != 10000 {
+		t.Errorf("%d requests allowed in ten hours, want 10000", total)
+	}
+	now = now.Add(time.Hour)
+	if n := allowN(check, 1, client, 1); n != 0 {
+		t.Error("a request past the daily limit was allowed")
+	}
+	if n := allowN(check, 1, "10.0.0.50", 1); n != 1 {
+		t.Error("an allowlisted request past the daily limit was refused")
+	}
+	now = now.Add(14 * time.Hour)
+	if n := allowN(check, 1, client, 1); n != 1 {
+		t.Error("a request a day after the first was refused")
+	}
+}
+
+// TestRateLimitCheckLoaded checks that the limits and allowlist a
+// configuration file sets are the ones enforced, and that bad ones are
+// reported.
+func TestRateLimitCheckLoaded(t *testing.T) {
+	cfg, _, err := Load(context.Background(), Sources{File: "testdata/app.yaml", Secrets: secrets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	check := newRateLimitCheck(t, cfg.RateLimit, &now)
+	if n := allowN(check, 1, "203.0.113.7", 121); n != 120 {
+		t.Errorf("%d of 121 requests in a minute allowed, want app.yaml's 120", n)
+	}
+	if n := allowN(check, 1, "172.31.255.1", 200); n != 200 {
+		t.Errorf("%d of 200 requests from app.yaml's 172.16.0.0/12 allowed, want all", n)
+	}
+
+	for _, c := range []RateLimitConfig{
+		{PerMinute: 60, PerHour: 1000, PerDay: 10000, Allowlist: "10.0.0.500"},
+		{PerMinute: 0, PerHour: 1000, PerDay: 10000},
+	} {
+		if _, err := c.Check(ratelimit.Options{}); err == nil {
+			t.Errorf("Check of %+v succeeded", c)
+		}
+	}
+}
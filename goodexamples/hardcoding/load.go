@@ -0,0 +1,301 @@
+package hardcoding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/profile"
+)
+
+// Sources are the layers Load reads over the defaults, lowest first, and
+// where it finds secrets. Any of them may be left out.
+type Sources struct {
+	// File is a YAML, JSON or TOML file, by extension, whose keys are the
+	// settings' keys, nested or dotted: database.host is host in the
+	// database table.
+	File string
+	// Getenv reads the environment; it is os.Getenv in a real program.
+	Getenv func(string) string
+	// Flags holds the flags RegisterFlags defined, already parsed. Only
+	// the flags set on the command line count.
+	Flags *flag.FlagSet
+	// Secrets provides every Secret setting, by its variable name. Secrets
+	// are never read from the other layers, which end up in shell history,
+	// process listings and version control.
+	Secrets SecretProvider
+}
+
+// Provenance says which layer set each setting, by key: "default",
+// "file app.yaml", "env DB_HOST", "flag -database.host" or
+// "secret DB_PASSWORD". A setting nothing set has no entry. Each resolved
+// endpoint, such as endpoints.auth, is there too, set by "profile prod" or
+// by an override such as "env AUTH_URL".
+type Provenance map[string]string
+
+// A SettingError is a problem with one setting's value.
+type SettingError struct {
+	Key    string
+	Source string // where the value came from, or "" if nothing set it
+	Err    error
+}
+
+func (e *SettingError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("%s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", e.Key, e.Source, e.Err)
+}
+
+func (e *SettingError) Unwrap() error { return e.Err }
+
+// RegisterFlags defines a string flag for every setting but the secrets,
+// named by its key, e.g. -database.host.
+func RegisterFlags(fs *flag.FlagSet) {
+	var c Config
+	for _, s := range c.settings() {
+		if !isSecret(s) {
+			fs.String(s.key, "", s.usage+" (env "+s.env+")")
+		}
+	}
+}
+
+// Load builds the configuration from the defaults and the sources, then
+// validates it. The error reports every problem found, in every layer, as
+// *SettingError values joined with errors.Join; a file that cannot be read
+// at all is reported on its own.
+func Load(ctx context.Context, src Sources) (Config, Provenance, error) {
+	c := Defaults()
+	prov := make(Provenance)
+	settings := c.settings()
+	byKey := make(map[string]*setting, len(settings))
+	for i := range settings {
+		s := &settings[i]
+		byKey[s.key] = s
+		if format(s.ptr) != "" {
+			prov[s.key] = "default"
+		}
+	}
+
+	var errs []error
+	failed := make(map[string]bool)
+	set := func(s *setting, raw, source string) {
+		if err := parse(s.ptr, raw); err != nil {
+			errs = append(errs, &SettingError{Key: s.key, Source: source, Err: err})
+			failed[s.key] = true
+			return
+		}
+		prov[s.key] = source
+		delete(failed, s.key)
+	}
+
+	if src.File != "" {
+		values, err := readFile(src.File)
+		if err != nil {
+			return c, prov, err
+		}
+		source := "file " + src.File
+		for _, key := range slices.Sorted(maps.Keys(values)) {
+			switch s := byKey[key]; {
+			case s == nil:
+				errs = append(errs, &SettingError{Key: key, Source: source, Err: errors.New("unknown setting")})
+			case isSecret(*s):
+				errs = append(errs, &SettingError{Key: key, Source: source, Err: errors.New("is a secret; give it to the secret provider as " + s.env)})
+			default:
+				set(s, values[key], source)
+			}
+		}
+	}
+	if src.Getenv != nil {
+		for i, s := range settings {
+			if raw := src.Getenv(s.env); raw != "" && !isSecret(s) {
+				set(&settings[i], raw, "env "+s.env)
+			}
+		}
+	}
+	if src.Flags != nil {
+		src.Flags.Visit(func(f *flag.Flag) {
+			if s := byKey[f.Name]; s != nil && !isSecret(*s) {
+				set(s, f.Value.String(), "flag -"+f.Name)
+			}
+		})
+	}
+	if src.Secrets != nil {
+		for _, s := range settings {
+			if !isSecret(s) {
+				continue
+			}
+			v, err := src.Secrets.Secret(ctx, s.env)
+			switch {
+			case err == nil:
+				*s.ptr.(*Secret) = v
+				prov[s.key] = "secret " + s.env
+			case !errors.Is(err, ErrSecretNotFound):
+				errs = append(errs, &SettingError{Key: s.key, Source: "secret " + s.env, Err: err})
+				failed[s.key] = true
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if s.check == nil || failed[s.key] {
+			continue
+		}
+		if err := s.check(s.ptr); err != nil {
+			errs = append(errs, &SettingError{Key: s.key, Source: prov[s.key], Err: err})
+			failed[s.key] = true
+		}
+	}
+	if !failed["endpoints.profile"] && !failed["endpoints.file"] {
+		origins, err := c.Endpoints.resolve(src.Getenv)
+		if err != nil {
+			errs = append(errs, &SettingError{Key: "endpoints.profile", Source: prov["endpoints.profile"], Err: err})
+		}
+		for name, origin := range origins {
+			prov["endpoints."+name] = origin
+		}
+	}
+	return c, prov, errors.Join(errs...)
+}
+
+// PrintConfig writes every setting of c, one per line, with the layer that
+// set it. Secrets are shown only as set or not.
+func PrintConfig(w io.Writer, c Config, prov Provenance) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, s := range c.settings() {
+		value := format(s.ptr)
+		source := prov[s.key]
+		if source == "" {
+			source = "unset"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.key, value, source)
+	}
+	for _, name := range profile.Names() {
+		key := "endpoints." + name
+		if source := prov[key]; source != "" {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", key, c.Endpoints.URLs.URL(name), source)
+		}
+	}
+	return tw.Flush()
+}
+
+func parse(ptr any, raw string) error {
+	switch p := ptr.(type) {
+	case *string:
+		*p = raw
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		*p = n
+	case *time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 1h", raw)
+		}
+		*p = d
+	default:
+		return fmt.Errorf("setting of unsupported type %T", ptr)
+	}
+	return nil
+}
+
+func format(ptr any) string {
+	switch p := ptr.(type) {
+	case *string:
+		return *p
+	case *int:
+		return strconv.Itoa(*p)
+	case *time.Duration:
+		return p.String()
+	case *Secret:
+		if p.IsZero() {
+			return ""
+		}
+	}
+	return fmt.Sprint(ptr)
+}
+
+func isSecret(s setting) bool {
+	_, ok := s.ptr.(*Secret)
+	return ok
+}
+
+// readFile reads a configuration file into raw values by dotted key.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		var tree map[string]any
+		if err = yaml.Unmarshal(data, &tree); err == nil {
+			values, err = flatten(tree)
+		}
+	case ".json":
+		var tree map[string]any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err = dec.Decode(&tree); err == nil {
+			values, err = flatten(tree)
+		}
+	case ".toml":
+		var tree map[string]any
+		if err = toml.Unmarshal(data, &tree); err == nil {
+			values, err = flatten(tree)
+		}
+	default:
+		return nil, fmt.Errorf("%s: want a .yaml, .yml, .json or .toml file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return values, nil
+}
+
+// flatten turns nested tables into dotted keys and scalar values into
+// their text.
+func flatten(tree map[string]any) (map[string]string, error) {
+	out := make(map[string]string)
+	var walk func(prefix string, v any) error
+	walk = func(prefix string, v any) error {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, child := range v {
+				key := k
+				if prefix != "" {
+					key = prefix + "." + k
+				}
+				if err := walk(key, child); err != nil {
+					return err
+				}
+			}
+		case []any, []map[string]any:
+			return fmt.Errorf("%s: lists are not settings", prefix)
+		case nil:
+			return fmt.Errorf("%s: no value", prefix)
+		default:
+			out[prefix] = fmt.Sprint(v)
+		}
+		return nil
+	}
+	return out, walk("", tree)
+}
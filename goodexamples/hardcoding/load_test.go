@@ -0,0 +1,171 @@
+package hardcoding
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secrets sets the required secrets, each to its own name, so that Load
+// can succeed.
+var secrets = EnvSecrets(func(name string) string {
+	if name == "DB_PASSWORD" || name == "API_KEY" {
+		return name
+	}
+	return ""
+})
+
+// TestLoadFormats loads testdata/app.yaml and the same settings written as
+// JSON and TOML, and expects the same configuration from each.
+func TestLoadFormats(t *testing.T) {
+	want, _, err := Load(context.Background(), Sources{File: "testdata/app.yaml", Secrets: secrets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.Database.Host != "staging-db.internal" || want.API.Timeout != 10*time.Second || want.DBPool.Size != 4 ||
+		want.Endpoints.URLs.Auth != "https://auth.staging.company.com" {
+		t.Fatalf("app.yaml loaded as %+v", want)
+	}
+	files := map[string]string{
+		"app.json": `{
+  "database": {"host": "staging-db.internal", "name": "staging_db", "user": "app"},
+  "smtp": {"host": "smtp.staging.internal"},
+  "api": {"base_url": "https://api.staging.internal/v1", "timeout": "10s"},
+  "cache": {"redis_host": "redis.staging.internal", "user_ttl": "15m"},
+  "db_pool.size": 4,
+  "rate_limit": {"per_minute": 120, "allowlist": "10.0.0.50, 172.16.0.0/12"},
+  "endpoints": {"profile": "staging"}
+}`,
+		"app.toml": `# Settings for a staging deployment.
+db_pool.size = 4
+
+[database]
+host = "staging-db.internal"
+name = 'staging_db'
+user = "app" # the application's own user
+
+[smtp]
+host = "smtp.staging.internal"
+
+[api]
+base_url = "https://api.staging.internal/v1"
+timeout = "10s"
+
+[cache]
+redis_host = "redis.staging.internal"
+user_ttl = "15m"
+
+[rate_limit]
+per_minute = 1_20
+allowlist = "10.0.0.50, 172.16.0.0/12"
+
+[endpoints]
+profile = "staging"
+`,
+	}
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		got, prov, err := Load(context.Background(), Sources{File: path, Secrets: secrets})
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s loaded as %+v, want %+v", name, got, want)
+		}
+		if prov["database.host"] != "file "+path {
+			t.Errorf("%s: database.host set by %q", name, prov["database.host"])
+		}
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name, data string
+		want       string
+	}{
+		{"bad.toml", "[database\nhost = 1\n", "bad.toml: toml:"},
+		{"list.toml", "[rate_limit]\nallowlist = [\"10.0.0.50\"]\n", "rate_limit.allowlist: lists are not settings"},
+		{"tables.toml", "[[database]]\nhost = \"a\"\n", "database: lists are not settings"},
+		{"app.ini", "host = a\n", "want a .yaml, .yml, .json or .toml file"},
+	}
+	dir := t.TempDir()
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		if err := os.WriteFile(path, []byte(test.data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := Load(context.Background(), Sources{File: path})
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: Load error %v, want one containing %q", test.name, err, test.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	var n int
+	if err := parse(&n, " 42 "); err != nil || n != 42 {
+		t.Errorf("parse int = %d, %v", n, err)
+	}
+	var d time.Duration
+	if err := parse(&d, "1h"); err != nil || d != time.Hour {
+		t.Errorf("parse duration = %v, %v", d, err)
+	}
+	if err := parse(&d, "an hour"); err == nil {
+		t.Error("parse of a bad duration succeeded")
+	}
+	var b bool
+	if err := parse(&b, "true"); err == nil || !strings.Contains(err.Error(), "unsupported type *bool") {
+		t.Errorf("parse bool: %v, want an unsupported type error", err)
+	}
+}
+
+// TestLoadEndpoints checks that Load resolves the profile's service URLs,
+// with environment overrides, and reports a missing or unknown profile
+// once, against endpoints.profile.
+func TestLoadEndpoints(t *testing.T) {
+	env := map[string]string{
+		"DB_HOST": "db", "DB_USER": "app", "DB_NAME": "app", "SMTP_HOST": "smtp",
+		"API_BASE_URL": "https://api.example.com", "REDIS_HOST": "redis",
+		"PROFILE": "prod", "AUTH_URL": "https://auth.example.com",
+	}
+	getenv := func(name string) string { return env[name] }
+	cfg, prov, err := Load(context.Background(), Sources{Getenv: getenv, Secrets: secrets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Endpoints.URLs.Auth != "https://auth.example.com" || prov["endpoints.auth"] != "env AUTH_URL" {
+		t.Errorf("auth = %q from %q, want the AUTH_URL override", cfg.Endpoints.URLs.Auth, prov["endpoints.auth"])
+	}
+	if cfg.Endpoints.URLs.Twilio != "https://api.twilio.com/2010-04-01" || prov["endpoints.twilio"] != "profile prod" {
+		t.Errorf("twilio = %q from %q, want prod's", cfg.Endpoints.URLs.Twilio, prov["endpoints.twilio"])
+	}
+	var buf strings.Builder
+	if err := PrintConfig(&buf, cfg, prov); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`(?m)^endpoints\.auth +https://auth\.example\.com +env AUTH_URL$`).MatchString(buf.String()) {
+		t.Errorf("PrintConfig does not show the auth endpoint:\n%s", buf.String())
+	}
+
+	for profile, want := range map[string]string{
+		"":        "endpoints.profile: is required",
+		"qa":      `endpoints.profile (env PROFILE): unknown profile "qa": have dev, prod, staging, test`,
+		"staging": `endpoints.profile (env PROFILE): profile staging: auth (env AUTH_URL): "http://auth.example.com" must use https`,
+	} {
+		env["PROFILE"], env["AUTH_URL"] = profile, "http://auth.example.com"
+		_, _, err := Load(context.Background(), Sources{Getenv: getenv, Secrets: secrets})
+		if err == nil || err.Error() != want {
+			t.Errorf("PROFILE=%q: Load error %v, want %s", profile, err, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package profile
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// Local is a server on the loopback interface for every endpoint, for
+// resolving a local profile.
+type Local struct {
+	servers map[string]*httptest.Server
+}
+
+// StartLocal starts a server for every endpoint, serving what handler
+// returns for the endpoint's name. If handler is nil, each server answers
+// every request with its endpoint's name and the request's path, which is
+// enough to see that a request went where it should.
+func StartLocal(handler func(endpoint string) http.Handler) *Local {
+	if handler == nil {
+		handler = echo
+	}
+	l := &Local{servers: make(map[string]*httptest.Server)}
+	for _, name := range Names() {
+		l.servers[name] = httptest.NewServer(handler(name))
+	}
+	return l
+}
+
+func echo(endpoint string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s\n", endpoint, r.URL.Path)
+	})
+}
+
+// URL returns the URL of the named endpoint's server, with a ws scheme for
+// the socket, or "" if there is no such endpoint.
+func (l *Local) URL(endpoint string) string {
+	s := l.servers[endpoint]
+	if s == nil {
+		return ""
+	}
+	var e Endpoints
+	for _, ep := range e.endpoints() {
+		if ep.name == endpoint && ep.socket {
+			return "ws" + strings.TrimPrefix(s.URL, "http")
+		}
+	}
+	return s.URL
+}
+
+// Close shuts the servers down.
+func (l *Local) Close() {
+	for _, s := range l.servers {
+		s.Close()
+	}
+}
@@ -0,0 +1,369 @@
+// Package profile chooses the service URLs for an environment, replacing
+// the production-only AuthURL, APIURL, WebURL and SocketURL constants and
+// the third-party endpoints of the Hard Coding example.
+//
+// A profile is a named set of URLs, such as dev, staging or prod. It may
+// extend another and override some of its URLs, so that staging lists only
+// what differs from prod. Every profile is checked when it is parsed: each
+// endpoint must be set, to an absolute URL of the right scheme and without
+// credentials, and a secure profile allows only https and wss. A local
+// profile lists no URLs: Resolve asks Options.Local for each endpoint's
+// instead, so that tests can point every endpoint at a server of their own.
+package profile
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed profiles.yaml
+var defaultProfiles []byte
+
+// Default are the profiles in profiles.yaml.
+var Default = mustParse(defaultProfiles, "yaml")
+
+// Endpoints are the URLs of the services a program talks to.
+type Endpoints struct {
+	Auth   string
+	API    string
+	Web    string
+	Socket string
+
+	Stripe   string
+	PayPal   string
+	SendGrid string
+	Twilio   string
+}
+
+// An endpoint is one field of Endpoints and its name in profiles.
+type endpoint struct {
+	name   string
+	ptr    *string
+	socket bool // ws and wss rather than http and https
+}
+
+func (e *Endpoints) endpoints() []endpoint {
+	return []endpoint{
+		{name: "auth", ptr: &e.Auth},
+		{name: "api", ptr: &e.API},
+		{name: "web", ptr: &e.Web},
+		{name: "socket", ptr: &e.Socket, socket: true},
+		{name: "stripe", ptr: &e.Stripe},
+		{name: "paypal", ptr: &e.PayPal},
+		{name: "sendgrid", ptr: &e.SendGrid},
+		{name: "twilio", ptr: &e.Twilio},
+	}
+}
+
+// Names returns the endpoints' names, as profiles list them.
+func Names() []string {
+	var e Endpoints
+	var names []string
+	for _, ep := range e.endpoints() {
+		names = append(names, ep.name)
+	}
+	return names
+}
+
+// URL returns the URL of the named endpoint, or "" if there is no such
+// endpoint.
+func (e Endpoints) URL(name string) string {
+	for _, ep := range e.endpoints() {
+		if ep.name == name {
+			return *ep.ptr
+		}
+	}
+	return ""
+}
+
+// EnvName returns the environment variable that overrides an endpoint:
+// AUTH_URL for auth.
+func EnvName(endpoint string) string {
+	return strings.ToUpper(endpoint) + "_URL"
+}
+
+// Origins says where each endpoint's URL came from: "profile prod",
+// "env AUTH_URL" or "local".
+type Origins map[string]string
+
+// Profiles are the environments a program can run in.
+type Profiles struct {
+	defs map[string]*definition
+}
+
+// A definition is a profile as written in the file.
+type definition struct {
+	Extends string            `yaml:"extends" json:"extends"`
+	Secure  *bool             `yaml:"secure" json:"secure"` // inherited if not set
+	Local   bool              `yaml:"local" json:"local"`
+	URLs    map[string]string `yaml:"urls" json:"urls"`
+}
+
+// Load reads profiles from a .yaml, .yml or .json file.
+func Load(path string) (*Profiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var format string
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	case ".json":
+		format = "json"
+	default:
+		return nil, fmt.Errorf("%s: want a .yaml, .yml or .json file", path)
+	}
+	p, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return p, nil
+}
+
+// Parse decodes profiles in the given format, "yaml" or "json", and
+// checks every one of them.
+func Parse(data []byte, format string) (*Profiles, error) {
+	var f struct {
+		Profiles map[string]*definition `yaml:"profiles" json:"profiles"`
+	}
+	switch format {
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown profiles format %q", format)
+	}
+	p := &Profiles{defs: f.Profiles}
+	for name, d := range p.defs {
+		if d == nil {
+			p.defs[name] = new(definition)
+		}
+	}
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func mustParse(data []byte, format string) *Profiles {
+	p, err := Parse(data, format)
+	if err != nil {
+		panic("profiles.yaml: " + err.Error())
+	}
+	return p
+}
+
+// check reports every malformed profile.
+func (p *Profiles) check() error {
+	known := Names()
+	var errs []error
+	for _, name := range p.Names() {
+		d := p.defs[name]
+		for ep := range d.URLs {
+			if !slices.Contains(known, ep) {
+				errs = append(errs, fmt.Errorf("profile %s: unknown endpoint %q", name, ep))
+			}
+		}
+		if d.Local {
+			if d.Extends != "" || len(d.URLs) > 0 || d.Secure != nil {
+				errs = append(errs, fmt.Errorf("profile %s: a local profile cannot have extends, secure or urls", name))
+			}
+			continue
+		}
+		if _, err := p.chain(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, _, err := p.Resolve(name, Options{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Names returns the profiles' names, sorted.
+func (p *Profiles) Names() []string {
+	return slices.Sorted(maps.Keys(p.defs))
+}
+
+// IsLocal reports whether the named profile is a local one, whose
+// endpoints are servers a test starts.
+func (p *Profiles) IsLocal(name string) bool {
+	d := p.defs[name]
+	return d != nil && d.Local
+}
+
+// Describe summarizes how the named profile is defined, such as
+// "extends staging, insecure".
+func (p *Profiles) Describe(name string) string {
+	d := p.defs[name]
+	switch {
+	case d == nil:
+		return "unknown"
+	case p.IsLocal(name):
+		return "local"
+	}
+	var parts []string
+	if d.Extends != "" {
+		parts = append(parts, "extends "+d.Extends)
+	}
+	if d.Secure != nil && *d.Secure {
+		parts = append(parts, "secure")
+	} else if d.Secure != nil {
+		parts = append(parts, "insecure")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// chain returns the named profile and the ones it extends, nearest first.
+func (p *Profiles) chain(name string) ([]string, error) {
+	var chain []string
+	for n := name; n != ""; n = p.defs[n].Extends {
+		if slices.Contains(chain, n) {
+			return nil, fmt.Errorf("profile %s: extends itself through %s", name, strings.Join(chain, ", "))
+		}
+		if p.defs[n] == nil {
+			if n == name {
+				return nil, fmt.Errorf("unknown profile %q: have %s", name, strings.Join(p.Names(), ", "))
+			}
+			return nil, fmt.Errorf("profile %s: extends unknown profile %q", chain[len(chain)-1], n)
+		}
+		if p.defs[n].Local {
+			return nil, fmt.Errorf("profile %s: cannot extend local profile %s", chain[len(chain)-1], n)
+		}
+		chain = append(chain, n)
+	}
+	return chain, nil
+}
+
+// Options adjust the endpoints a profile resolves to.
+type Options struct {
+	// Getenv reads overrides, such as AUTH_URL for auth, which replace
+	// the profile's URLs and are checked like them. It is os.Getenv in a
+	// real program; nil means no overrides.
+	Getenv func(string) string
+	// Local gives the URL of each endpoint of a local profile, such as
+	// that of a test server. A local profile cannot be resolved without
+	// it.
+	Local func(endpoint string) string
+}
+
+// Resolve returns the endpoints of the named profile, with where each came
+// from. The error reports every endpoint that is missing or malformed.
+func (p *Profiles) Resolve(name string, opts Options) (Endpoints, Origins, error) {
+	var e Endpoints
+	origins := make(Origins)
+	secure := false
+	if p.IsLocal(name) {
+		if opts.Local == nil {
+			return e, nil, fmt.Errorf("profile %s: local endpoints need servers to point at", name)
+		}
+		for _, ep := range e.endpoints() {
+			*ep.ptr, origins[ep.name] = opts.Local(ep.name), "local"
+		}
+	} else {
+		chain, err := p.chain(name)
+		if err != nil {
+			return e, nil, err
+		}
+		// Apply the farthest ancestor first, so nearer profiles override it.
+		for _, n := range slices.Backward(chain) {
+			d := p.defs[n]
+			if d.Secure != nil {
+				secure = *d.Secure
+			}
+			for _, ep := range e.endpoints() {
+				if u, ok := d.URLs[ep.name]; ok {
+					*ep.ptr, origins[ep.name] = u, "profile "+n
+				}
+			}
+		}
+	}
+	if opts.Getenv != nil {
+		for _, ep := range e.endpoints() {
+			if u := opts.Getenv(EnvName(ep.name)); u != "" {
+				*ep.ptr, origins[ep.name] = u, "env "+EnvName(ep.name)
+			}
+		}
+	}
+
+	var errs []error
+	for _, ep := range e.endpoints() {
+		if err := checkURL(*ep.ptr, ep.socket, secure); err != nil {
+			source := origins[ep.name]
+			if source == "" {
+				source = "unset"
+			}
+			errs = append(errs, fmt.Errorf("profile %s: %s (%s): %v", name, ep.name, source, err))
+		}
+	}
+	return e, origins, errors.Join(errs...)
+}
+
+// checkURL reports what is wrong with an endpoint's URL.
+func checkURL(s string, socket, secure bool) error {
+	if s == "" {
+		return errors.New("is required")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	schemes := []string{"https", "http"}
+	if socket {
+		schemes = []string{"wss", "ws"}
+	}
+	if secure {
+		schemes = schemes[:1]
+	}
+	switch {
+	case !slices.Contains(schemes, u.Scheme):
+		return fmt.Errorf("%q must use %s", s, strings.Join(schemes, " or "))
+	case u.Host == "":
+		return fmt.Errorf("%q has no host", s)
+	case u.User != nil:
+		return fmt.Errorf("%q holds credentials, which belong in a secret", u.Redacted())
+	case u.RawQuery != "" || u.Fragment != "":
+		return fmt.Errorf("%q has a query or fragment", s)
+	}
+	return nil
+}
+
+// A Difference is an endpoint whose URL differs between two profiles.
+type Difference struct {
+	Endpoint string
+	A, B     string
+}
+
+// Diff returns the endpoints whose URLs differ, in the order profiles list
+// them.
+func Diff(a, b Endpoints) []Difference {
+	var out []Difference
+	for _, name := range Names() {
+		if ua, ub := a.URL(name), b.URL(name); ua != ub {
+			out = append(out, Difference{name, ua, ub})
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// An Allowlist is a set of client address ranges that are never limited.
+type Allowlist []netip.Prefix
+
+// ParseAllowlist parses CIDR ranges, such as 10.0.0.0/8, and single
+// addresses, which stand for themselves alone.
+func ParseAllowlist(entries ...string) (Allowlist, error) {
+	var a Allowlist
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if !strings.Contains(e, "/") {
+			addr, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, fmt.Errorf("allowlist: %v", err)
+			}
+			a = append(a, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(e)
+		if err != nil {
+			return nil, fmt.Errorf("allowlist: %v", err)
+		}
+		a = append(a, p.Masked())
+	}
+	return a, nil
+}
+
+// Contains reports whether addr is in one of the ranges. An IPv4 address
+// written as IPv6, ::ffff:10.0.0.1, counts as the IPv4 address.
+func (a Allowlist) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range a {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address the request came from, as the connection
+// reports it. Headers such as X-Forwarded-For are ignored: any client can
+// set them, so trusting them is only safe behind a proxy that overwrites
+// them, which Middleware.Key can be written for.
+func ClientIP(r *http.Request) (netip.Addr, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("client address %q: %v", r.RemoteAddr, err)
+	}
+	return addr.Unmap(), nil
+}
+
+// Middleware limits the requests an http.Handler serves.
+type Middleware struct {
+	Limiter *Limiter
+	// Key names whom a request is counted against; the client's address
+	// if nil. A request whose key is "" is not limited.
+	Key func(*http.Request) string
+	// Allow lists the client addresses that are never limited.
+	Allow Allowlist
+}
+
+// Wrap returns a handler that serves requests with next while the limiter
+// allows them, and answers the rest with 429 Too Many Requests and a
+// Retry-After header giving the seconds to wait.
+func (m Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, err := ClientIP(r)
+		if err == nil && m.Allow.Contains(addr) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := r.RemoteAddr
+		switch {
+		case m.Key != nil:
+			key = m.Key(r)
+		case err == nil:
+			key = addr.String()
+		}
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		d := m.Limiter.Allow(key)
+		if !d.Allowed {
+			secs := int(math.Ceil(d.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+			http.Error(w, fmt.Sprintf("rate limit of %v exceeded", d.Limit), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
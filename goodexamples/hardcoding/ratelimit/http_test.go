@@ -0,0 +1,105 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/ratelimit"
+)
+
+func TestMiddleware(t *testing.T) {
+	c := newClock()
+	allow, err := ratelimit.ParseAllowlist("10.0.0.50", " 172.16.0.0/12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ratelimit.Middleware{
+		Limiter: newLimiter(t, ratelimit.TokenBucket, c, ratelimit.Limit{Requests: 2, Per: 10 * time.Second}),
+		Allow:   allow,
+	}
+	h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := range 2 {
+		if w := serve("192.0.2.1:1234"); w.Code != http.StatusNoContent {
+			t.Errorf("request %d: %d, want 204", i+1, w.Code)
+		}
+	}
+	w := serve("192.0.2.1:5678") // another port of the same client
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "5" {
+		t.Errorf("third request: %d with Retry-After %q, want 429 with 5", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := serve("192.0.2.2:1234"); w.Code != http.StatusNoContent {
+		t.Errorf("another client: %d, want 204", w.Code)
+	}
+	for _, addr := range []string{"10.0.0.50:1", "172.20.1.1:1", "[::ffff:10.0.0.50]:1"} {
+		for range 5 {
+			if w := serve(addr); w.Code != http.StatusNoContent {
+				t.Errorf("allowlisted %s: %d, want 204", addr, w.Code)
+			}
+		}
+	}
+	c.Advance(5 * time.Second)
+	if w := serve("192.0.2.1:1234"); w.Code != http.StatusNoContent {
+		t.Errorf("after Retry-After: %d, want 204", w.Code)
+	}
+}
+
+func TestMiddlewareKey(t *testing.T) {
+	c := newClock()
+	m := ratelimit.Middleware{
+		Limiter: newLimiter(t, ratelimit.SlidingLog, c, ratelimit.Limit{Requests: 1, Per: time.Minute}),
+		Key:     func(r *http.Request) string { return r.Header.Get("X-User") },
+	}
+	h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(user string) int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+	if serve("alice") != http.StatusOK || serve("alice") != http.StatusTooManyRequests {
+		t.Error("alice's second request in a minute was not refused")
+	}
+	if serve("bob") != http.StatusOK {
+		t.Error("bob was refused for alice's requests")
+	}
+	for range 3 {
+		if serve("") != http.StatusOK {
+			t.Error("a request with no key was limited")
+		}
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	if _, err := ratelimit.ParseAllowlist("10.0.0.300"); err == nil {
+		t.Error("ParseAllowlist accepted 10.0.0.300")
+	}
+	a, err := ratelimit.ParseAllowlist("10.0.0.7/8", "", "2001:db8::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for addr, want := range map[string]bool{
+		"10.1.2.3":        true,
+		"::ffff:10.1.2.3": true,
+		"11.0.0.1":        false,
+		"2001:db8::1":     true,
+		"2001:db8::2":     false,
+	} {
+		if got := a.Contains(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("Contains(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
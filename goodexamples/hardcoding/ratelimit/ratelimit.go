@@ -0,0 +1,199 @@
+// Package ratelimit limits how often a key, such as a user or a client
+// address, may do something. It is what the Hard Coding example's
+// RateLimitCheck declared, 60 requests a minute, 1000 an hour and 10000 a
+// day, but never enforced.
+//
+// A Limiter enforces any number of limits at once, and a request is allowed
+// only if every limit allows it. It counts with one of two algorithms: a
+// token bucket per limit, which needs constant space per key and lets a
+// full bucket's worth of requests through in a burst, or a log of request
+// times, which is exact over any window but remembers up to the largest
+// limit's worth of times per key. Its state lives in a Store, in memory by
+// default, and it reads the time from a clock that tests can replace.
+// Middleware puts a Limiter in front of an http.Handler.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// A Limit allows Requests requests Per duration.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l Limit) String() string { return fmt.Sprintf("%d per %v", l.Requests, l.Per) }
+
+// An Algorithm is a way of counting requests against limits.
+type Algorithm int
+
+const (
+	// TokenBucket keeps a bucket of Requests tokens per limit, refilled
+	// at Requests per Per; a request takes a token from each.
+	TokenBucket Algorithm = iota
+	// SlidingLog keeps the times of the allowed requests and counts
+	// those in the last Per for each limit.
+	SlidingLog
+)
+
+// Options configure a Limiter.
+type Options struct {
+	Algorithm Algorithm
+	// Store keeps each key's state; a new MemoryStore if nil.
+	Store Store
+	// Now is the clock; time.Now if nil.
+	Now func() time.Time
+}
+
+// A Limiter decides whether a key may make another request. It is safe for
+// concurrent use.
+type Limiter struct {
+	limits  []Limit
+	longest time.Duration // the longest limit's period
+	alg     Algorithm
+	store   Store
+	now     func() time.Time
+	calls   atomic.Uint64
+}
+
+// New returns a limiter enforcing all of limits.
+func New(limits []Limit, opts Options) (*Limiter, error) {
+	if len(limits) == 0 {
+		return nil, errors.New("ratelimit: no limits")
+	}
+	for _, l := range limits {
+		if l.Requests <= 0 || l.Per <= 0 {
+			return nil, fmt.Errorf("ratelimit: limit %v: requests and period must be positive", l)
+		}
+	}
+	switch opts.Algorithm {
+	case TokenBucket, SlidingLog:
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown algorithm %d", opts.Algorithm)
+	}
+	l := &Limiter{limits: limits, alg: opts.Algorithm, store: opts.Store, now: opts.Now}
+	for _, lim := range limits {
+		l.longest = max(l.longest, lim.Per)
+	}
+	if l.store == nil {
+		l.store = NewMemoryStore(0)
+	}
+	if l.now == nil {
+		l.now = time.Now
+	}
+	return l, nil
+}
+
+// A Decision is the answer to one request.
+type Decision struct {
+	Allowed bool
+	// Remaining is how many more requests the tightest limit allows now.
+	Remaining int
+	// RetryAfter is how long a refused key must wait before every limit
+	// would allow it; zero if the request was allowed.
+	RetryAfter time.Duration
+	// Limit is the limit that refused the request, the one with the
+	// longest wait if several did.
+	Limit Limit
+}
+
+// Allow decides whether key may make a request now and, if so, counts it
+// against every limit. Refused requests are not counted. Every sweepEvery
+// calls, it also sweeps a Store that is a Sweeper of the keys idle for
+// longer than the longest limit.
+func (l *Limiter) Allow(key string) Decision {
+	now := l.now()
+	var d Decision
+	l.store.Update(key, func(s *State) {
+		if l.alg == SlidingLog {
+			d = l.slidingLog(s, now)
+		} else {
+			d = l.tokenBucket(s, now)
+		}
+		s.Updated = now
+	})
+	if sw, ok := l.store.(Sweeper); ok && l.calls.Add(1)%sweepEvery == 0 {
+		sw.Sweep(now.Add(-l.longest))
+	}
+	return d
+}
+
+func (l *Limiter) tokenBucket(s *State, now time.Time) Decision {
+	if len(s.Tokens) != len(l.limits) {
+		s.Tokens = make([]float64, len(l.limits))
+		for i, lim := range l.limits {
+			s.Tokens[i] = float64(lim.Requests)
+		}
+	} else if elapsed := now.Sub(s.Updated); elapsed > 0 {
+		for i, lim := range l.limits {
+			s.Tokens[i] = min(float64(lim.Requests), s.Tokens[i]+elapsed.Seconds()*rate(lim))
+		}
+	}
+	d := Decision{Allowed: true, Remaining: math.MaxInt}
+	for i, lim := range l.limits {
+		if s.Tokens[i] >= 1 {
+			continue
+		}
+		d.Allowed = false
+		if wait := seconds((1 - s.Tokens[i]) / rate(lim)); wait > d.RetryAfter {
+			d.RetryAfter, d.Limit = wait, lim
+		}
+	}
+	for i := range l.limits {
+		if d.Allowed {
+			s.Tokens[i]--
+		}
+		d.Remaining = min(d.Remaining, int(s.Tokens[i]))
+	}
+	return d
+}
+
+// rate is a limit's refill rate in tokens per second.
+func rate(l Limit) float64 { return float64(l.Requests) / l.Per.Seconds() }
+
+// seconds converts seconds to a Duration, rounding up so that waiting that
+// long is always enough.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+func (l *Limiter) slidingLog(s *State, now time.Time) Decision {
+	// Forget requests older than the longest window.
+	i := 0
+	for i < len(s.Log) && !s.Log[i].After(now.Add(-l.longest)) {
+		i++
+	}
+	s.Log = s.Log[i:]
+
+	d := Decision{Allowed: true, Remaining: math.MaxInt}
+	for _, lim := range l.limits {
+		// The log is in time order, so the requests in this window are
+		// a suffix of it.
+		start := len(s.Log)
+		for start > 0 && s.Log[start-1].After(now.Add(-lim.Per)) {
+			start--
+		}
+		count := len(s.Log) - start
+		if count < lim.Requests {
+			d.Remaining = min(d.Remaining, lim.Requests-count-1)
+			continue
+		}
+		// The window has room again once the Requests-th newest
+		// request has left it.
+		d.Allowed = false
+		if wait := s.Log[len(s.Log)-lim.Requests].Add(lim.Per).Sub(now); wait > d.RetryAfter {
+			d.RetryAfter, d.Limit = wait, lim
+		}
+	}
+	if d.Allowed {
+		s.Log = append(s.Log, now)
+	} else {
+		d.Remaining = 0
+	}
+	return d
+}
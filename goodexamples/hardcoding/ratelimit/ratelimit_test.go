@@ -0,0 +1,141 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bclements/antipatterns/goodexamples/hardcoding/ratelimit"
+)
+
+// clock is a fake clock that only moves when told.
+type clock struct{ t time.Time }
+
+func newClock() *clock { return &clock{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)} }
+
+func (c *clock) Now() time.Time          { return c.t }
+func (c *clock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newLimiter(t *testing.T, alg ratelimit.Algorithm, c *clock, limits ...ratelimit.Limit) *ratelimit.Limiter {
+	t.Helper()
+	l, err := ratelimit.New(limits, ratelimit.Options{Algorithm: alg, Now: c.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// allowN makes n requests for key and returns how many were allowed.
+func allowN(l *ratelimit.Limiter, key string, n int) int {
+	allowed := 0
+	for range n {
+		if l.Allow(key).Allowed {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestTokenBucket(t *testing.T) {
+	c := newClock()
+	perMinute := ratelimit.Limit{Requests: 60, Per: time.Minute}
+	l := newLimiter(t, ratelimit.TokenBucket, c, perMinute)
+
+	// A full bucket lets a burst through.
+	if d := l.Allow("alice"); !d.Allowed || d.Remaining != 59 {
+		t.Errorf("first request: %+v, want allowed with 59 remaining", d)
+	}
+	if n := allowN(l, "alice", 100); n != 59 {
+		t.Errorf("%d of a burst of 100 allowed, want 59", n)
+	}
+	d := l.Allow("alice")
+	if d.Allowed || d.RetryAfter != time.Second || d.Limit != perMinute {
+		t.Errorf("request on an empty bucket: %+v, want refused for 1s by %v", d, perMinute)
+	}
+	if !l.Allow("bob").Allowed {
+		t.Error("bob refused for alice's requests")
+	}
+
+	// It refills at one token a second, up to its size.
+	c.Advance(1500 * time.Millisecond)
+	if n := allowN(l, "alice", 5); n != 1 {
+		t.Errorf("%d allowed after 1.5s, want 1", n)
+	}
+	c.Advance(time.Hour)
+	if n := allowN(l, "alice", 100); n != 60 {
+		t.Errorf("%d allowed after an hour, want a full bucket of 60", n)
+	}
+}
+
+func TestTokenBucketLimits(t *testing.T) {
+	c := newClock()
+	perSecond := ratelimit.Limit{Requests: 2, Per: time.Second}
+	perMinute := ratelimit.Limit{Requests: 5, Per: time.Minute}
+	l := newLimiter(t, ratelimit.TokenBucket, c, perSecond, perMinute)
+
+	if n := allowN(l, "k", 3); n != 2 {
+		t.Errorf("%d allowed in the first second, want 2", n)
+	}
+	c.Advance(time.Second)
+	if n := allowN(l, "k", 3); n != 2 {
+		t.Errorf("%d allowed in the second, want 2", n)
+	}
+	c.Advance(time.Second)
+	d := l.Allow("k")
+	if !d.Allowed || d.Remaining != 0 {
+		t.Errorf("fifth request: %+v, want allowed with 0 remaining", d)
+	}
+	c.Advance(time.Second)
+	d = l.Allow("k")
+	// The per-minute bucket refills 1/12 of a token a second, so over
+	// the 3s it holds a quarter of one, and three quarters take 9s more.
+	if d.Allowed || d.Limit != perMinute || d.RetryAfter != 9*time.Second {
+		t.Errorf("sixth request: %+v, want refused for 9s by %v", d, perMinute)
+	}
+}
+
+func TestSlidingLog(t *testing.T) {
+	c := newClock()
+	perMinute := ratelimit.Limit{Requests: 3, Per: time.Minute}
+	l := newLimiter(t, ratelimit.SlidingLog, c, perMinute)
+
+	for i := range 3 {
+		if d := l.Allow("k"); !d.Allowed || d.Remaining != 2-i {
+			t.Errorf("request %d: %+v, want allowed with %d remaining", i+1, d, 2-i)
+		}
+		c.Advance(10 * time.Second)
+	}
+	// 30s in: the first request leaves the window at 60s.
+	d := l.Allow("k")
+	if d.Allowed || d.RetryAfter != 30*time.Second || d.Limit != perMinute {
+		t.Errorf("fourth request: %+v, want refused for 30s", d)
+	}
+	c.Advance(30*time.Second - time.Nanosecond)
+	if l.Allow("k").Allowed {
+		t.Error("allowed before the first request left the window")
+	}
+	c.Advance(time.Nanosecond)
+	if !l.Allow("k").Allowed {
+		t.Error("refused once the first request left the window")
+	}
+	// Refused requests were not counted: the next slot opens when the
+	// second request leaves, at 70s.
+	if d := l.Allow("k"); d.Allowed || d.RetryAfter != 10*time.Second {
+		t.Errorf("request at 60s: %+v, want refused for 10s", d)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, test := range []struct {
+		limits []ratelimit.Limit
+		opts   ratelimit.Options
+	}{
+		{nil, ratelimit.Options{}},
+		{[]ratelimit.Limit{{Requests: 0, Per: time.Second}}, ratelimit.Options{}},
+		{[]ratelimit.Limit{{Requests: 1, Per: 0}}, ratelimit.Options{}},
+		{[]ratelimit.Limit{{Requests: 1, Per: time.Second}}, ratelimit.Options{Algorithm: 7}},
+	} {
+		if _, err := ratelimit.New(test.limits, test.opts); err == nil {
+			t.Errorf("New(%v, %+v) succeeded", test.limits, test.opts)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// State is what a Limiter remembers about one key. A Store may keep it
+// anywhere, so it is plain data.
+type State struct {
+	Tokens  []float64   // TokenBucket: the tokens left in each limit's bucket
+	Log     []time.Time // SlidingLog: the allowed requests' times, oldest first
+	Updated time.Time   // the last request
+}
+
+// A Store keeps each key's State.
+type Store interface {
+	// Update calls fn with the key's state, a zero State if it has none,
+	// and keeps the state fn leaves. Updates of one key must not run at
+	// the same time.
+	Update(key string, fn func(*State))
+}
+
+// defaultShards is the number of shards of a MemoryStore that is not told
+// how many to use.
+const defaultShards = 64
+
+// A MemoryStore keeps state in memory, spread over shards that each have
+// their own lock, so that requests for different keys rarely wait for one
+// another.
+type MemoryStore struct {
+	seed   maphash.Seed
+	shards []shard
+}
+
+type shard struct {
+	mu     sync.Mutex
+	states map[string]*State
+}
+
+// NewMemoryStore returns an empty store of n shards, or of a default number
+// if n is not positive.
+func NewMemoryStore(n int) *MemoryStore {
+	if n <= 0 {
+		n = defaultShards
+	}
+	m := &MemoryStore{seed: maphash.MakeSeed(), shards: make([]shard, n)}
+	for i := range m.shards {
+		m.shards[i].states = make(map[string]*State)
+	}
+	return m
+}
+
+func (m *MemoryStore) shard(key string) *shard {
+	return &m.shards[maphash.String(m.seed, key)%uint64(len(m.shards))]
+}
+
+func (m *MemoryStore) Update(key string, fn func(*State)) {
+	sh := m.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	s := sh.states[key]
+	if s == nil {
+		s = new(State)
+		sh.states[key] = s
+	}
+	fn(s)
+}
+
+// A Sweeper is a Store that can forget idle keys. A Limiter whose Store is
+// a Sweeper calls Sweep every sweepEvery requests, with the current time
+// less the longest limit's period, so that memory stays bounded by the
+// keys active within one window.
+type Sweeper interface {
+	Store
+	// Sweep forgets the keys with no request since before.
+	Sweep(before time.Time)
+}
+
+// sweepEvery is how many requests a Limiter serves between sweeps.
+const sweepEvery = 1024
+
+// Sweep forgets the keys with no request since before, which a limiter
+// treats the same as keys it has never seen once every window has passed.
+func (m *MemoryStore) Sweep(before time.Time) {
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.mu.Lock()
+		for key, s := range sh.states {
+			if s.Updated.Before(before) {
+				delete(sh.states, key)
+			}
+		}
+		sh.mu.Unlock()
+	}
+}
+
+// Len returns the number of keys the store holds.
+func (m *MemoryStore) Len() int {
+	n := 0
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.mu.Lock()
+		n += len(sh.states)
+		sh.mu.Unlock()
+	}
+	return n
+}
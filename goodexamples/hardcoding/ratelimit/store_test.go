@@ -0,0 +1,34 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestSweep checks that a Limiter sweeps its MemoryStore of the keys idle
+// for longer than its longest limit, and only those.
+func TestSweep(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(4)
+	l, err := New([]Limit{{Requests: 10, Per: time.Second}, {Requests: 100, Per: time.Hour}}, Options{
+		Store: store,
+		Now:   func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range sweepEvery - 2 {
+		l.Allow(fmt.Sprint("idle", i))
+	}
+	now = now.Add(time.Hour)
+	l.Allow("recent")
+	if n := store.Len(); n != sweepEvery-1 {
+		t.Fatalf("%d keys before the sweep, want %d", n, sweepEvery-1)
+	}
+	now = now.Add(time.Nanosecond)
+	l.Allow("active") // the sweepEvery'th request
+	if n := store.Len(); n != 2 {
+		t.Errorf("%d keys after the sweep, want recent and active", n)
+	}
+}
@@ -0,0 +1,138 @@
+package hardcoding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// redacted is what a Secret prints as.
+const redacted = "[redacted]"
+
+// A Secret is a credential. However it is formatted, logged or encoded as
+// JSON it reads as [redacted]; only Reveal returns the value, so a secret
+// cannot reach a log line or an error message by accident.
+type Secret struct {
+	value string
+}
+
+// NewSecret wraps a credential.
+func NewSecret(value string) Secret { return Secret{value} }
+
+// Reveal returns the credential, for handing to whatever needs it.
+func (s Secret) Reveal() string { return s.value }
+
+// IsZero reports whether the secret is empty.
+func (s Secret) IsZero() bool { return s.value == "" }
+
+func (Secret) String() string   { return redacted }
+func (Secret) GoString() string { return redacted }
+
+// Format prints [redacted] for every verb, so that %d or %x cannot print
+// the value either.
+func (Secret) Format(f fmt.State, verb rune) { io.WriteString(f, redacted) }
+
+func (Secret) MarshalJSON() ([]byte, error) { return json.Marshal(redacted) }
+
+// ErrSecretNotFound is returned by a SecretProvider that has no secret of
+// the name asked for.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// A SecretProvider looks secrets up by name, such as DB_PASSWORD.
+type SecretProvider interface {
+	Secret(ctx context.Context, name string) (Secret, error)
+}
+
+// EnvSecrets reads secrets from environment variables through a getenv
+// function such as os.Getenv. An empty variable is not found.
+type EnvSecrets func(string) string
+
+func (e EnvSecrets) Secret(_ context.Context, name string) (Secret, error) {
+	v := e(name)
+	if v == "" {
+		return Secret{}, fmt.Errorf("%w: env %s", ErrSecretNotFound, name)
+	}
+	return NewSecret(v), nil
+}
+
+// FileSecrets reads secrets from one file each in Dir, named after the
+// secret, the way Docker and Kubernetes mount them. A trailing newline is
+// not part of the secret.
+type FileSecrets struct {
+	Dir string
+}
+
+func (f FileSecrets) Secret(_ context.Context, name string) (Secret, error) {
+	if !filepath.IsLocal(name) {
+		return Secret{}, fmt.Errorf("secret name %q is not a file name", name)
+	}
+	path := filepath.Join(f.Dir, name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Secret{}, fmt.Errorf("%w: %s", ErrSecretNotFound, path)
+	}
+	if err != nil {
+		return Secret{}, err
+	}
+	return NewSecret(strings.TrimRight(string(data), "\r\n")), nil
+}
+
+// HTTPSecrets reads secrets from a Vault-style key-value store over HTTP:
+// the secret name is a path under Mount, and the secret is the "value"
+// field of its data.
+type HTTPSecrets struct {
+	Addr   string // e.g. https://vault.internal:8200
+	Mount  string // the key-value engine's mount; "secret" if empty
+	Token  Secret
+	Client *http.Client // http.DefaultClient if nil
+}
+
+func (h *HTTPSecrets) Secret(ctx context.Context, name string) (Secret, error) {
+	mount := h.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	u := strings.TrimSuffix(h.Addr, "/") + "/v1/" + mount + "/data/" + url.PathEscape(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Secret{}, err
+	}
+	req.Header.Set("X-Vault-Token", h.Token.Reveal())
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return Secret{}, fmt.Errorf("%w: %s", ErrSecretNotFound, u)
+	default:
+		return Secret{}, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return Secret{}, fmt.Errorf("%s: %v", u, err)
+	}
+	v, ok := body.Data.Data["value"]
+	if !ok {
+		return Secret{}, fmt.Errorf("%s: no value field", u)
+	}
+	return NewSecret(v), nil
+}
@@ -0,0 +1,135 @@
+package hardcoding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// vaultStandIn serves secrets the way HTTPSecrets reads them, to requests
+// carrying the token.
+func vaultStandIn(token string, secrets map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		name, ok := strings.CutPrefix(r.URL.Path, "/v1/secret/data/")
+		v, found := secrets[name]
+		if !ok || !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]string{"value": v}}})
+	})
+}
+
+func TestHTTPSecrets(t *testing.T) {
+	vault := httptest.NewServer(vaultStandIn("test-token", map[string]string{"DB_PASSWORD": "fixture"}))
+	defer vault.Close()
+	ctx := context.Background()
+
+	h := &HTTPSecrets{Addr: vault.URL, Token: NewSecret("test-token"), Client: vault.Client()}
+	s, err := h.Secret(ctx, "DB_PASSWORD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Reveal() != "fixture" {
+		t.Errorf("DB_PASSWORD = %q, want fixture", s.Reveal())
+	}
+
+	_, err = h.Secret(ctx, "API_KEY")
+	if !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("missing key: %v, want ErrSecretNotFound", err)
+	}
+
+	bad := &HTTPSecrets{Addr: vault.URL, Token: NewSecret("wrong-token"), Client: vault.Client()}
+	_, err = bad.Secret(ctx, "DB_PASSWORD")
+	if err == nil || errors.Is(err, ErrSecretNotFound) || !strings.Contains(err.Error(), "403") {
+		t.Errorf("wrong token: %v, want a 403 error", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "wrong-token") {
+		t.Errorf("error %q shows the token", err)
+	}
+}
+
+func TestHTTPSecretsMalformed(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"data": {"other": "x"}}}`)
+	}))
+	defer vault.Close()
+	h := &HTTPSecrets{Addr: vault.URL, Client: vault.Client()}
+	if _, err := h.Secret(context.Background(), "DB_PASSWORD"); err == nil || !strings.Contains(err.Error(), "no value field") {
+		t.Errorf("response without a value: %v", err)
+	}
+}
+
+func TestEnvSecrets(t *testing.T) {
+	env := EnvSecrets(func(name string) string {
+		if name == "API_KEY" {
+			return name
+		}
+		return ""
+	})
+	if s, err := env.Secret(context.Background(), "API_KEY"); err != nil || s.Reveal() != "API_KEY" {
+		t.Errorf("API_KEY = %q, %v", s.Reveal(), err)
+	}
+	if _, err := env.Secret(context.Background(), "DB_PASSWORD"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("unset variable: %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "DB_PASSWORD"), []byte("fixture\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f := FileSecrets{Dir: dir}
+	ctx := context.Background()
+	if s, err := f.Secret(ctx, "DB_PASSWORD"); err != nil || s.Reveal() != "fixture" {
+		t.Errorf("DB_PASSWORD = %q, %v, want fixture without the newline", s.Reveal(), err)
+	}
+	if _, err := f.Secret(ctx, "API_KEY"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("missing file: %v, want ErrSecretNotFound", err)
+	}
+	if _, err := f.Secret(ctx, "../DB_PASSWORD"); err == nil || errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("name outside the directory: %v, want an error", err)
+	}
+}
+
+func TestSecretRedacted(t *testing.T) {
+	s := NewSecret("fixture")
+	if s.String() != redacted || s.GoString() != redacted {
+		t.Errorf("String = %q, GoString = %q", s.String(), s.GoString())
+	}
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q", "%x", "%X", "%d", "%10s"} {
+		if got := fmt.Sprintf(format, s); got != redacted {
+			t.Errorf("Sprintf(%q) = %q", format, got)
+		}
+	}
+	wrapped := struct {
+		Password Secret
+		Ptr      *Secret
+	}{s, &s}
+	if got := fmt.Sprintf("%+v %#v", wrapped, wrapped); strings.Contains(got, "fixture") {
+		t.Errorf("struct printed as %s", got)
+	}
+	data, err := json.Marshal(wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Password":"[redacted]","Ptr":"[redacted]"}`; string(data) != want {
+		t.Errorf("MarshalJSON = %s, want %s", data, want)
+	}
+	if s.Reveal() != "fixture" {
+		t.Errorf("Reveal = %q", s.Reveal())
+	}
+}
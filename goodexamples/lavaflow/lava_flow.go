@@ -0,0 +1,42 @@
+// Package lavaflow is the Lava Flow example with the hardened lava chipped
+// away. Every path that could never run, every flag that was always false
+// and every global nobody read is gone; what remains is the code that
+// produced the example's output, and the one helper that was still used,
+// with its rule written down.
+package lavaflow
+
+import (
+	"fmt"
+	"io"
+)
+
+// Double returns each value of data times two. This was
+// DataProcessor.ProcessData; its legacyMode and useOldAlgorithm branches
+// were never taken.
+func Double(data []int) []int {
+	out := make([]int, len(data))
+	for i, v := range data {
+		out[i] = v * 2
+	}
+	return out
+}
+
+// privateKeys are the fields PublicFields leaves out.
+var privateKeys = map[string]bool{"password": true, "secret": true}
+
+// PublicFields returns a copy of user without its password and secret.
+// Of the four versions that were kept in comments, only this one ever ran.
+func PublicFields(user map[string]any) map[string]any {
+	out := make(map[string]any, len(user))
+	for k, v := range user {
+		if !privateKeys[k] {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	fmt.Fprintf(w, "Processed data: %v\n", Double([]int{1, 2, 3, 4, 5}))
+}
@@ -0,0 +1,309 @@
+// Package leakyabstractions is the Leaky Abstractions example with the
+// leaks plugged, or, where they cannot be, documented as part of the
+// contract. Run prints the same sections as the original. Two lines
+// differ, and each difference is a leak gone: the reader returns all of
+// its input whatever its buffer size, and the work queue runs every task
+// instead of refusing one when its buffer is full.
+package leakyabstractions
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================================================================
+// Example 1: Errors belong to the interface, not the implementation
+// ==============================================================================
+
+// ErrUnavailable is returned, wrapped in a *CacheError, when the cache
+// cannot be reached. Callers test for it with errors.Is; which backend is
+// unavailable, and why, is not their concern.
+var ErrUnavailable = errors.New("cache unavailable")
+
+// Cache stores strings by key.
+type Cache interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// CacheError records a failed cache operation.
+type CacheError struct {
+	Op  string
+	Err error
+}
+
+func (e *CacheError) Error() string {
+	return fmt.Sprintf("cache %s: %v", e.Op, e.Err)
+}
+
+func (e *CacheError) Unwrap() error {
+	return e.Err
+}
+
+// RedisCache is a Cache in Redis. It retries an exhausted connection pool
+// itself, since only it knows that the condition is temporary.
+type RedisCache struct {
+	connected bool
+	log       io.Writer
+}
+
+// errPoolExhausted is Redis's own error. It never leaves RedisCache.
+var errPoolExhausted = errors.New("redis: connection pool exhausted")
+
+// NewRedisCache returns a cache that logs its recoveries to log.
+func NewRedisCache(log io.Writer) *RedisCache {
+	return &RedisCache{log: log}
+}
+
+func (r *RedisCache) Get(key string) (string, error) {
+	if !r.connected {
+		return "", &CacheError{Op: "get", Err: ErrUnavailable}
+	}
+	return "value", nil
+}
+
+func (r *RedisCache) Set(key, value string) error {
+	err := r.set(key, value)
+	if err == errPoolExhausted {
+		fmt.Fprintln(r.log, "Redis pool exhausted - retry logic")
+		err = r.set(key, value)
+	}
+	if err != nil {
+		return &CacheError{Op: "set", Err: ErrUnavailable}
+	}
+	return nil
+}
+
+func (r *RedisCache) set(key, value string) error {
+	if !r.connected {
+		return errPoolExhausted
+	}
+	return nil
+}
+
+func (r *RedisCache) Delete(key string) error {
+	return nil
+}
+
+// ProcessData stores a value, knowing only what Cache promises.
+func ProcessData(cache Cache) error {
+	if err := cache.Set("key", "value"); err != nil {
+		return fmt.Errorf("process data: %w", err)
+	}
+	return nil
+}
+
+// ==============================================================================
+// Example 2: Buffering is the reader's business
+// ==============================================================================
+
+// DataReader reads text, choosing its own buffer.
+type DataReader struct {
+	r *bufio.Reader
+}
+
+// NewDataReader returns a reader of source.
+func NewDataReader(source io.Reader) *DataReader {
+	return &DataReader{r: bufio.NewReader(source)}
+}
+
+// ReadNext returns the next line, without its newline, or io.EOF when
+// there are no more.
+func (dr *DataReader) ReadNext() (string, error) {
+	line, err := dr.r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSuffix(line, "\n"), err
+}
+
+// ==============================================================================
+// Example 3: A pool is a cache, not a promise
+// ==============================================================================
+
+// Buffer is a scratch buffer worth reusing.
+type Buffer struct {
+	Data [1024]byte
+}
+
+var buffers = sync.Pool{New: func() any { return new(Buffer) }}
+
+// WithBuffer calls f with a zeroed scratch buffer. Whether the buffer is
+// new or reused is invisible to f, so nothing can come to depend on it.
+func WithBuffer(f func(*Buffer)) {
+	b := buffers.Get().(*Buffer)
+	*b = Buffer{}
+	defer buffers.Put(b)
+	f(b)
+}
+
+// ==============================================================================
+// Example 5: A queue that does not depend on its buffer size
+// ==============================================================================
+
+// WorkQueue runs tasks on a fixed number of workers.
+type WorkQueue struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+}
+
+// NewWorkQueue starts a queue with the given number of workers.
+func NewWorkQueue(workers int) *WorkQueue {
+	q := &WorkQueue{tasks: make(chan func())}
+	for range workers {
+		q.wg.Go(func() {
+			for task := range q.tasks {
+				task()
+			}
+		})
+	}
+	return q
+}
+
+// Submit hands task to a worker, waiting for one to be free. It fails only
+// if ctx is done first.
+func (q *WorkQueue) Submit(ctx context.Context, task func()) error {
+	select {
+	case q.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting tasks and waits for the submitted ones to finish.
+func (q *WorkQueue) Close() {
+	close(q.tasks)
+	q.wg.Wait()
+}
+
+// ==============================================================================
+// Example 6: Cancellation as a documented contract
+// ==============================================================================
+
+// Service handles requests.
+type Service struct {
+	name string
+	out  io.Writer
+}
+
+// ProcessRequest handles request, taking about 100ms. If ctx is done
+// first it returns ctx.Err(), so callers can tell a deadline from a
+// cancellation with errors.Is.
+func (s *Service) ProcessRequest(ctx context.Context, request string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(100 * time.Millisecond):
+		fmt.Fprintf(s.out, "[%s] Processed: %s\n", s.name, request)
+		return nil
+	}
+}
+
+// ==============================================================================
+// Example 7: Transactions that cannot be left open
+// ==============================================================================
+
+// DB is a database.
+type DB struct {
+	out io.Writer
+}
+
+// Tx is a transaction, valid only inside the function passed to InTx.
+type Tx struct {
+	db *DB
+}
+
+// Exec runs a statement in the transaction.
+func (tx *Tx) Exec(query string) error {
+	fmt.Fprintf(tx.db.out, "[DB] Query: %s (in_transaction: %v)\n", query, true)
+	return nil
+}
+
+// InTx runs f in a transaction, committing if it returns nil and rolling
+// back if it returns an error or panics. Callers cannot forget either.
+func (db *DB) InTx(f func(*Tx) error) (err error) {
+	tx := &Tx{db: db}
+	defer func() {
+		if p := recover(); p != nil {
+			db.rollback(tx)
+			panic(p)
+		}
+		if err != nil {
+			db.rollback(tx)
+		}
+	}()
+	if err := f(tx); err != nil {
+		return err
+	}
+	return db.commit(tx)
+}
+
+func (db *DB) commit(*Tx) error { return nil }
+func (db *DB) rollback(*Tx)     {}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	rule := strings.Repeat("=", 80)
+	section := func(title string) {
+		fmt.Fprintln(w, title)
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+	}
+
+	fmt.Fprintln(w, rule)
+	fmt.Fprintln(w, "LEAKY ABSTRACTIONS ANTI-PATTERN")
+	fmt.Fprintln(w, rule)
+	fmt.Fprintln(w)
+
+	section("Example 1: Interface Leaking Through Error Types")
+	_ = ProcessData(NewRedisCache(w))
+	fmt.Fprintln(w)
+
+	section("Example 2: io.Reader Leaking Buffer Management")
+	line, _ := NewDataReader(strings.NewReader("hello world")).ReadNext()
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w)
+
+	section("Example 3: sync.Pool Leaking Memory Semantics")
+	WithBuffer(func(*Buffer) {})
+	fmt.Fprintln(w)
+
+	section("Example 5: Channel Buffering Leaking Goroutine Behavior")
+	q := NewWorkQueue(1)
+	for _, name := range []string{"Task 1", "Task 2"} {
+		_ = q.Submit(context.Background(), func() { fmt.Fprintln(w, name) })
+	}
+	q.Close()
+	fmt.Fprintln(w)
+
+	section("Example 6: context.Context Leaking Cancellation")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	switch err := (&Service{name: "UserService", out: w}).ProcessRequest(ctx, "data"); {
+	case errors.Is(err, context.DeadlineExceeded):
+		fmt.Fprintf(w, "Error: %v\n", err) // our deadline, not the service's fault
+	case err != nil:
+		fmt.Fprintf(w, "Error: UserService failed: %v\n", err)
+	}
+	fmt.Fprintln(w)
+
+	section("Example 7: Database Leaking Transaction Semantics")
+	_ = (&DB{out: w}).InTx(func(tx *Tx) error {
+		return tx.Exec("UPDATE users SET balance = balance - 100 WHERE id = 1")
+	})
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, rule)
+	fmt.Fprintln(w, "KEY INSIGHT: All abstractions leak to some degree.")
+	fmt.Fprintln(w, "Good abstractions leak gracefully and document what leaks.")
+	fmt.Fprintln(w, "Bad ones force you to understand implementation details to use")
+	fmt.Fprintln(w, "them correctly, defeating the purpose of abstraction.")
+	fmt.Fprintln(w, rule)
+}
@@ -0,0 +1,95 @@
+// Package reinventingthewheel is the Reinventing the Wheel example with
+// each home-made wheel replaced by the standard library's. Most of the
+// functions shrink to a call, and the edge cases the originals missed -
+// quoted CSV fields, display names in addresses, URL ports and queries -
+// come for free.
+package reinventingthewheel
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ParseJSON decodes a JSON object.
+func ParseJSON(s string) (map[string]any, error) {
+	var m map[string]any
+	err := json.Unmarshal([]byte(s), &m)
+	return m, err
+}
+
+// ParseCSV returns the records of a CSV document.
+func ParseCSV(s string) ([][]string, error) {
+	return csv.NewReader(strings.NewReader(s)).ReadAll()
+}
+
+// FormatDate formats a date as e.g. "Jan 2, 2006".
+func FormatDate(year int, month time.Month, day int) string {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Format("Jan 2, 2006")
+}
+
+// Sorted returns a sorted copy of s.
+func Sorted(s []int) []int {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s
+}
+
+// ValidateEmail reports whether s is an RFC 5322 address.
+func ValidateEmail(s string) bool {
+	_, err := mail.ParseAddress(s)
+	return err == nil
+}
+
+// Render executes a text/template against data.
+func Render(w io.Writer, tmpl string, data any) error {
+	t, err := template.New("").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
+
+// ParseURL parses a URL; its Scheme, Host and Path replace the original's
+// "protocol", "domain" and "path".
+func ParseURL(s string) (*url.URL, error) {
+	return url.Parse(s)
+}
+
+// Hash returns the SHA-256 digest of s.
+func Hash(s string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(s))
+}
+
+// RandomString returns a random URL-safe string of n bytes of entropy from
+// a cryptographic source.
+func RandomString(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Encode returns data in standard base64.
+func Encode(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// Wrap adds context to err, keeping it visible to errors.Is and errors.As.
+func Wrap(message string, err error) error {
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	fmt.Fprintln(w, "Don't reinvent the wheel - use existing libraries!")
+}
@@ -0,0 +1,293 @@
+package spaghetticode
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed pricing.yaml
+var defaultRules []byte
+
+// DefaultRules are the rules in pricing.yaml, which ProcessOrder uses.
+var DefaultRules = mustParseRules(defaultRules, "yaml")
+
+// Rules price orders. Each table is searched in order and only the first
+// rule whose conditions all hold applies; see pricing.yaml for the format.
+type Rules struct {
+	// Pricing sets the status and the total before shipping, or rejects
+	// the order.
+	Pricing []Rule `yaml:"pricing" json:"pricing"`
+	// Shipping adds a surcharge to orders it matches.
+	Shipping []Rule `yaml:"shipping" json:"shipping"`
+}
+
+// A Rule is an action and the conditions under which it applies.
+type Rule struct {
+	Name string    `yaml:"name" json:"name"`
+	When Condition `yaml:"when" json:"when"`
+	Then Action    `yaml:"then" json:"then"`
+}
+
+// A Condition is met when every field that is set holds. String lists
+// match any of their values, "" matching an empty one.
+type Condition struct {
+	User     []string `yaml:"user,omitempty" json:"user,omitempty"`
+	Payment  []string `yaml:"payment,omitempty" json:"payment,omitempty"`
+	Code     []string `yaml:"code,omitempty" json:"code,omitempty"`
+	Shipping []string `yaml:"shipping,omitempty" json:"shipping,omitempty"`
+	Status   []string `yaml:"status,omitempty" json:"status,omitempty"`
+	Items    *Bound   `yaml:"items,omitempty" json:"items,omitempty"`
+	Subtotal *Bound   `yaml:"subtotal,omitempty" json:"subtotal,omitempty"`
+	Total    *Bound   `yaml:"total,omitempty" json:"total,omitempty"`
+}
+
+// A Bound is a range of numbers, open below and closed above.
+type Bound struct {
+	Over   *float64 `yaml:"over,omitempty" json:"over,omitempty"`
+	AtMost *float64 `yaml:"at_most,omitempty" json:"at_most,omitempty"`
+}
+
+// An Action is what a matching rule does: take Discount off the total,
+// then add Surcharge, then set Status. A rule with Reject set refuses the
+// order instead, for that reason.
+type Action struct {
+	Discount  float64 `yaml:"discount,omitempty" json:"discount,omitempty"`
+	Surcharge float64 `yaml:"surcharge,omitempty" json:"surcharge,omitempty"`
+	Status    string  `yaml:"status,omitempty" json:"status,omitempty"`
+	Reject    string  `yaml:"reject,omitempty" json:"reject,omitempty"`
+}
+
+// A Quote is the outcome of pricing an order.
+type Quote struct {
+	Total  float64
+	Status string
+	// Rejected says why the order was refused, or is "" if it was not.
+	Rejected string
+	// Applied names the rules that matched, in order.
+	Applied []string
+}
+
+// LoadRules reads rules from a .yaml, .yml or .json file.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var format string
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	case ".json":
+		format = "json"
+	default:
+		return nil, fmt.Errorf("%s: want a .yaml, .yml or .json file", path)
+	}
+	r, err := ParseRules(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return r, nil
+}
+
+// ParseRules decodes rules in the given format, "yaml" or "json", and
+// checks them. Unknown fields are errors, so a misspelled condition
+// cannot silently match everything.
+func ParseRules(data []byte, format string) (*Rules, error) {
+	r := new(Rules)
+	switch format {
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(r); err != nil {
+			return nil, err
+		}
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown rules format %q", format)
+	}
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func mustParseRules(data []byte, format string) *Rules {
+	r, err := ParseRules(data, format)
+	if err != nil {
+		panic("pricing.yaml: " + err.Error())
+	}
+	return r
+}
+
+// check reports every malformed rule.
+func (r *Rules) check() error {
+	var errs []error
+	for i, rule := range r.Pricing {
+		errs = append(errs, checkPricing(i, rule))
+	}
+	for i, rule := range r.Shipping {
+		errs = append(errs, checkShipping(i, rule))
+	}
+	return errors.Join(errs...)
+}
+
+// checkPricing reports what is wrong with the i'th pricing rule: anything
+// checkRule finds, or that it neither rejects the order nor sets a status.
+func checkPricing(i int, rule Rule) error {
+	where := describe("pricing", i, rule)
+	if err := checkRule(rule); err != nil {
+		return fmt.Errorf("%s: %v", where, err)
+	}
+	if rule.Then.Reject == "" && rule.Then.Status == "" {
+		return fmt.Errorf("%s: sets no status", where)
+	}
+	return nil
+}
+
+// checkShipping reports what is wrong with the i'th shipping rule:
+// anything checkRule finds, or that it does more than add a surcharge.
+func checkShipping(i int, rule Rule) error {
+	where := describe("shipping", i, rule)
+	if err := checkRule(rule); err != nil {
+		return fmt.Errorf("%s: %v", where, err)
+	}
+	if a := rule.Then; a.Reject != "" || a.Status != "" || a.Discount != 0 {
+		return fmt.Errorf("%s: shipping rules only add surcharges", where)
+	}
+	return nil
+}
+
+// checkRule reports what is wrong with a rule of either table.
+func checkRule(rule Rule) error {
+	a := rule.Then
+	switch {
+	case rule.Name == "":
+		return errors.New("no name")
+	case a.Discount < 0 || a.Discount >= 1:
+		return fmt.Errorf("discount %g is not a fraction in [0, 1)", a.Discount)
+	case a.Reject != "" && (a.Status != "" || a.Discount != 0 || a.Surcharge != 0):
+		return errors.New("a rejecting rule cannot also price the order")
+	}
+	return nil
+}
+
+// describe names the i'th rule of a table for errors.
+func describe(table string, i int, rule Rule) string {
+	where := fmt.Sprintf("%s rule %d", table, i+1)
+	if rule.Name != "" {
+		where += fmt.Sprintf(" (%s)", rule.Name)
+	}
+	return where
+}
+
+// Quote prices an order.
+func (r *Rules) Quote(userType, paymentMethod, discountCode, shippingMethod string, items []Item) Quote {
+	in := facts{
+		user:     userType,
+		payment:  paymentMethod,
+		code:     discountCode,
+		shipping: shippingMethod,
+		items:    len(items),
+		subtotal: subtotal(items),
+	}
+	q := Quote{Total: in.subtotal}
+	rule, ok := first(r.Pricing, in)
+	if !ok {
+		q.Rejected = "no pricing rule matches"
+		return q
+	}
+	q.Applied = append(q.Applied, rule.Name)
+	if rule.Then.Reject != "" {
+		q.Rejected = rule.Then.Reject
+		return q
+	}
+	q.Total = q.Total*(1-rule.Then.Discount) + rule.Then.Surcharge
+	q.Status = rule.Then.Status
+
+	in.status, in.total = q.Status, q.Total
+	if rule, ok := first(r.Shipping, in); ok {
+		q.Applied = append(q.Applied, rule.Name)
+		q.Total += rule.Then.Surcharge
+	}
+	if q.Total <= 0 {
+		q.Rejected = "nothing to pay"
+	}
+	return q
+}
+
+// ProcessOrder prices an order with the rules and returns it with its
+// status, or nil if the rules reject it.
+func (r *Rules) ProcessOrder(orderID int, userType, paymentMethod, discountCode, shippingMethod string, items []Item) *Order {
+	q := r.Quote(userType, paymentMethod, discountCode, shippingMethod, items)
+	if q.Rejected != "" {
+		return nil
+	}
+	return &Order{
+		ID:             orderID,
+		Items:          items,
+		UserType:       userType,
+		PaymentMethod:  paymentMethod,
+		DiscountCode:   discountCode,
+		ShippingMethod: shippingMethod,
+		Status:         q.Status,
+	}
+}
+
+// facts are what a condition can test.
+type facts struct {
+	user, payment, code, shipping, status string
+	items                                 int
+	subtotal, total                       float64
+}
+
+func first(rules []Rule, in facts) (Rule, bool) {
+	for _, r := range rules {
+		if r.When.holds(in) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+func (c Condition) holds(in facts) bool {
+	return oneOf(c.User, in.user) &&
+		oneOf(c.Payment, in.payment) &&
+		oneOf(c.Code, in.code) &&
+		oneOf(c.Shipping, in.shipping) &&
+		oneOf(c.Status, in.status) &&
+		c.Items.contains(float64(in.items)) &&
+		c.Subtotal.contains(in.subtotal) &&
+		c.Total.contains(in.total)
+}
+
+func oneOf(values []string, v string) bool {
+	return values == nil || slices.Contains(values, v)
+}
+
+func (b *Bound) contains(x float64) bool {
+	if b == nil {
+		return true
+	}
+	return (b.Over == nil || x > *b.Over) && (b.AtMost == nil || x <= *b.AtMost)
+}
+
+func subtotal(items []Item) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.Price
+	}
+	return total
+}
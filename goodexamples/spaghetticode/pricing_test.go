@@ -0,0 +1,107 @@
+package spaghetticode_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bclements/antipatterns/golangexamples/spaghetticode"
+	good "github.com/bclements/antipatterns/goodexamples/spaghetticode"
+)
+
+// TestMatrix orders every combination pricematrix checks through the
+// original ProcessOrder and the default rules, and expects the same
+// outcome: whether there is an order, and its status.
+func TestMatrix(t *testing.T) {
+	var (
+		userTypes = []string{"premium", "regular", "guest", ""}
+		payments  = []string{"credit", "paypal", "cash", ""}
+		codes     = []string{"", "SAVE20", "SAVE10", "save20", "BOGUS"}
+		shipping  = []string{"express", "standard", "pickup", ""}
+		// counts straddle the 3 and 5 item thresholds; prices make
+		// subtotals, and discounted totals, that straddle 0, 50 and 100.
+		counts = []int{0, 1, 2, 3, 4, 5, 6, 7}
+		prices = []float64{-10, 0, 5, 10, 12.5, 17.5, 20, 25, 27.5, 50, 55, 62.5, 100, 101, 125}
+	)
+	n := 0
+	for _, u := range userTypes {
+		for _, p := range payments {
+			for _, c := range codes {
+				for _, s := range shipping {
+					for _, count := range counts {
+						for _, price := range prices {
+							n++
+							items := make([]spaghetticode.Item, count)
+							goodItems := make([]good.Item, count)
+							for i := range count {
+								name := fmt.Sprintf("Item%d", i+1)
+								items[i] = spaghetticode.Item{Product: name, Price: price}
+								goodItems[i] = good.Item{Product: name, Price: price}
+							}
+							want := "no order"
+							if o := spaghetticode.ProcessOrder(n, u, p, c, s, items); o != nil {
+								want = o.Status
+							}
+							got := "no order"
+							if o := good.DefaultRules.ProcessOrder(n, u, p, c, s, goodItems); o != nil {
+								got = o.Status
+							}
+							if got != want {
+								q := good.DefaultRules.Quote(u, p, c, s, goodItems)
+								t.Errorf("user=%q payment=%q code=%q shipping=%q items=%d×%g: got %s, want %s (rules: %s)",
+									u, p, c, s, count, price, got, want, strings.Join(q.Applied, " → "))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if n != 38400 {
+		t.Errorf("%d combinations, want 38400", n)
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	const rules = `
+pricing:
+  - when: {user: [premium]}
+    then: {status: processing}
+  - name: half off
+    then: {discount: 1.5, status: processing}
+  - name: refuse
+    then: {reject: no, status: pending}
+  - name: silent
+    then: {discount: 0.1}
+shipping:
+  - name: express
+    then: {surcharge: 20}
+  - name: free
+    then: {discount: 0.5}
+`
+	_, err := good.ParseRules([]byte(rules), "yaml")
+	if err == nil {
+		t.Fatal("ParseRules succeeded")
+	}
+	want := []string{
+		"pricing rule 1: no name",
+		"pricing rule 2 (half off): discount 1.5 is not a fraction in [0, 1)",
+		"pricing rule 3 (refuse): a rejecting rule cannot also price the order",
+		"pricing rule 4 (silent): sets no status",
+		"shipping rule 2 (free): shipping rules only add surcharges",
+	}
+	if err.Error() != strings.Join(want, "\n") {
+		t.Errorf("errors:\n%s\nwant:\n%s", err, strings.Join(want, "\n"))
+	}
+}
+
+func TestParseRulesUnknownField(t *testing.T) {
+	for format, rules := range map[string]string{
+		"yaml": "pricing:\n  - name: typo\n    when: {usr: [premium]}\n    then: {status: processing}\n",
+		"json": `{"pricing": [{"name": "typo", "when": {"usr": ["premium"]}, "then": {"status": "processing"}}]}`,
+	} {
+		if _, err := good.ParseRules([]byte(rules), format); err == nil || !strings.Contains(err.Error(), "usr") {
+			t.Errorf("%s: ParseRules = %v, want an error naming usr", format, err)
+		}
+	}
+}
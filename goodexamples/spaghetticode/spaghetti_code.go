@@ -0,0 +1,151 @@
+// Package spaghetticode is the Spaghetti Code example untangled. The
+// decisions ProcessOrder made in one nested function are now data: a table
+// of pricing rules and a table of shipping rules, in pricing.yaml, read by
+// a small engine. The results are the same for every input. ValidateUser
+// and OrderProcessor are untangled in code.
+package spaghetticode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Customer types, payment methods, discount codes and shipping methods.
+const (
+	Premium = "premium"
+	Regular = "regular"
+
+	Credit = "credit"
+	PayPal = "paypal"
+
+	Save10 = "SAVE10"
+	Save20 = "SAVE20"
+
+	Express  = "express"
+	Standard = "standard"
+)
+
+// Order statuses.
+const (
+	StatusProcessing  = "processing"
+	StatusPending     = "pending"
+	StatusInvalidCode = "invalid_code"
+)
+
+// Order represents an order in the system.
+type Order struct {
+	ID             int
+	Items          []Item
+	UserType       string
+	PaymentMethod  string
+	DiscountCode   string
+	ShippingMethod string
+	Status         string
+}
+
+// Item is one line of an order.
+type Item struct {
+	Product string
+	Price   float64
+}
+
+// ProcessOrder prices an order with DefaultRules and returns it with its
+// status, or nil if the order cannot be accepted: guests, unsupported
+// payment methods, unknown codes on large premium baskets and orders that
+// come to nothing.
+func ProcessOrder(orderID int, userType, paymentMethod, discountCode, shippingMethod string, items []Item) *Order {
+	return DefaultRules.ProcessOrder(orderID, userType, paymentMethod, discountCode, shippingMethod, items)
+}
+
+// batchSize is how many pieces an OrderProcessor collects.
+const batchSize = 8
+
+// OrderProcessor collects the pieces of an order. The original tangle of
+// states and flags only ever did one thing: return the concatenation of
+// the first eight pieces when the eighth arrives.
+type OrderProcessor struct {
+	buf  strings.Builder
+	n    int
+	done bool
+}
+
+// NewOrderProcessor returns an empty processor.
+func NewOrderProcessor() *OrderProcessor {
+	return &OrderProcessor{}
+}
+
+// Process adds a piece. It returns the collected pieces and true when the
+// batch is complete, and false before then and for any later pieces.
+func (op *OrderProcessor) Process(data any) (string, bool) {
+	if op.done {
+		return "", false
+	}
+	fmt.Fprint(&op.buf, data)
+	op.n++
+	if op.n < batchSize {
+		return "", false
+	}
+	op.done = true
+	return op.buf.String(), true
+}
+
+// ValidateUser checks a sign-up, returning false and the first problem
+// found, or true and "valid".
+func ValidateUser(username, password, email, userType string, age int, country string) (bool, string) {
+	switch {
+	case username == "":
+		return false, "username required"
+	case len(username) < 3:
+		return false, "username too short"
+	case len(username) > 20:
+		return false, "username too long"
+	case password == "":
+		return false, "password required"
+	case len(password) < 8:
+		return false, "password too short"
+	case email == "":
+		return false, "email required"
+	}
+	if msg := eligibility(userType, age, country); msg != "" {
+		return false, msg
+	}
+	return true, "valid"
+}
+
+// eligibility returns why a user of the given type, age and country may
+// not sign up, or "" if they may.
+func eligibility(userType string, age int, country string) string {
+	switch userType {
+	case "admin":
+		if age < 21 {
+			return "admin must be 21+"
+		}
+		if country != "US" && country != "UK" {
+			return "admin must be in US or UK"
+		}
+	case Regular:
+		if age < 18 {
+			return "must be 18+"
+		}
+		if country == "" {
+			return "country required"
+		}
+	default:
+		if age < 13 {
+			return "must be 13+"
+		}
+	}
+	return ""
+}
+
+// Run demonstrates the refactoring, writing everything it prints to w.
+func Run(w io.Writer) {
+	items := []Item{
+		{Product: "Item1", Price: 10.0},
+		{Product: "Item2", Price: 20.0},
+	}
+	if order := ProcessOrder(1, Premium, Credit, Save20, Express, items); order != nil {
+		fmt.Fprintf(w, "Order processed: %+v\n", order)
+	}
+}
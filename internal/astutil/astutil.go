@@ -0,0 +1,75 @@
+// Package astutil holds small syntax-tree helpers shared by the analyzers.
+package astutil
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// FuncName returns the name of a function declaration in the form used by
+// go doc, e.g. "ProcessOrder", "Calculator.Add" or "(*DB).GetUserByID".
+func FuncName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	t := fd.Recv.List[0].Type
+	star := false
+	if p, ok := t.(*ast.StarExpr); ok {
+		star, t = true, p.X
+	}
+	switch x := t.(type) {
+	case *ast.IndexExpr:
+		t = x.X
+	case *ast.IndexListExpr:
+		t = x.X
+	}
+	recv := "?"
+	if id, ok := t.(*ast.Ident); ok {
+		recv = id.Name
+	}
+	if star {
+		return "(*" + recv + ")." + fd.Name.Name
+	}
+	return recv + "." + fd.Name.Name
+}
+
+// EnclosingDecl returns the top-level declaration of f that contains pos,
+// counting a declaration's doc comment as part of it, or nil.
+func EnclosingDecl(f *ast.File, pos token.Pos) ast.Decl {
+	for _, d := range f.Decls {
+		start := d.Pos()
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		}
+		if start <= pos && pos < d.End() {
+			return d
+		}
+	}
+	return nil
+}
+
+// DeclName returns the name of a top-level declaration: the FuncName of a
+// function, or the first name a type, const or var declaration introduces.
+func DeclName(d ast.Decl) string {
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		return FuncName(d)
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				return spec.Name.Name
+			case *ast.ValueSpec:
+				return spec.Names[0].Name
+			}
+		}
+	}
+	return ""
+}
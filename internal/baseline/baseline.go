@@ -0,0 +1,150 @@
+// Package baseline records the findings a code base already has, so that
+// a check can fail only on new ones and the count can only go down.
+//
+// Findings are identified by a fingerprint rather than a position: a hash
+// of the analyzer, the diagnostic's category, the declaration it occurs in
+// and its source text with whitespace normalized. Editing other lines, or
+// moving the declaration around the file, keeps every fingerprint; changing
+// the offending code itself, or adding another copy of it, does not.
+package baseline
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Version is the format version written to baseline files.
+const Version = 1
+
+// An Entry is a finding, or in a Baseline the findings that share a
+// fingerprint.
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	Analyzer    string `json:"analyzer"`
+	Category    string `json:"category,omitempty"`
+	Symbol      string `json:"symbol"`
+	// File and Message are for the reader; they do not contribute to the
+	// fingerprint.
+	File    string `json:"file"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// NewEntry returns the entry of a single finding. symbol names the
+// declaration it occurs in, e.g. "example.com/pkg.(*T).Method", and snippet
+// is the source text it reports.
+func NewEntry(analyzer, category, symbol, snippet, file, message string) Entry {
+	h := sha256.New()
+	for _, s := range []string{analyzer, category, symbol, Normalize(snippet)} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return Entry{
+		Fingerprint: hex.EncodeToString(h.Sum(nil))[:16],
+		Analyzer:    analyzer,
+		Category:    category,
+		Symbol:      symbol,
+		File:        file,
+		Message:     message,
+		Count:       1,
+	}
+}
+
+// Normalize collapses each run of white space in s to a single space, so
+// that reindenting code keeps its fingerprint.
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// A Baseline is the set of findings a code base is allowed to have.
+type Baseline struct {
+	Version  int     `json:"version"`
+	Findings []Entry `json:"findings"`
+}
+
+// New returns a baseline allowing the given findings.
+func New(findings []Entry) *Baseline {
+	index := make(map[string]int)
+	b := &Baseline{Version: Version, Findings: []Entry{}}
+	for _, e := range findings {
+		if i, ok := index[e.Fingerprint]; ok {
+			b.Findings[i].Count += e.Count
+			continue
+		}
+		index[e.Fingerprint] = len(b.Findings)
+		b.Findings = append(b.Findings, e)
+	}
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Symbol != y.Symbol {
+			return x.Symbol < y.Symbol
+		}
+		if x.Analyzer != y.Analyzer {
+			return x.Analyzer < y.Analyzer
+		}
+		return x.Fingerprint < y.Fingerprint
+	})
+	return b
+}
+
+// Read reads a baseline file.
+func Read(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := new(Baseline)
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("%s: unsupported baseline version %d", path, b.Version)
+	}
+	return b, nil
+}
+
+// Write writes the baseline to path as indented JSON.
+func (b *Baseline) Write(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o666)
+}
+
+// Compare returns the indexes of the findings the baseline does not allow,
+// and the baseline's entries that current no longer has, with Count set to
+// how many occurrences have gone.
+func (b *Baseline) Compare(current []Entry) (added []int, gone []Entry) {
+	allowed := make(map[string]int)
+	for _, e := range b.Findings {
+		allowed[e.Fingerprint] += e.Count
+	}
+	for i, e := range current {
+		if allowed[e.Fingerprint] > 0 {
+			allowed[e.Fingerprint]--
+			continue
+		}
+		added = append(added, i)
+	}
+	for _, e := range b.Findings {
+		if n := min(allowed[e.Fingerprint], e.Count); n > 0 {
+			allowed[e.Fingerprint] -= n
+			e.Count = n
+			gone = append(gone, e)
+		}
+	}
+	return added, gone
+}
@@ -0,0 +1,269 @@
+// Package characterize records what a type's methods do today, so that a
+// rewrite can be checked against it: a golden-master, or characterization,
+// test.
+//
+// Record runs a script of method calls against one value and writes down
+// every argument and result as JSON. Replay makes the same calls, with the
+// same arguments, against another value of any type with the same method
+// set, and reports each call whose results differ. Arguments go through
+// JSON even when recording, so that both runs see exactly the same values.
+package characterize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A Step is a call for Record to make. Args holds one value per parameter;
+// a variadic parameter takes a slice.
+type Step struct {
+	Method string
+	Args   []any
+}
+
+// A Call is a recorded method call.
+type Call struct {
+	Method  string            `json:"method"`
+	Args    []json.RawMessage `json:"args"`
+	Results []json.RawMessage `json:"results"`
+	// Panic is the value the call panicked with, formatted with %v.
+	Panic string `json:"panic,omitempty"`
+}
+
+// A Transcript is the record of a script's calls against one type.
+type Transcript struct {
+	Type  string `json:"type"`
+	Calls []Call `json:"calls"`
+}
+
+// Record makes the calls of script on target, which is usually a pointer,
+// and returns what they returned. Errors are recorded as their messages.
+func Record(target any, script []Step) (*Transcript, error) {
+	v := reflect.ValueOf(target)
+	t := &Transcript{Type: typeName(v.Type())}
+	for i, s := range script {
+		m := v.MethodByName(s.Method)
+		if !m.IsValid() {
+			return nil, fmt.Errorf("step %d: %s has no method %s", i, t.Type, s.Method)
+		}
+		if len(s.Args) != m.Type().NumIn() {
+			return nil, fmt.Errorf("step %d: %s takes %d arguments, not %d", i, s.Method, m.Type().NumIn(), len(s.Args))
+		}
+		c := Call{Method: s.Method}
+		for j, a := range s.Args {
+			data, err := json.Marshal(a)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %s argument %d: %v", i, s.Method, j, err)
+			}
+			c.Args = append(c.Args, data)
+		}
+		var err error
+		c.Results, c.Panic, err = call(m, c.Args)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %s: %v", i, s.Method, err)
+		}
+		t.Calls = append(t.Calls, c)
+	}
+	return t, nil
+}
+
+// A Divergence is a call whose results differ from the transcript's.
+type Divergence struct {
+	Index int // in the transcript's Calls
+	Call  Call
+	// Got is what the replay returned, with Results and Panic set; or, if
+	// the call could not be made, Err says why.
+	Got Call
+	Err error
+}
+
+func (d Divergence) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "call %d: %s(%s): ", d.Index, d.Call.Method, join(d.Call.Args))
+	if d.Err != nil {
+		b.WriteString(d.Err.Error())
+		return b.String()
+	}
+	fmt.Fprintf(&b, "want %s, got %s", outcome(d.Call), outcome(d.Got))
+	return b.String()
+}
+
+// Replay makes the transcript's calls on target and returns those whose
+// results differ. It fails only if an argument no longer decodes into
+// the parameter's type; a missing method is a divergence.
+func Replay(t *Transcript, target any) ([]Divergence, error) {
+	v := reflect.ValueOf(target)
+	var out []Divergence
+	for i, c := range t.Calls {
+		m := v.MethodByName(c.Method)
+		if !m.IsValid() {
+			out = append(out, Divergence{Index: i, Call: c, Err: fmt.Errorf("%s has no method %s", typeName(v.Type()), c.Method)})
+			continue
+		}
+		if len(c.Args) != m.Type().NumIn() {
+			out = append(out, Divergence{Index: i, Call: c, Err: fmt.Errorf("%s takes %d arguments, not %d", c.Method, m.Type().NumIn(), len(c.Args))})
+			continue
+		}
+		got := Call{Method: c.Method, Args: c.Args}
+		var err error
+		got.Results, got.Panic, err = call(m, c.Args)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %s: %v", i, c.Method, err)
+		}
+		if !equal(c, got) {
+			out = append(out, Divergence{Index: i, Call: c, Got: got})
+		}
+	}
+	return out, nil
+}
+
+// Missing returns the exported methods of target that the transcript never
+// calls, in alphabetical order.
+func Missing(t *Transcript, target any) []string {
+	called := make(map[string]bool)
+	for _, c := range t.Calls {
+		called[c.Method] = true
+	}
+	typ := reflect.TypeOf(target)
+	var out []string
+	for i := range typ.NumMethod() {
+		if name := typ.Method(i).Name; !called[name] {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Read reads a transcript written by Write.
+func Read(path string) (*Transcript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := new(Transcript)
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return t, nil
+}
+
+// Write writes the transcript to path as indented JSON, one call per
+// line.
+func (t *Transcript) Write(path string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "{\n  \"type\": %q,\n  \"calls\": [\n", t.Type)
+	for i, c := range t.Calls {
+		line, err := marshal(c)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("    ")
+		buf.Write(line)
+		if i < len(t.Calls)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("  ]\n}\n")
+	return os.WriteFile(path, buf.Bytes(), 0o666)
+}
+
+var errorType = reflect.TypeFor[error]()
+
+// call decodes args into m's parameter types, calls m and encodes its
+// results.
+func call(m reflect.Value, args []json.RawMessage) (results []json.RawMessage, panicked string, err error) {
+	mt := m.Type()
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		p := reflect.New(mt.In(i))
+		if err := json.Unmarshal(a, p.Interface()); err != nil {
+			return nil, "", fmt.Errorf("argument %d: %v", i, err)
+		}
+		in[i] = p.Elem()
+	}
+	var out []reflect.Value
+	func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panicked = fmt.Sprint(p)
+			}
+		}()
+		if mt.IsVariadic() {
+			out = m.CallSlice(in)
+		} else {
+			out = m.Call(in)
+		}
+	}()
+	if panicked != "" {
+		return nil, panicked, nil
+	}
+	results = []json.RawMessage{}
+	for i, r := range out {
+		var v any = r.Interface()
+		if mt.Out(i) == errorType && !r.IsNil() {
+			v = r.Interface().(error).Error()
+		}
+		data, err := marshal(v)
+		if err != nil {
+			return nil, "", fmt.Errorf("result %d: %v", i, err)
+		}
+		results = append(results, data)
+	}
+	return results, "", nil
+}
+
+func equal(want, got Call) bool {
+	if want.Panic != got.Panic || len(want.Results) != len(got.Results) {
+		return false
+	}
+	for i := range want.Results {
+		if !bytes.Equal(want.Results[i], got.Results[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// marshal encodes v compactly, without escaping HTML, so that equal values
+// always encode to equal bytes.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func outcome(c Call) string {
+	if c.Panic != "" {
+		return "panic: " + c.Panic
+	}
+	return "(" + join(c.Results) + ")"
+}
+
+func join(msgs []json.RawMessage) string {
+	s := make([]string, len(msgs))
+	for i, m := range msgs {
+		s[i] = string(m)
+	}
+	return strings.Join(s, ", ")
+}
+
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		return "*" + typeName(t.Elem())
+	}
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
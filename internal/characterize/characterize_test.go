@@ -0,0 +1,189 @@
+package characterize_test
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bclements/antipatterns/internal/characterize"
+)
+
+// counter is the code being characterized.
+type counter struct{ n int }
+
+func (c *counter) Add(d int) int { c.n += d; return c.n }
+
+func (c *counter) Div(d int) (int, error) {
+	if d == 0 {
+		return 0, errors.New("divide by zero")
+	}
+	return c.n / d, nil
+}
+
+func (c *counter) Join(sep string, parts ...string) string { return strings.Join(parts, sep) }
+
+func (c *counter) Reset() { c.n = 0 }
+
+// wrong is a rewrite of counter that gets every kind of thing wrong: Add
+// is off by one, Div panics on zero, Join lost its separator and Reset is
+// gone.
+type wrong struct{ n int }
+
+func (c *wrong) Add(d int) int { c.n += d; return c.n + 1 }
+
+func (c *wrong) Div(d int) (int, error) { return c.n / d, nil }
+
+func (c *wrong) Join(parts ...string) string { return strings.Join(parts, "") }
+
+var script = []characterize.Step{
+	{Method: "Add", Args: []any{2}},
+	{Method: "Add", Args: []any{3}},
+	{Method: "Div", Args: []any{0}},
+	{Method: "Div", Args: []any{2}},
+	{Method: "Join", Args: []any{"-", []string{"a", "b"}}},
+	{Method: "Reset"},
+}
+
+func record(t *testing.T) *characterize.Transcript {
+	t.Helper()
+	tr, err := characterize.Record(new(counter), script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestRecord(t *testing.T) {
+	tr := record(t)
+	if want := "*github.com/bclements/antipatterns/internal/characterize_test.counter"; tr.Type != want {
+		t.Errorf("Type = %s, want %s", tr.Type, want)
+	}
+	want := []string{`2`, `5`, `0, "divide by zero"`, `2, null`, `"a-b"`, ``}
+	for i, c := range tr.Calls {
+		var results []string
+		for _, r := range c.Results {
+			results = append(results, string(r))
+		}
+		if got := strings.Join(results, ", "); got != want[i] || c.Panic != "" {
+			t.Errorf("call %d: %s = (%s) %q, want (%s)", i, c.Method, got, c.Panic, want[i])
+		}
+	}
+}
+
+func TestRecordErrors(t *testing.T) {
+	for _, test := range []struct {
+		step characterize.Step
+		err  string
+	}{
+		{characterize.Step{Method: "Sub", Args: []any{1}}, "step 0: *github.com/bclements/antipatterns/internal/characterize_test.counter has no method Sub"},
+		{characterize.Step{Method: "Add", Args: []any{1, 2}}, "step 0: Add takes 1 arguments, not 2"},
+		{characterize.Step{Method: "Add", Args: []any{"one"}}, "step 0: Add: argument 0: json: cannot unmarshal string into Go value of type int"},
+		{characterize.Step{Method: "Add", Args: []any{func() {}}}, "step 0: Add argument 0: json: unsupported type: func()"},
+	} {
+		if _, err := characterize.Record(new(counter), []characterize.Step{test.step}); err == nil || err.Error() != test.err {
+			t.Errorf("Record(%v) = %v, want %s", test.step, err, test.err)
+		}
+	}
+}
+
+// TestReplaySame checks that a transcript survives Write and Read and
+// that the code it was recorded from matches it.
+func TestReplaySame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.json")
+	if err := record(t).Write(path); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := characterize.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	divergences, err := characterize.Replay(tr, new(counter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range divergences {
+		t.Error(d)
+	}
+	if missing := characterize.Missing(tr, new(counter)); len(missing) > 0 {
+		t.Errorf("Missing = %v", missing)
+	}
+}
+
+func TestReplayWrong(t *testing.T) {
+	divergences, err := characterize.Replay(record(t), new(wrong))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range divergences {
+		got = append(got, d.String())
+	}
+	want := []string{
+		// A different result, and Add's state carries the difference on.
+		`call 0: Add(2): want (2), got (3)`,
+		`call 1: Add(3): want (5), got (6)`,
+		// A panic where the original returned an error.
+		`call 2: Div(0): want (0, "divide by zero"), got panic: runtime error: integer divide by zero`,
+		// Call 3, Div(2), matches.
+		// An arity mismatch.
+		`call 4: Join("-", ["a","b"]): Join takes 1 arguments, not 2`,
+		// A missing method.
+		`call 5: Reset(): *github.com/bclements/antipatterns/internal/characterize_test.wrong has no method Reset`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("divergences:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, d := range divergences[:3] {
+		if d.Err != nil {
+			t.Errorf("call %d: Err = %v, want the results in Got", d.Index, d.Err)
+		}
+	}
+	if d := divergences[2]; d.Got.Panic != "runtime error: integer divide by zero" || d.Got.Results != nil {
+		t.Errorf("call 2: Got = %+v, want only a panic", d.Got)
+	}
+	for _, d := range divergences[3:] {
+		if d.Err == nil {
+			t.Errorf("call %d: no Err for a call that could not be made", d.Index)
+		}
+	}
+}
+
+// TestReplayPanicked checks a divergence the other way round: the
+// recording panicked and the replay returns.
+func TestReplayPanicked(t *testing.T) {
+	tr, err := characterize.Record(new(wrong), []characterize.Step{{Method: "Div", Args: []any{0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	divergences, err := characterize.Replay(tr, new(counter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `call 0: Div(0): want panic: runtime error: integer divide by zero, got (0, "divide by zero")`
+	if len(divergences) != 1 || divergences[0].String() != want {
+		t.Errorf("divergences = %v, want [%s]", divergences, want)
+	}
+}
+
+// TestReplayBadArgument checks that an argument that no longer decodes
+// fails the replay rather than being reported as a divergence.
+func TestReplayBadArgument(t *testing.T) {
+	tr := &characterize.Transcript{Calls: []characterize.Call{
+		{Method: "Add", Args: []json.RawMessage{json.RawMessage(`"two"`)}, Results: []json.RawMessage{json.RawMessage(`2`)}},
+	}}
+	_, err := characterize.Replay(tr, new(counter))
+	if want := "call 0: Add: argument 0: json: cannot unmarshal string into Go value of type int"; err == nil || err.Error() != want {
+		t.Errorf("Replay = %v, want %s", err, want)
+	}
+}
+
+func TestMissing(t *testing.T) {
+	tr := record(t)
+	tr.Calls = slices.DeleteFunc(tr.Calls, func(c characterize.Call) bool { return c.Method == "Div" || c.Method == "Reset" })
+	if got, want := characterize.Missing(tr, new(counter)), []string{"Div", "Reset"}; !slices.Equal(got, want) {
+		t.Errorf("Missing = %v, want %v", got, want)
+	}
+}
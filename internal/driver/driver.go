@@ -0,0 +1,115 @@
+// Package driver loads Go packages and runs analyzers over them on behalf of
+// the repository's commands that need more than singlechecker offers, such
+// as SARIF output or access to analyzer results.
+package driver
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Load loads and type-checks the packages matching patterns, resolved
+// relative to dir. It fails if any package has errors.
+func Load(dir string, tests bool, patterns ...string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode:  packages.LoadAllSyntax | packages.NeedModule,
+		Dir:   dir,
+		Tests: tests,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("%d errors loading packages", n)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages match %q", patterns)
+	}
+	return pkgs, nil
+}
+
+// Analyze runs the analyzers over pkgs and their dependencies.
+func Analyze(pkgs []*packages.Package, analyzers ...*analysis.Analyzer) (*checker.Graph, error) {
+	return checker.Analyze(analyzers, pkgs, nil)
+}
+
+// A Finding is a diagnostic resolved to file positions.
+type Finding struct {
+	Analyzer string
+	Category string
+	Package  string
+	Pos      token.Position
+	End      token.Position
+	Message  string
+	Related  []Related
+
+	// Fset and Diagnostic give access to the original diagnostic, e.g. to
+	// apply its suggested fixes.
+	Fset       *token.FileSet
+	Diagnostic analysis.Diagnostic
+}
+
+// Related is a resolved analysis.RelatedInformation.
+type Related struct {
+	Pos     token.Position
+	Message string
+}
+
+// Findings returns the diagnostics reported on the root packages of g,
+// sorted by position. It returns the first analyzer error it meets.
+func Findings(g *checker.Graph) ([]Finding, error) {
+	var out []Finding
+	for _, act := range g.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", act.Package.PkgPath, act.Analyzer.Name, act.Err)
+		}
+		fset := act.Package.Fset
+		for _, d := range act.Diagnostics {
+			f := Finding{
+				Analyzer:   act.Analyzer.Name,
+				Category:   d.Category,
+				Package:    act.Package.PkgPath,
+				Pos:        fset.Position(d.Pos),
+				Message:    d.Message,
+				Fset:       fset,
+				Diagnostic: d,
+			}
+			if d.End.IsValid() {
+				f.End = fset.Position(d.End)
+			}
+			for _, r := range d.Related {
+				f.Related = append(f.Related, Related{Pos: fset.Position(r.Pos), Message: r.Message})
+			}
+			out = append(out, f)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].Pos, out[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Offset != b.Offset {
+			return a.Offset < b.Offset
+		}
+		return out[i].Analyzer < out[j].Analyzer
+	})
+	return out, nil
+}
+
+// Results returns the results computed by analyzer a for each root package
+// in g, keyed by package path.
+func Results(g *checker.Graph, a *analysis.Analyzer) map[string]any {
+	out := make(map[string]any)
+	for _, act := range g.Roots {
+		if act.Analyzer == a {
+			out[act.Package.PkgPath] = act.Result
+		}
+	}
+	return out
+}
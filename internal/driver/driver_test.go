@@ -0,0 +1,87 @@
+package driver_test
+
+import (
+	"go/ast"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/bclements/antipatterns/internal/driver"
+)
+
+// funcs reports every function declaration and returns their names.
+var funcs = &analysis.Analyzer{
+	Name:     "funcs",
+	Doc:      "report every function",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run: func(pass *analysis.Pass) (any, error) {
+		var names []string
+		pass.ResultOf[inspect.Analyzer].(*inspector.Inspector).Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+			fd := n.(*ast.FuncDecl)
+			names = append(names, fd.Name.Name)
+			pass.Report(analysis.Diagnostic{
+				Pos:      fd.Name.Pos(),
+				End:      fd.Name.End(),
+				Category: "func",
+				Message:  fd.Name.Name,
+				Related:  []analysis.RelatedInformation{{Pos: fd.Pos(), Message: "declared here"}},
+			})
+		})
+		return names, nil
+	},
+	ResultType: reflect.TypeOf([]string(nil)),
+}
+
+func TestFindings(t *testing.T) {
+	const pkg = "github.com/bclements/antipatterns/golangexamples/spaghetticode"
+	pkgs, err := driver.Load("../..", false, "./golangexamples/spaghetticode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := driver.Analyze(pkgs, funcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := driver.Findings(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Message)
+		if f.Analyzer != "funcs" || f.Category != "func" || f.Package != pkg {
+			t.Errorf("%s: analyzer %q, category %q, package %q", f.Message, f.Analyzer, f.Category, f.Package)
+		}
+		if filepath.Base(f.Pos.Filename) != "spaghetti_code.go" || f.End.Line != f.Pos.Line || f.End.Column != f.Pos.Column+len(f.Message) {
+			t.Errorf("%s: span %v-%v", f.Message, f.Pos, f.End)
+		}
+		if len(f.Related) != 1 || f.Related[0].Pos.Line != f.Pos.Line {
+			t.Errorf("%s: related %v", f.Message, f.Related)
+		}
+	}
+	want := []string{"ProcessOrder", "NewOrderProcessor", "Process", "handleReady", "handleLoading", "handleProcessing", "handleRetrying", "handleComplete", "ValidateUser", "Run"}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings in order %v, want %v", got, want)
+	}
+
+	results := driver.Results(g, funcs)
+	if names, _ := results[pkg].([]string); !slices.Equal(names, want) {
+		t.Errorf("Results[%s] = %v, want %v", pkg, results[pkg], want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := driver.Load("../..", false, "./no/such/package"); err == nil {
+		t.Error("Load of a missing package succeeded")
+	}
+	if _, err := driver.Load("../..", false, "./cmd/antilint/testdata/..."); err == nil || !strings.Contains(err.Error(), "no packages") {
+		t.Errorf("Load of an empty pattern: %v, want no packages", err)
+	}
+}
@@ -0,0 +1,189 @@
+// Package markers finds the anti-pattern markers the examples annotate
+// their code with, such as "// DEAD CODE: never executed" or
+// "// LEAK: Implementation-specific error", and ties each one to the
+// anti-pattern it names and the declaration it sits in.
+//
+// It also reads the "Why This Matters" sections of ANTI_PATTERNS_SUMMARY.md,
+// so that the markers can be exported with an explanation of each
+// anti-pattern.
+package markers
+
+import (
+	"bufio"
+	"bytes"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+)
+
+// A Pattern is one of the anti-patterns of ANTI_PATTERNS_SUMMARY.md.
+type Pattern struct {
+	ID   string // e.g. "dead-code"
+	Name string // the summary's heading, e.g. "Dead Code"
+	Tags []string
+}
+
+// Patterns lists the anti-patterns in the order of the summary, with the
+// marker tags the examples use for them.
+var Patterns = []*Pattern{
+	{ID: "boat-anchor", Name: "Boat Anchor", Tags: []string{"BOAT ANCHOR"}},
+	{ID: "spaghetti-code", Name: "Spaghetti Code", Tags: []string{"SPAGHETTI CODE", "SPAGHETTI"}},
+	{ID: "golden-hammer", Name: "Golden Hammer", Tags: []string{"GOLDEN HAMMER"}},
+	{ID: "copy-paste", Name: "Copy and Paste Programming", Tags: []string{"COPY-PASTE", "COPY PASTE"}},
+	{ID: "god-object", Name: "God Object", Tags: []string{"GOD OBJECT"}},
+	{ID: "lava-flow", Name: "Lava Flow", Tags: []string{"LAVA FLOW"}},
+	{ID: "dead-code", Name: "Dead Code", Tags: []string{"DEAD CODE"}},
+	{ID: "hard-coding", Name: "Hard Coding", Tags: []string{"HARD CODING", "HARDCODED"}},
+	{ID: "reinventing-the-wheel", Name: "Reinventing the Wheel", Tags: []string{"REINVENTING THE WHEEL"}},
+	{ID: "leaky-abstractions", Name: "Leaky Abstractions", Tags: []string{"LEAKY ABSTRACTION", "LEAK"}},
+}
+
+// markerRE matches a tag followed by a colon, anywhere in a comment line,
+// so that "IsEven - GOLDEN HAMMER: Using regex" counts.
+var markerRE = func() *regexp.Regexp {
+	var tags []string
+	for _, p := range Patterns {
+		for _, t := range p.Tags {
+			tags = append(tags, regexp.QuoteMeta(t))
+		}
+	}
+	return regexp.MustCompile(`\b(` + strings.Join(tags, "|") + `):\s*(.*)`)
+}()
+
+// A Marker is one marked comment line.
+type Marker struct {
+	Pattern  *Pattern
+	Tag      string // as written, e.g. "COPY-PASTE"
+	Text     string // what follows the tag, e.g. "Implementation-specific error"
+	Decl     string // the enclosing top-level declaration, e.g. "(*RedisCache).Get", if any
+	DeclKind string // func, method, type, var, const or import
+	Pos      token.Pos
+	End      token.Pos // the end of the comment line
+}
+
+// Find returns the markers in the comments of f, in order.
+func Find(f *ast.File) []*Marker {
+	var out []*Marker
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			offset := 0
+			for _, line := range strings.SplitAfter(c.Text, "\n") {
+				pos := c.Slash + token.Pos(offset)
+				offset += len(line)
+				m := markerRE.FindStringSubmatchIndex(line)
+				if m == nil {
+					continue
+				}
+				tag := line[m[2]:m[3]]
+				text := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line[m[4]:m[5]]), "*/"))
+				mk := &Marker{
+					Pattern: patternOf(tag),
+					Tag:     tag,
+					Text:    text,
+					Pos:     pos + token.Pos(m[2]),
+					End:     pos + token.Pos(len(strings.TrimRight(line, "\r\n"))),
+				}
+				if d := astutil.EnclosingDecl(f, mk.Pos); d != nil {
+					mk.Decl, mk.DeclKind = astutil.DeclName(d), declKind(d)
+				}
+				out = append(out, mk)
+			}
+		}
+	}
+	return out
+}
+
+func patternOf(tag string) *Pattern {
+	for _, p := range Patterns {
+		for _, t := range p.Tags {
+			if t == tag {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+func declKind(d ast.Decl) string {
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil {
+			return "method"
+		}
+		return "func"
+	case *ast.GenDecl:
+		return d.Tok.String()
+	}
+	return ""
+}
+
+// A Section is the explanation of one anti-pattern in the summary.
+type Section struct {
+	Heading string // e.g. "7. Dead Code"
+	Anchor  string // the heading's fragment on GitHub, e.g. "7-dead-code"
+	Why     string // the Markdown of its "Why This Matters" section
+}
+
+// ParseSummary returns the sections of ANTI_PATTERNS_SUMMARY.md, keyed by
+// Pattern ID. Patterns the summary does not explain are left out.
+func ParseSummary(summary []byte) map[string]*Section {
+	out := make(map[string]*Section)
+	var cur *Section
+	var why *strings.Builder
+	flush := func() {
+		if cur != nil && why != nil {
+			cur.Why = strings.TrimSpace(why.String())
+		}
+		why = nil
+	}
+	sc := bufio.NewScanner(bytes.NewReader(summary))
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "## "):
+			flush()
+			cur = nil
+			heading := strings.TrimSpace(line[3:])
+			name := heading
+			if i := strings.Index(name, ". "); i >= 0 {
+				name = name[i+2:]
+			}
+			for _, p := range Patterns {
+				if p.Name == name {
+					cur = &Section{Heading: heading, Anchor: anchor(heading)}
+					out[p.ID] = cur
+				}
+			}
+		case strings.HasPrefix(line, "### "):
+			flush()
+			if cur != nil && strings.TrimSpace(line[4:]) == "Why This Matters" {
+				why = new(strings.Builder)
+			}
+		case line == "---":
+			flush()
+		case why != nil:
+			why.WriteString(line)
+			why.WriteByte('\n')
+		}
+	}
+	flush()
+	return out
+}
+
+// anchor returns the fragment GitHub gives a Markdown heading: lower case,
+// punctuation dropped and spaces turned into hyphens.
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			b.WriteByte('-')
+		case r == '-' || r == '_' || 'a' <= r && r <= 'z' || '0' <= r && r <= '9':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
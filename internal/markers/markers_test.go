@@ -0,0 +1,93 @@
+package markers_test
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/bclements/antipatterns/internal/markers"
+)
+
+const src = `package p
+
+// DEAD CODE: never called
+func unused() {}
+
+// Cache - LEAKY ABSTRACTION: errors name the backend
+type Cache struct{}
+
+func (c *Cache) Get() {
+	// LEAK: Implementation-specific error
+}
+
+var (
+	limit = 10 // HARD CODING: magic number
+)
+
+/*
+LAVA FLOW: nobody knows
+what this was for
+SPAGHETTI: goto everywhere */
+func old() {}
+
+/* COPY-PASTE: same as old */
+
+// Not a marker: DEAD CODE without a colon, or dead code: in lower case.
+`
+
+func TestFind(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range markers.Find(f) {
+		pos, end := fset.Position(m.Pos), fset.Position(m.End)
+		got = append(got, fmt.Sprintf("%d:%d-%d %s %s %q %s %s", pos.Line, pos.Column, end.Column, m.Pattern.ID, m.Tag, m.Text, m.DeclKind, m.Decl))
+	}
+	want := []string{
+		`3:4-27 dead-code DEAD CODE "never called" func unused`,
+		`6:12-54 leaky-abstractions LEAKY ABSTRACTION "errors name the backend" type Cache`,
+		`10:5-40 leaky-abstractions LEAK "Implementation-specific error" method (*Cache).Get`,
+		`14:16-41 hard-coding HARD CODING "magic number" var limit`,
+		`18:1-24 lava-flow LAVA FLOW "nobody knows" func old`,
+		`20:1-30 spaghetti-code SPAGHETTI "goto everywhere" func old`,
+		`23:4-30 copy-paste COPY-PASTE "same as old"  `,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Find:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+// TestParseSummary reads the real summary: every anti-pattern must have a
+// heading and a "Why This Matters" section.
+func TestParseSummary(t *testing.T) {
+	data, err := os.ReadFile("../../ANTI_PATTERNS_SUMMARY.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections := markers.ParseSummary(data)
+	if len(sections) != len(markers.Patterns) {
+		t.Errorf("%d sections, want %d", len(sections), len(markers.Patterns))
+	}
+	for i, p := range markers.Patterns {
+		s := sections[p.ID]
+		if s == nil {
+			t.Errorf("no section for %s", p.ID)
+			continue
+		}
+		if want := fmt.Sprintf("%d. %s", i+1, p.Name); s.Heading != want {
+			t.Errorf("%s: heading %q, want %q", p.ID, s.Heading, want)
+		}
+		if s.Why == "" {
+			t.Errorf("%s: empty Why", p.ID)
+		}
+	}
+	if s := sections["copy-paste"]; s != nil && s.Anchor != "4-copy-and-paste-programming" {
+		t.Errorf("copy-paste anchor %q", s.Anchor)
+	}
+}
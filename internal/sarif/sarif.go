@@ -0,0 +1,151 @@
+// Package sarif writes Static Analysis Results Interchange Format (SARIF)
+// 2.1.0 logs, the format code-review tools such as GitHub code scanning
+// ingest.
+//
+// Only the parts of the schema the repository's commands produce are
+// modelled.
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+)
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Levels a Result or Rule may carry.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+	LevelNone    = "none"
+)
+
+// Log is the top-level SARIF document.
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []*Run `json:"runs"`
+}
+
+// New returns a Log holding a single run of the named tool.
+func New(driver Driver) *Log {
+	return &Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs:    []*Run{{Tool: Tool{Driver: driver}, Results: []Result{}}},
+	}
+}
+
+// Run returns the log's first run.
+func (l *Log) Run() *Run { return l.Runs[0] }
+
+// Write encodes the log as indented JSON.
+func (l *Log) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
+
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// AddRule adds r to the run's rules unless a rule with the same ID exists.
+func (r *Run) AddRule(rule Rule) {
+	for _, have := range r.Tool.Driver.Rules {
+		if have.ID == rule.ID {
+			return
+		}
+	}
+	r.Tool.Driver.Rules = append(r.Tool.Driver.Rules, rule)
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+type Rule struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name,omitempty"`
+	ShortDescription     *Message       `json:"shortDescription,omitempty"`
+	FullDescription      *Message       `json:"fullDescription,omitempty"`
+	Help                 *Message       `json:"help,omitempty"`
+	HelpURI              string         `json:"helpUri,omitempty"`
+	DefaultConfiguration *Configuration `json:"defaultConfiguration,omitempty"`
+	Properties           map[string]any `json:"properties,omitempty"`
+}
+
+type Configuration struct {
+	Level string `json:"level,omitempty"`
+}
+
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level,omitempty"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+type Message struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type LogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// FileLocation returns a Location for a span of file, which is made
+// relative to root when possible so logs are portable between checkouts.
+func FileLocation(root, file string, startLine, startCol, endLine, endCol int) Location {
+	if rel, err := filepath.Rel(root, file); err == nil && filepath.IsLocal(rel) {
+		file = rel
+	}
+	return Location{PhysicalLocation: &PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{URI: filepath.ToSlash(file)},
+		Region: &Region{
+			StartLine:   startLine,
+			StartColumn: startCol,
+			EndLine:     endLine,
+			EndColumn:   endCol,
+		},
+	}}
+}
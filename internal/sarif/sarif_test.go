@@ -0,0 +1,57 @@
+package sarif_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/bclements/antipatterns/internal/sarif"
+)
+
+func TestFileLocation(t *testing.T) {
+	for _, test := range []struct {
+		root, file, uri string
+	}{
+		{"/src/repo", "/src/repo/pkg/a.go", "pkg/a.go"},
+		{"/src/repo", "/src/other/b.go", "/src/other/b.go"},
+		{"/src/repo", "/src/repo/../repo2/c.go", "/src/repo/../repo2/c.go"},
+		{"", "pkg/a.go", "pkg/a.go"},
+	} {
+		loc := sarif.FileLocation(test.root, test.file, 3, 5, 4, 9)
+		got := loc.PhysicalLocation
+		if got.ArtifactLocation.URI != test.uri {
+			t.Errorf("FileLocation(%q, %q) URI = %q, want %q", test.root, test.file, got.ArtifactLocation.URI, test.uri)
+		}
+		if r := *got.Region; r != (sarif.Region{StartLine: 3, StartColumn: 5, EndLine: 4, EndColumn: 9}) {
+			t.Errorf("FileLocation(%q, %q) region = %+v", test.root, test.file, r)
+		}
+	}
+}
+
+func TestLog(t *testing.T) {
+	doc := sarif.New(sarif.Driver{Name: "tool"})
+	run := doc.Run()
+	run.AddRule(sarif.Rule{ID: "a", Name: "First"})
+	run.AddRule(sarif.Rule{ID: "b"})
+	run.AddRule(sarif.Rule{ID: "a", Name: "Second"})
+	if rules := run.Tool.Driver.Rules; len(rules) != 2 || rules[0].Name != "First" {
+		t.Errorf("rules after adding a, b, a: %+v", rules)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["version"] != sarif.Version || got["$schema"] != sarif.Schema {
+		t.Errorf("version %v, schema %v", got["version"], got["$schema"])
+	}
+	// A run with no results must still list them, as an empty array.
+	runs := got["runs"].([]any)
+	if results, ok := runs[0].(map[string]any)["results"].([]any); !ok || len(results) != 0 {
+		t.Errorf("results = %v, want []", runs[0].(map[string]any)["results"])
+	}
+}
@@ -0,0 +1,59 @@
+// Package suite lists the repository's analyzers, for the commands that
+// run all of them at once.
+package suite
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/bclements/antipatterns/analyzers/clones"
+	"github.com/bclements/antipatterns/analyzers/commentedcode"
+	"github.com/bclements/antipatterns/analyzers/complexity"
+	"github.com/bclements/antipatterns/analyzers/deadcode"
+	"github.com/bclements/antipatterns/analyzers/godobject"
+	"github.com/bclements/antipatterns/analyzers/goldenhammer"
+	"github.com/bclements/antipatterns/analyzers/lavaflow"
+	"github.com/bclements/antipatterns/analyzers/leakyerrors"
+	"github.com/bclements/antipatterns/analyzers/reinvent"
+	"github.com/bclements/antipatterns/analyzers/secretscan"
+)
+
+// Analyzers are all the analyzers under analyzers/, in the order the
+// README lists them.
+var Analyzers = []*analysis.Analyzer{
+	godobject.Analyzer,
+	complexity.Analyzer,
+	secretscan.Analyzer,
+	clones.Analyzer,
+	deadcode.Analyzer,
+	commentedcode.Analyzer,
+	reinvent.Analyzer,
+	goldenhammer.Analyzer,
+	leakyerrors.Analyzer,
+	lavaflow.Analyzer,
+}
+
+// Lookup returns the analyzers named in a comma-separated list, or all of
+// them if the list is empty.
+func Lookup(names string) ([]*analysis.Analyzer, error) {
+	if names == "" {
+		return Analyzers, nil
+	}
+	var out []*analysis.Analyzer
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, a := range Analyzers {
+			if a.Name == name {
+				out = append(out, a)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown analyzer %q", name)
+		}
+	}
+	return out, nil
+}
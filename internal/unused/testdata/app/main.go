@@ -0,0 +1,61 @@
+// Command app is a small program with one boat anchor of every kind.
+package main
+
+import "fmt"
+
+type Shape interface {
+	Area() float64
+}
+
+// Square reaches an interface, so reflection may read its fields and call
+// its exported methods: none of them are reported.
+type Square struct {
+	side  float64
+	color string
+}
+
+func newSquare(side float64) *Square {
+	return &Square{side: side, color: "red"}
+}
+
+func (s *Square) Area() float64 { return s.side * s.side }
+
+func (s *Square) Perimeter() float64 { return 4 * s.side }
+
+// options never reaches an interface.
+type options struct {
+	precision int
+	verbose   bool // set in main but never read
+}
+
+func (o options) reset() options { return options{} }
+
+// Circle is never used at all.
+type Circle struct {
+	radius float64
+}
+
+// Exporter is an interface nobody implements or mentions.
+type Exporter interface {
+	Export() string
+}
+
+const unit = "cm"
+
+const legacyUnit = "in"
+
+var total float64
+
+func describe(s Shape, o options) string {
+	return fmt.Sprintf("%.*f %s²", o.precision, s.Area(), unit)
+}
+
+func oldDescribe(s Shape) string {
+	return fmt.Sprintf("%.1f %s²", s.Area(), legacyUnit)
+}
+
+func main() {
+	total = 1
+	o := options{precision: 1, verbose: true}
+	fmt.Println(describe(newSquare(2), o))
+}
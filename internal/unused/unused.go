@@ -0,0 +1,363 @@
+// Package unused finds the boat anchors of a whole program: declarations
+// that nothing reachable from main and init ever uses.
+//
+// Functions and methods are live when rapid type analysis (RTA) finds them
+// reachable from the program's roots. Every other package-level declaration
+// is live when the syntax of a live function, of a package variable's
+// initializer or of another live declaration refers to it. Struct fields
+// and package variables additionally track whether live code ever reads
+// them, so that a field which is set in a constructor and then ignored is
+// told apart from one nobody mentions at all. Fields of types that reach an
+// interface at run time are not reported, since reflection may read them.
+package unused
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/bclements/antipatterns/internal/astutil"
+)
+
+// Categories of findings.
+const (
+	// CategoryUnreachable is a function or method that is never called
+	// from main or init.
+	CategoryUnreachable = "unreachable"
+	// CategoryUnreferenced is a type, interface, constant, variable or
+	// field that no live code mentions.
+	CategoryUnreferenced = "unreferenced"
+	// CategoryWriteOnly is a field or variable that live code assigns,
+	// typically while constructing its struct, but never reads.
+	CategoryWriteOnly = "write-only"
+)
+
+// A Finding is one unused declaration.
+type Finding struct {
+	Category string
+	Kind     string // func, method, type, interface, const, var or field
+	Name     string // e.g. "(*XMLExporter).Export" or "UserManager.xmlExporter"
+	Package  string
+	Pos      token.Position
+}
+
+func (f Finding) String() string {
+	switch f.Category {
+	case CategoryUnreachable:
+		return fmt.Sprintf("%s %s is unreachable from main and init", f.Kind, f.Name)
+	case CategoryWriteOnly:
+		return fmt.Sprintf("%s %s is assigned but never read", f.Kind, f.Name)
+	}
+	return fmt.Sprintf("%s %s is never referenced by reachable code", f.Kind, f.Name)
+}
+
+// Find reports the unused declarations in those packages of the program
+// rooted at the main packages among initial whose import path matches
+// filter. The packages must have been loaded with packages.LoadAllSyntax.
+func Find(initial []*packages.Package, filter *regexp.Regexp) ([]Finding, error) {
+	prog, ssaPkgs := ssautil.AllPackages(initial, ssa.InstantiateGenerics)
+	prog.Build()
+
+	var roots []*ssa.Function
+	for _, p := range ssaPkgs {
+		if p == nil || p.Pkg.Name() != "main" {
+			continue
+		}
+		for _, name := range []string{"main", "init"} {
+			if fn := p.Func(name); fn != nil {
+				roots = append(roots, fn)
+			}
+		}
+	}
+	if len(roots) == 0 {
+		return nil, errors.New("no main packages: reachability needs a program to start from")
+	}
+	res := rta.Analyze(roots, false)
+
+	byTypes := make(map[*types.Package]*packages.Package)
+	packages.Visit(initial, nil, func(p *packages.Package) { byTypes[p.Types] = p })
+
+	u := &usage{
+		reachable: make(map[*types.Func]bool),
+		dynamic:   make(map[types.Object]bool),
+		read:      make(map[types.Object]bool),
+		written:   make(map[types.Object]bool),
+		decls:     make(map[types.Object]decl),
+	}
+	var targets []*packages.Package
+	for _, p := range byTypes {
+		if !filter.MatchString(p.PkgPath) || len(p.Syntax) == 0 {
+			continue
+		}
+		targets = append(targets, p)
+		u.collectDecls(p)
+	}
+
+	for _, t := range res.RuntimeTypes.Keys() {
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if n, ok := t.(*types.Named); ok {
+			u.dynamic[n.Origin().Obj()] = true
+		}
+	}
+	for fn := range res.Reachable {
+		if o := fn.Origin(); o != nil {
+			fn = o
+		}
+		if obj, ok := fn.Object().(*types.Func); ok {
+			u.reachable[obj] = true
+		}
+		if fd, ok := fn.Syntax().(*ast.FuncDecl); ok && fn.Pkg != nil {
+			if p := byTypes[fn.Pkg.Pkg]; p != nil {
+				u.walk(p.TypesInfo, fd)
+			}
+		}
+	}
+	// Package variable initializers run whenever their package is
+	// initialized, whether or not the variable is ever read.
+	for _, p := range targets {
+		for _, f := range p.Syntax {
+			for _, d := range f.Decls {
+				if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+					u.walk(p.TypesInfo, gd)
+				}
+			}
+		}
+	}
+	u.drain()
+
+	var findings []Finding
+	for _, p := range targets {
+		findings = append(findings, u.report(p)...)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i].Pos, findings[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return findings, nil
+}
+
+// A decl is the syntax declaring a package-level type or constant, walked
+// once the object becomes live.
+type decl struct {
+	info *types.Info
+	node ast.Node
+}
+
+type usage struct {
+	reachable map[*types.Func]bool
+	dynamic   map[types.Object]bool // named types converted to interfaces
+	read      map[types.Object]bool
+	written   map[types.Object]bool
+	decls     map[types.Object]decl
+	queue     []decl
+}
+
+func (u *usage) collectDecls(p *packages.Package) {
+	for _, f := range p.Syntax {
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					u.decls[p.TypesInfo.Defs[spec.Name]] = decl{p.TypesInfo, spec}
+				case *ast.ValueSpec:
+					if gd.Tok == token.CONST {
+						for _, name := range spec.Names {
+							u.decls[p.TypesInfo.Defs[name]] = decl{p.TypesInfo, spec}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// use marks obj as read, queueing its declaration the first time.
+func (u *usage) use(obj types.Object) {
+	if u.read[obj] {
+		return
+	}
+	u.read[obj] = true
+	if d, ok := u.decls[obj]; ok {
+		u.queue = append(u.queue, d)
+	}
+}
+
+func (u *usage) drain() {
+	for len(u.queue) > 0 {
+		d := u.queue[len(u.queue)-1]
+		u.queue = u.queue[:len(u.queue)-1]
+		u.walk(d.info, d.node)
+	}
+}
+
+// walk records every object that live syntax n refers to, telling writes
+// to fields and package variables apart from reads.
+func (u *usage) walk(info *types.Info, n ast.Node) {
+	writes := make(map[*ast.Ident]bool)
+	target := func(lhs ast.Expr) {
+		switch e := ast.Unparen(lhs).(type) {
+		case *ast.Ident:
+			writes[e] = true
+		case *ast.SelectorExpr:
+			writes[e.Sel] = true
+		}
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				target(lhs)
+			}
+		case *ast.IncDecStmt:
+			target(n.X)
+		case *ast.CompositeLit:
+			st, ok := info.TypeOf(n).Underlying().(*types.Struct)
+			if !ok {
+				break
+			}
+			for i, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if id, ok := kv.Key.(*ast.Ident); ok {
+						writes[id] = true
+					}
+				} else if i < st.NumFields() {
+					u.written[st.Field(i)] = true
+				}
+			}
+		}
+		return true
+	})
+
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			obj := info.Uses[n]
+			if obj == nil {
+				break
+			}
+			if writes[n] {
+				u.written[obj] = true
+			} else {
+				u.use(obj)
+			}
+		case *ast.SelectorExpr:
+			// Fields promoted through embedding read the embedded fields.
+			if sel := info.Selections[n]; sel != nil {
+				t := sel.Recv()
+				for _, i := range sel.Index()[:len(sel.Index())-1] {
+					if p, ok := t.Underlying().(*types.Pointer); ok {
+						t = p.Elem()
+					}
+					st, ok := t.Underlying().(*types.Struct)
+					if !ok {
+						break
+					}
+					u.use(st.Field(i))
+					t = st.Field(i).Type()
+				}
+			}
+		}
+		return true
+	})
+}
+
+// report lists the unused declarations of p.
+func (u *usage) report(p *packages.Package) []Finding {
+	var findings []Finding
+	add := func(category, kind, name string, pos token.Pos) {
+		findings = append(findings, Finding{
+			Category: category,
+			Kind:     kind,
+			Name:     name,
+			Package:  p.PkgPath,
+			Pos:      p.Fset.Position(pos),
+		})
+	}
+	value := func(kind string, id *ast.Ident, name string) {
+		obj := p.TypesInfo.Defs[id]
+		switch {
+		case obj == nil || id.Name == "_" || u.read[obj]:
+		case u.written[obj]:
+			add(CategoryWriteOnly, kind, name, id.Pos())
+		default:
+			add(CategoryUnreferenced, kind, name, id.Pos())
+		}
+	}
+
+	for _, f := range p.Syntax {
+		for _, d := range f.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				obj, _ := p.TypesInfo.Defs[d.Name].(*types.Func)
+				if obj == nil || d.Name.Name == "_" || u.reachable[obj] {
+					continue
+				}
+				kind := "func"
+				if d.Recv != nil {
+					kind = "method"
+				}
+				add(CategoryUnreachable, kind, astutil.FuncName(d), d.Name.Pos())
+
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						obj := p.TypesInfo.Defs[spec.Name]
+						if !u.read[obj] {
+							kind := "type"
+							if _, ok := obj.Type().Underlying().(*types.Interface); ok {
+								kind = "interface"
+							}
+							add(CategoryUnreferenced, kind, spec.Name.Name, spec.Name.Pos())
+							continue
+						}
+						// Values stored in interfaces may have their fields
+						// read by reflection, as fmt's %v does.
+						st, ok := spec.Type.(*ast.StructType)
+						if !ok || u.dynamic[obj] {
+							continue
+						}
+						for _, field := range st.Fields.List {
+							// Embedded fields may be needed for their
+							// promoted methods, and tagged fields are
+							// typically read by reflection.
+							if field.Names == nil || field.Tag != nil {
+								continue
+							}
+							for _, name := range field.Names {
+								value("field", name, spec.Name.Name+"."+name.Name)
+							}
+						}
+					case *ast.ValueSpec:
+						kind := "var"
+						if d.Tok == token.CONST {
+							kind = "const"
+						}
+						for _, name := range spec.Names {
+							value(kind, name, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
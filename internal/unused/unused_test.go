@@ -0,0 +1,47 @@
+package unused_test
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/bclements/antipatterns/internal/driver"
+	"github.com/bclements/antipatterns/internal/unused"
+)
+
+func TestFind(t *testing.T) {
+	pkgs, err := driver.Load("testdata/app", false, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := unused.Find(pkgs, regexp.MustCompile(`/testdata/app$`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"field options.verbose is assigned but never read",
+		"method options.reset is unreachable from main and init",
+		"type Circle is never referenced by reachable code",
+		"interface Exporter is never referenced by reachable code",
+		"const legacyUnit is never referenced by reachable code",
+		"var total is assigned but never read",
+		"func oldDescribe is unreachable from main and init",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestFindNeedsMain(t *testing.T) {
+	pkgs, err := driver.Load("../..", false, "./internal/unused")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unused.Find(pkgs, regexp.MustCompile(``)); err == nil {
+		t.Error("Find without a main package succeeded")
+	}
+}